@@ -6,8 +6,12 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/hdbrzgr/docs-mcp/services"
 	"github.com/hdbrzgr/docs-mcp/tools"
@@ -155,6 +159,9 @@ func main() {
 		}
 	}
 
+	// Hooks let the resource handlers extend the resource list
+	hooks := &server.Hooks{}
+
 	mcpServer := server.NewMCPServer(
 		"Google Docs MCP",
 		"1.0.0",
@@ -162,16 +169,33 @@ func main() {
 		server.WithPromptCapabilities(true),
 		server.WithResourceCapabilities(true, true),
 		server.WithRecovery(),
+		server.WithHooks(hooks),
 	)
 
 	// Register available Google Docs tools
 	tools.RegisterDocumentTools(mcpServer)
+	tools.RegisterFolderTools(mcpServer)
+	tools.RegisterTemplateTools(mcpServer)
+	tools.RegisterMergeTools(mcpServer)
 	tools.RegisterContentTools(mcpServer)
 	tools.RegisterFormattingTools(mcpServer)
 	tools.RegisterStructureTools(mcpServer)
+	tools.RegisterTableTools(mcpServer)
+	tools.RegisterBatchTools(mcpServer)
+	tools.RegisterNavigationTools(mcpServer)
+	tools.RegisterSearchTools(mcpServer)
 	tools.RegisterCollaborationTools(mcpServer)
 	tools.RegisterRevisionTools(mcpServer)
 
+	// Register Google Docs resources
+	tools.RegisterDocumentResources(mcpServer, hooks)
+
+	// Register document workflow prompts
+	tools.RegisterPrompts(mcpServer)
+
+	// Watch subscribed documents for changes
+	watcher := tools.NewResourceWatcher(mcpServer)
+
 	if *httpPort != "" {
 		fmt.Println()
 		fmt.Println("🚀 Starting Google Docs MCP Server in HTTP mode...")
@@ -197,8 +221,29 @@ func main() {
 		fmt.Println()
 		fmt.Println("🔄 Server starting...")
 
-		httpServer := server.NewStreamableHTTPServer(mcpServer, server.WithEndpointPath("/mcp"))
-		if err := httpServer.Start(fmt.Sprintf(":%s", *httpPort)); err != nil && !isContextCanceled(err) {
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+		defer stop()
+
+		go watcher.Run(ctx)
+
+		// The watcher sees subscription requests before the MCP server does
+		mux := http.NewServeMux()
+		httpServer := server.NewStreamableHTTPServer(mcpServer,
+			server.WithEndpointPath("/mcp"),
+			server.WithStreamableHTTPServer(&http.Server{Handler: mux}),
+		)
+		mux.Handle("/mcp", watcher.HTTPHandler(httpServer))
+
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := httpServer.Shutdown(shutdownCtx); err != nil {
+				log.Printf("⚠️  Server shutdown error: %v", err)
+			}
+		}()
+
+		if err := httpServer.Start(fmt.Sprintf(":%s", *httpPort)); err != nil && !errors.Is(err, http.ErrServerClosed) && !isContextCanceled(err) {
 			log.Fatalf("❌ Server error: %v", err)
 		}
 	} else {
@@ -212,7 +257,7 @@ func main() {
 		fmt.Println("- If this was your first run, check that token.json was created successfully")
 		fmt.Println()
 		services.GoogleDriveClient()
-		if err := server.ServeStdio(mcpServer); err != nil && !isContextCanceled(err) {
+		if err := watcher.ServeStdio(mcpServer); err != nil && !isContextCanceled(err) {
 			log.Fatalf("❌ Server error: %v", err)
 		}
 	}
@@ -128,6 +128,40 @@ var GoogleDriveClient = sync.OnceValue[*drive.Service](func() *drive.Service {
 	return service
 })
 
+// GoogleHTTPClient provides a singleton authenticated HTTP client for requests
+// the API client libraries do not cover, such as downloading revision export links
+var GoogleHTTPClient = sync.OnceValue[*http.Client](func() *http.Client {
+	config := loadGoogleCredentials()
+
+	ctx := context.Background()
+
+	if config.UseServiceAccount {
+		credentialsData, err := ioutil.ReadFile(config.CredentialsPath)
+		if err != nil {
+			log.Fatalf("Failed to read service account credentials: %v", err)
+		}
+
+		creds, err := google.CredentialsFromJSON(ctx, credentialsData, docs.DocumentsScope, drive.DriveScope)
+		if err != nil {
+			log.Fatalf("Failed to create credentials from JSON: %v", err)
+		}
+
+		return oauth2.NewClient(ctx, creds.TokenSource)
+	}
+
+	clientSecretsData, err := ioutil.ReadFile(config.ClientSecretsPath)
+	if err != nil {
+		log.Fatalf("Failed to read client secrets: %v", err)
+	}
+
+	oauthConfig, err := google.ConfigFromJSON(clientSecretsData, docs.DocumentsScope, drive.DriveScope)
+	if err != nil {
+		log.Fatalf("Failed to create OAuth config: %v", err)
+	}
+
+	return getHTTPClient(ctx, oauthConfig)
+})
+
 // loadGoogleCredentials loads Google API credentials from environment variables
 func loadGoogleCredentials() AuthConfig {
 	credentialsPath := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
@@ -0,0 +1,72 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hdbrzgr/docs-mcp/services"
+	"github.com/hdbrzgr/docs-mcp/util"
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/api/docs/v1"
+)
+
+// withAnchor adds the optional anchor parameter shared by all range-taking tools
+func withAnchor() mcp.ToolOption {
+	return mcp.WithObject("anchor",
+		mcp.Description("Address the range by content instead of start_index/end_index. Use one of: {\"match\": \"exact text\", \"occurrence\": 2}, {\"heading\": \"Risks\"} for the whole section under a heading, or {\"paragraph\": 5} for the 5th non-empty paragraph"),
+		mcp.Properties(map[string]any{
+			"match": map[string]any{
+				"type":        "string",
+				"description": "Exact text to find (case-sensitive)",
+			},
+			"occurrence": map[string]any{
+				"type":        "number",
+				"description": "Which occurrence of the matched text to use (1-based, default: 1)",
+			},
+			"heading": map[string]any{
+				"type":        "string",
+				"description": "Heading text or heading ID; resolves to the heading and everything up to the next heading of the same or higher level",
+			},
+			"paragraph": map[string]any{
+				"type":        "number",
+				"description": "Paragraph number in the body (1-based, empty paragraphs are not counted)",
+			},
+		}),
+	)
+}
+
+// resolveRange returns the range a tool should operate on. When an anchor is
+// given it is resolved against a fresh copy of the document; otherwise the
+// explicit indices are validated. A non-nil result is an error to return.
+func resolveRange(ctx context.Context, documentID string, startIndex, endIndex int64, anchor *util.Anchor, writeControl *docs.WriteControl) (int64, int64, *mcp.CallToolResult) {
+	if anchor.IsZero() {
+		if startIndex <= 0 && endIndex <= 0 {
+			return 0, 0, mcp.NewToolResultError("Error: Provide either start_index and end_index, or an anchor.")
+		}
+		if startIndex >= endIndex {
+			return 0, 0, mcp.NewToolResultError("Error: Start index must be less than end index.")
+		}
+		return startIndex, endIndex, nil
+	}
+
+	docsService := services.GoogleDocsClient()
+
+	doc, err := docsService.Documents.Get(documentID).Context(ctx).Do()
+	if err != nil {
+		return 0, 0, util.HandleGoogleAPIError("get document to resolve anchor", err)
+	}
+
+	if errResult := pinWriteControl(writeControl, doc); errResult != nil {
+		return 0, 0, errResult
+	}
+
+	start, end, err := util.ResolveAnchor(doc, anchor)
+	if err != nil {
+		return 0, 0, mcp.NewToolResultError(fmt.Sprintf("Error: Could not resolve anchor: %v", err))
+	}
+	if start >= end {
+		return 0, 0, mcp.NewToolResultError("Error: Anchor resolved to an empty range.")
+	}
+
+	return start, end, nil
+}
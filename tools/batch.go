@@ -0,0 +1,602 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hdbrzgr/docs-mcp/services"
+	"github.com/hdbrzgr/docs-mcp/util"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"google.golang.org/api/docs/v1"
+)
+
+// Input types for batch tools
+type BatchEditInput struct {
+	DocumentID       string           `json:"document_id" validate:"required"`
+	Operations       []BatchOperation `json:"operations" validate:"required"`
+	RevisionID       string           `json:"revision_id,omitempty"`
+	TargetRevisionID string           `json:"target_revision_id,omitempty"`
+}
+
+// BatchOperation is one step of a batch edit. Indices and anchors refer to the
+// document as it was before the batch; Target refers to content inserted by an
+// earlier operation of the same batch.
+type BatchOperation struct {
+	Type       string       `json:"type" validate:"required"`
+	Index      int64        `json:"index,omitempty"`
+	StartIndex int64        `json:"start_index,omitempty"`
+	EndIndex   int64        `json:"end_index,omitempty"`
+	Anchor     *util.Anchor `json:"anchor,omitempty"`
+	Target     int          `json:"target,omitempty"` // 1-based number of an earlier operation whose inserted content to address
+
+	Text    string   `json:"text,omitempty"`
+	Items   []string `json:"items,omitempty"`
+	Ordered bool     `json:"ordered,omitempty"`
+
+	Bold            *bool  `json:"bold,omitempty"`
+	Italic          *bool  `json:"italic,omitempty"`
+	Underline       *bool  `json:"underline,omitempty"`
+	FontSize        *int64 `json:"font_size,omitempty"`
+	FontFamily      string `json:"font_family,omitempty"`
+	Color           string `json:"color,omitempty"`
+	BackgroundColor string `json:"background_color,omitempty"`
+
+	StyleType string  `json:"style_type,omitempty"`
+	Alignment string  `json:"alignment,omitempty"`
+	Spacing   float64 `json:"spacing,omitempty"`
+
+	Rows     int64  `json:"rows,omitempty"`
+	Columns  int64  `json:"columns,omitempty"`
+	ImageURL string `json:"image_url,omitempty"`
+	Width    int64  `json:"width,omitempty"`
+	Height   int64  `json:"height,omitempty"`
+}
+
+// Output types for batch tools
+type BatchEditOutput struct {
+	DocumentID string               `json:"document_id"`
+	RevisionID string               `json:"revision_id,omitempty" jsonschema_description:"Revision of the document after the edit. Pass it as revision_id to chain further edits"`
+	Operations int                  `json:"operations"`
+	Requests   int                  `json:"requests" jsonschema_description:"Number of Docs API requests the operations were compiled to"`
+	Summary    []string             `json:"summary" jsonschema_description:"What each operation did"`
+	Inserted   []BatchInsertedRange `json:"inserted,omitempty" jsonschema_description:"Content added by each inserting operation, in the coordinates of the document after the whole batch"`
+}
+
+type BatchInsertedRange struct {
+	Operation  int   `json:"operation" jsonschema_description:"1-based number of the operation"`
+	StartIndex int64 `json:"start_index"`
+	EndIndex   int64 `json:"end_index"`
+}
+
+// insertedRange is the content added by a batch operation, in the coordinates
+// of the rebaser step just after that operation
+type insertedRange struct {
+	startIndex int64
+	endIndex   int64
+	step       int
+}
+
+// batchCompiler turns batch operations into Docs API requests, rebasing the
+// indices of every operation onto the edits made by the operations before it
+type batchCompiler struct {
+	doc      *docs.Document
+	rebaser  util.IndexRebaser
+	inserted map[int]insertedRange
+	requests []*docs.Request
+	summary  []string
+}
+
+func RegisterBatchTools(s *server.MCPServer) {
+	// Batch edit tool
+	batchEditTool := mcp.NewTool("batch_edit",
+		mcp.WithDescription("Apply an ordered list of edits to a Google Docs document in a single atomic update. All indices and anchors refer to the document as it is before the batch; indices of later operations are shifted automatically to account for earlier inserts and deletes. Either every operation is applied or none is."),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
+		mcp.WithArray("operations", mcp.Required(),
+			mcp.Description("Operations to apply in order. Each has a 'type' of: 'insert_text' (index, text), 'delete_text' (range), 'replace_text' (range, text), 'format_text' (range, bold, italic, underline, font_size, font_family, color, background_color), 'set_paragraph_style' (range, style_type, alignment, spacing), 'create_bullets' (range, ordered), 'insert_list' (index, items, ordered), 'insert_table' (index, rows, columns), 'insert_image' (index, image_url, width, height) or 'insert_page_break' (index). A range is start_index/end_index, an anchor, or target: the number of an earlier operation whose inserted content to address."),
+			mcp.Items(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"type":             map[string]any{"type": "string", "description": "Operation type"},
+					"index":            map[string]any{"type": "number", "description": "Insertion position"},
+					"start_index":      map[string]any{"type": "number", "description": "Start of the range"},
+					"end_index":        map[string]any{"type": "number", "description": "End of the range"},
+					"anchor":           map[string]any{"type": "object", "description": "Content anchor for the range: {\"match\": \"text\", \"occurrence\": 1}, {\"heading\": \"text\"} or {\"paragraph\": 3}"},
+					"target":           map[string]any{"type": "number", "description": "1-based number of an earlier operation; the range is the content it inserted"},
+					"text":             map[string]any{"type": "string", "description": "Text to insert or replace with"},
+					"items":            map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "List items for insert_list"},
+					"ordered":          map[string]any{"type": "boolean", "description": "Numbered list instead of bullets"},
+					"bold":             map[string]any{"type": "boolean"},
+					"italic":           map[string]any{"type": "boolean"},
+					"underline":        map[string]any{"type": "boolean"},
+					"font_size":        map[string]any{"type": "number", "description": "Font size in points"},
+					"font_family":      map[string]any{"type": "string"},
+					"color":            map[string]any{"type": "string", "description": "Hex text color, e.g. '#FF0000'"},
+					"background_color": map[string]any{"type": "string", "description": "Hex highlight color, e.g. '#FFFF00'"},
+					"style_type":       map[string]any{"type": "string", "description": "NORMAL_TEXT, TITLE, SUBTITLE or HEADING_1 to HEADING_6"},
+					"alignment":        map[string]any{"type": "string", "description": "START, CENTER, END or JUSTIFY"},
+					"spacing":          map[string]any{"type": "number", "description": "Line spacing, e.g. 1.0, 1.5, 2.0"},
+					"rows":             map[string]any{"type": "number"},
+					"columns":          map[string]any{"type": "number"},
+					"image_url":        map[string]any{"type": "string"},
+					"width":            map[string]any{"type": "number", "description": "Image width in points"},
+					"height":           map[string]any{"type": "number", "description": "Image height in points"},
+				},
+				"required": []string{"type"},
+			}),
+		),
+		withWriteControl(),
+		mcp.WithOutputSchema[BatchEditOutput](),
+	)
+	s.AddTool(batchEditTool, mcp.NewTypedToolHandler(batchEditHandler))
+}
+
+func batchEditHandler(ctx context.Context, request mcp.CallToolRequest, input BatchEditInput) (*mcp.CallToolResult, error) {
+	docsService := services.GoogleDocsClient()
+
+	writeControl, errResult := newWriteControl(input.RevisionID, input.TargetRevisionID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	if len(input.Operations) == 0 {
+		return mcp.NewToolResultError("Error: At least one operation is required."), nil
+	}
+
+	// Anchors are resolved against the current revision, which moves a target
+	// revision forward. Indices meant for the older target revision would then
+	// address the wrong text, so the two cannot be mixed.
+	if writeControl != nil && writeControl.TargetRevisionId != "" {
+		var anchored, indexed []string
+		for i, op := range input.Operations {
+			if !op.Anchor.IsZero() {
+				anchored = append(anchored, strconv.Itoa(i+1))
+			}
+			if usesIndices(op) {
+				indexed = append(indexed, strconv.Itoa(i+1))
+			}
+		}
+		if len(anchored) > 0 && len(indexed) > 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("Error: With target_revision_id, operations cannot mix anchors (operations %s) with indices (operations %s), since anchors are resolved against the current revision. Use anchors throughout, or indices throughout. No changes were made.",
+				strings.Join(anchored, ", "), strings.Join(indexed, ", "))), nil
+		}
+	}
+
+	c := &batchCompiler{inserted: make(map[int]insertedRange)}
+
+	// Anchors are resolved against the document as it is before the batch
+	for _, op := range input.Operations {
+		if !op.Anchor.IsZero() {
+			doc, err := docsService.Documents.Get(input.DocumentID).Context(ctx).Do()
+			if err != nil {
+				return util.HandleGoogleAPIError("get document to resolve anchors", err), nil
+			}
+			if errResult := pinWriteControl(writeControl, doc); errResult != nil {
+				return errResult, nil
+			}
+			c.doc = doc
+			break
+		}
+	}
+
+	for i, op := range input.Operations {
+		if err := c.compile(i+1, op); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Error: Operation %d (%s): %v. No changes were made.", i+1, op.Type, err)), nil
+		}
+	}
+
+	if len(c.requests) == 0 {
+		output := BatchEditOutput{
+			DocumentID: input.DocumentID,
+			Operations: len(input.Operations),
+			Summary:    []string{},
+		}
+		return mcp.NewToolResultStructured(output, "No changes specified."), nil
+	}
+
+	batchUpdateRequest := &docs.BatchUpdateDocumentRequest{
+		Requests:     c.requests,
+		WriteControl: writeControl,
+	}
+
+	response, err := docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
+	if err != nil {
+		return handleWriteError(ctx, "batch edit", input.DocumentID, writeControl, err), nil
+	}
+
+	result := fmt.Sprintf("Batch edit applied successfully!\n\nDocument ID: %s\nOperations: %d\nRequests: %d\n",
+		input.DocumentID, len(input.Operations), len(c.requests))
+
+	if response.WriteControl != nil && response.WriteControl.RequiredRevisionId != "" {
+		result += fmt.Sprintf("Revision ID: %s\n", response.WriteControl.RequiredRevisionId)
+	}
+
+	result += "\n" + strings.Join(c.summary, "\n")
+
+	output := BatchEditOutput{
+		DocumentID: input.DocumentID,
+		RevisionID: revisionAfter(response),
+		Operations: len(input.Operations),
+		Requests:   len(c.requests),
+		Summary:    c.summary,
+		Inserted:   c.insertedRanges(len(input.Operations)),
+	}
+
+	return mcp.NewToolResultStructured(output, result), nil
+}
+
+// compile appends the requests for one operation and records its effect on indices
+func (c *batchCompiler) compile(number int, op BatchOperation) error {
+	switch op.Type {
+	case "insert_text":
+		if op.Text == "" {
+			return fmt.Errorf("text is required")
+		}
+		index, err := c.index(op)
+		if err != nil {
+			return err
+		}
+		c.requests = append(c.requests, insertTextRequest(index, op.Text))
+		c.recordInsert(number, index, util.UTF16Length(op.Text))
+		c.summarize(number, "insert_text at %d (%d characters)", index, util.UTF16Length(op.Text))
+
+	case "delete_text":
+		start, end, err := c.textRange(number, op)
+		if err != nil {
+			return err
+		}
+		c.requests = append(c.requests, deleteRangeRequest(start, end))
+		c.rebaser.Record(start, end-start, 0)
+		c.summarize(number, "delete_text %d-%d", start, end)
+
+	case "replace_text":
+		start, end, err := c.textRange(number, op)
+		if err != nil {
+			return err
+		}
+		c.requests = append(c.requests, deleteRangeRequest(start, end))
+		length := util.UTF16Length(op.Text)
+		if length > 0 {
+			c.requests = append(c.requests, insertTextRequest(start, op.Text))
+		}
+		c.rebaser.Record(start, end-start, length)
+		c.inserted[number] = insertedRange{startIndex: start, endIndex: start + length, step: c.rebaser.Step()}
+		c.summarize(number, "replace_text %d-%d with %d characters", start, end, length)
+
+	case "format_text":
+		start, end, err := c.textRange(number, op)
+		if err != nil {
+			return err
+		}
+		style, fields, err := batchTextStyle(op)
+		if err != nil {
+			return err
+		}
+		c.requests = append(c.requests, &docs.Request{
+			UpdateTextStyle: &docs.UpdateTextStyleRequest{
+				Range:     &docs.Range{StartIndex: start, EndIndex: end},
+				TextStyle: style,
+				Fields:    fields,
+			},
+		})
+		c.summarize(number, "format_text %d-%d (%s)", start, end, fields)
+
+	case "set_paragraph_style":
+		start, end, err := c.textRange(number, op)
+		if err != nil {
+			return err
+		}
+		style, fields, err := batchParagraphStyle(op)
+		if err != nil {
+			return err
+		}
+		c.requests = append(c.requests, &docs.Request{
+			UpdateParagraphStyle: &docs.UpdateParagraphStyleRequest{
+				Range:          &docs.Range{StartIndex: start, EndIndex: end},
+				ParagraphStyle: style,
+				Fields:         fields,
+			},
+		})
+		c.summarize(number, "set_paragraph_style %d-%d (%s)", start, end, fields)
+
+	case "create_bullets":
+		start, end, err := c.textRange(number, op)
+		if err != nil {
+			return err
+		}
+		c.requests = append(c.requests, bulletsRequest(start, end, op.Ordered))
+		c.summarize(number, "create_bullets %d-%d", start, end)
+
+	case "insert_list":
+		if len(op.Items) == 0 {
+			return fmt.Errorf("items must contain at least one item")
+		}
+		index, err := c.index(op)
+		if err != nil {
+			return err
+		}
+		text := strings.Join(op.Items, "\n") + "\n"
+		length := util.UTF16Length(text)
+		c.requests = append(c.requests, insertTextRequest(index, text), bulletsRequest(index, index+length, op.Ordered))
+		c.recordInsert(number, index, length)
+		c.summarize(number, "insert_list at %d (%d items)", index, len(op.Items))
+
+	case "insert_table":
+		if op.Rows <= 0 || op.Columns <= 0 {
+			return fmt.Errorf("rows and columns must be greater than 0")
+		}
+		if op.Rows > util.MaxTableRows || op.Columns > util.MaxTableColumns {
+			return fmt.Errorf("maximum %d rows and %d columns allowed", util.MaxTableRows, util.MaxTableColumns)
+		}
+		index, err := c.index(op)
+		if err != nil {
+			return err
+		}
+		c.requests = append(c.requests, &docs.Request{
+			InsertTable: &docs.InsertTableRequest{
+				Location: &docs.Location{Index: index},
+				Rows:     op.Rows,
+				Columns:  op.Columns,
+			},
+		})
+		c.recordInsert(number, index, util.InsertedTableLength(op.Rows, op.Columns))
+		c.summarize(number, "insert_table at %d (%dx%d)", index, op.Rows, op.Columns)
+
+	case "insert_image":
+		if op.ImageURL == "" {
+			return fmt.Errorf("image_url is required")
+		}
+		index, err := c.index(op)
+		if err != nil {
+			return err
+		}
+		var size *docs.Size
+		if op.Width > 0 || op.Height > 0 {
+			size = &docs.Size{}
+			if op.Width > 0 {
+				size.Width = &docs.Dimension{Magnitude: float64(op.Width), Unit: "PT"}
+			}
+			if op.Height > 0 {
+				size.Height = &docs.Dimension{Magnitude: float64(op.Height), Unit: "PT"}
+			}
+		}
+		c.requests = append(c.requests, &docs.Request{
+			InsertInlineImage: &docs.InsertInlineImageRequest{
+				Location:   &docs.Location{Index: index},
+				Uri:        op.ImageURL,
+				ObjectSize: size,
+			},
+		})
+		c.recordInsert(number, index, 1)
+		c.summarize(number, "insert_image at %d", index)
+
+	case "insert_page_break":
+		index, err := c.index(op)
+		if err != nil {
+			return err
+		}
+		c.requests = append(c.requests, &docs.Request{
+			InsertPageBreak: &docs.InsertPageBreakRequest{
+				Location: &docs.Location{Index: index},
+			},
+		})
+		// A page break is inserted together with a newline
+		c.recordInsert(number, index, 2)
+		c.summarize(number, "insert_page_break at %d", index)
+
+	default:
+		return fmt.Errorf("unknown operation type '%s'", op.Type)
+	}
+
+	return nil
+}
+
+// index returns the rebased insertion index of an operation
+func (c *batchCompiler) index(op BatchOperation) (int64, error) {
+	if op.Index < 1 {
+		return 0, fmt.Errorf("index is required and must be at least 1")
+	}
+	return c.rebaser.Start(op.Index, 0), nil
+}
+
+// usesIndices reports whether an operation gives its position as document
+// indices rather than through an anchor or an earlier operation
+func usesIndices(op BatchOperation) bool {
+	if op.Index > 0 {
+		return true
+	}
+	if op.Target != 0 || !op.Anchor.IsZero() {
+		return false
+	}
+	return op.StartIndex > 0 || op.EndIndex > 0
+}
+
+// textRange returns the rebased range an operation addresses, given either by
+// a target operation, an anchor or explicit indices
+func (c *batchCompiler) textRange(number int, op BatchOperation) (int64, int64, error) {
+	var start, end int64
+
+	switch {
+	case op.Target != 0:
+		if op.Target < 1 || op.Target >= number {
+			return 0, 0, fmt.Errorf("target must be the number of an earlier operation")
+		}
+		r, ok := c.inserted[op.Target]
+		if !ok {
+			return 0, 0, fmt.Errorf("operation %d did not insert any content to target", op.Target)
+		}
+		start, end = c.rebaser.Start(r.startIndex, r.step), c.rebaser.End(r.endIndex, r.step)
+
+	case !op.Anchor.IsZero():
+		anchorStart, anchorEnd, err := util.ResolveAnchor(c.doc, op.Anchor)
+		if err != nil {
+			return 0, 0, fmt.Errorf("could not resolve anchor: %v", err)
+		}
+		start, end = c.rebaser.Start(anchorStart, 0), c.rebaser.End(anchorEnd, 0)
+
+	default:
+		if op.StartIndex <= 0 && op.EndIndex <= 0 {
+			return 0, 0, fmt.Errorf("provide start_index and end_index, an anchor, or a target")
+		}
+		if op.StartIndex >= op.EndIndex {
+			return 0, 0, fmt.Errorf("start index must be less than end index")
+		}
+		start, end = c.rebaser.Start(op.StartIndex, 0), c.rebaser.End(op.EndIndex, 0)
+	}
+
+	if start >= end {
+		return 0, 0, fmt.Errorf("the range was removed by an earlier operation")
+	}
+	return start, end, nil
+}
+
+// recordInsert records content added at index and remembers it for later targets
+func (c *batchCompiler) recordInsert(number int, index, length int64) {
+	c.rebaser.Record(index, 0, length)
+	c.inserted[number] = insertedRange{startIndex: index, endIndex: index + length, step: c.rebaser.Step()}
+}
+
+// insertedRanges returns the content added by each operation, mapped to the
+// document after the whole batch
+func (c *batchCompiler) insertedRanges(operations int) []BatchInsertedRange {
+	var ranges []BatchInsertedRange
+	for number := 1; number <= operations; number++ {
+		inserted, ok := c.inserted[number]
+		if !ok {
+			continue
+		}
+		ranges = append(ranges, BatchInsertedRange{
+			Operation:  number,
+			StartIndex: c.rebaser.Start(inserted.startIndex, inserted.step),
+			EndIndex:   c.rebaser.End(inserted.endIndex, inserted.step),
+		})
+	}
+	return ranges
+}
+
+func (c *batchCompiler) summarize(number int, format string, args ...any) {
+	c.summary = append(c.summary, fmt.Sprintf("%d. ", number)+fmt.Sprintf(format, args...))
+}
+
+func insertTextRequest(index int64, text string) *docs.Request {
+	return &docs.Request{
+		InsertText: &docs.InsertTextRequest{
+			Location: &docs.Location{Index: index},
+			Text:     text,
+		},
+	}
+}
+
+func deleteRangeRequest(start, end int64) *docs.Request {
+	return &docs.Request{
+		DeleteContentRange: &docs.DeleteContentRangeRequest{
+			Range: &docs.Range{StartIndex: start, EndIndex: end},
+		},
+	}
+}
+
+func bulletsRequest(start, end int64, ordered bool) *docs.Request {
+	preset := "BULLET_DISC_CIRCLE_SQUARE"
+	if ordered {
+		preset = "NUMBERED_DECIMAL_ALPHA_ROMAN"
+	}
+	return &docs.Request{
+		CreateParagraphBullets: &docs.CreateParagraphBulletsRequest{
+			Range:        &docs.Range{StartIndex: start, EndIndex: end},
+			BulletPreset: preset,
+		},
+	}
+}
+
+// batchTextStyle builds a text style and its field mask from the style options
+// of an operation, so only the given attributes are changed
+func batchTextStyle(op BatchOperation) (*docs.TextStyle, string, error) {
+	style := &docs.TextStyle{}
+	var fields []string
+
+	if op.Bold != nil {
+		style.Bold = *op.Bold
+		style.ForceSendFields = append(style.ForceSendFields, "Bold")
+		fields = append(fields, "bold")
+	}
+	if op.Italic != nil {
+		style.Italic = *op.Italic
+		style.ForceSendFields = append(style.ForceSendFields, "Italic")
+		fields = append(fields, "italic")
+	}
+	if op.Underline != nil {
+		style.Underline = *op.Underline
+		style.ForceSendFields = append(style.ForceSendFields, "Underline")
+		fields = append(fields, "underline")
+	}
+	if op.FontSize != nil {
+		style.FontSize = &docs.Dimension{Magnitude: float64(*op.FontSize), Unit: "PT"}
+		fields = append(fields, "fontSize")
+	}
+	if op.FontFamily != "" {
+		style.WeightedFontFamily = &docs.WeightedFontFamily{FontFamily: op.FontFamily}
+		fields = append(fields, "weightedFontFamily")
+	}
+	if op.Color != "" {
+		color, err := parseHexColor(op.Color)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid color: %v", err)
+		}
+		style.ForegroundColor = &docs.OptionalColor{Color: color}
+		fields = append(fields, "foregroundColor")
+	}
+	if op.BackgroundColor != "" {
+		color, err := parseHexColor(op.BackgroundColor)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid background_color: %v", err)
+		}
+		style.BackgroundColor = &docs.OptionalColor{Color: color}
+		fields = append(fields, "backgroundColor")
+	}
+
+	if len(fields) == 0 {
+		return nil, "", fmt.Errorf("no formatting changes specified")
+	}
+	return style, strings.Join(fields, ","), nil
+}
+
+// batchParagraphStyle builds a paragraph style and its field mask from the
+// paragraph options of an operation
+func batchParagraphStyle(op BatchOperation) (*docs.ParagraphStyle, string, error) {
+	style := &docs.ParagraphStyle{}
+	var fields []string
+
+	if op.StyleType != "" {
+		switch op.StyleType {
+		case "NORMAL_TEXT", "TITLE", "SUBTITLE", "HEADING_1", "HEADING_2", "HEADING_3", "HEADING_4", "HEADING_5", "HEADING_6":
+		default:
+			return nil, "", fmt.Errorf("invalid style_type '%s'", op.StyleType)
+		}
+		style.NamedStyleType = op.StyleType
+		fields = append(fields, "namedStyleType")
+	}
+	if op.Alignment != "" {
+		switch op.Alignment {
+		case "START", "CENTER", "END", "JUSTIFY":
+		default:
+			return nil, "", fmt.Errorf("invalid alignment '%s'", op.Alignment)
+		}
+		style.Alignment = op.Alignment
+		fields = append(fields, "alignment")
+	}
+	if op.Spacing != 0 {
+		if op.Spacing < 0 {
+			return nil, "", fmt.Errorf("spacing must be greater than 0")
+		}
+		// The API expresses line spacing as a percentage of normal
+		style.LineSpacing = op.Spacing * 100
+		fields = append(fields, "lineSpacing")
+	}
+
+	if len(fields) == 0 {
+		return nil, "", fmt.Errorf("no paragraph style changes specified")
+	}
+	return style, strings.Join(fields, ","), nil
+}
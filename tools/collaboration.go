@@ -14,10 +14,11 @@ import (
 
 // Input types for collaboration tools
 type CreateCommentInput struct {
-	DocumentID string `json:"document_id" validate:"required"`
-	StartIndex int64  `json:"start_index" validate:"required"`
-	EndIndex   int64  `json:"end_index" validate:"required"`
-	Comment    string `json:"comment" validate:"required"`
+	DocumentID string       `json:"document_id" validate:"required"`
+	StartIndex int64        `json:"start_index,omitempty"`
+	EndIndex   int64        `json:"end_index,omitempty"`
+	Comment    string       `json:"comment" validate:"required"`
+	Anchor     *util.Anchor `json:"anchor,omitempty"`
 }
 
 type ReplyToCommentInput struct {
@@ -51,11 +52,91 @@ type RemovePermissionInput struct {
 }
 
 type CreateSuggestionInput struct {
-	DocumentID      string `json:"document_id" validate:"required"`
-	StartIndex      int64  `json:"start_index" validate:"required"`
-	EndIndex        int64  `json:"end_index" validate:"required"`
-	SuggestedText   string `json:"suggested_text" validate:"required"`
-	SuggestionType  string `json:"suggestion_type,omitempty"` // REPLACE_TEXT, DELETE_TEXT, INSERT_TEXT
+	DocumentID     string       `json:"document_id" validate:"required"`
+	StartIndex     int64        `json:"start_index,omitempty"`
+	EndIndex       int64        `json:"end_index,omitempty"`
+	SuggestedText  string       `json:"suggested_text" validate:"required"`
+	SuggestionType string       `json:"suggestion_type,omitempty"` // REPLACE_TEXT, DELETE_TEXT, INSERT_TEXT
+	Anchor         *util.Anchor `json:"anchor,omitempty"`
+}
+
+// Output types for collaboration tools
+type CreateCommentOutput struct {
+	DocumentID string `json:"document_id"`
+	CommentID  string `json:"comment_id"`
+	StartIndex int64  `json:"start_index"`
+	EndIndex   int64  `json:"end_index"`
+	Content    string `json:"content"`
+	Author     string `json:"author"`
+}
+
+type CreateSuggestionOutput struct {
+	CreateCommentOutput
+	SuggestionType string `json:"suggestion_type"`
+	SuggestedText  string `json:"suggested_text"`
+}
+
+type ReplyToCommentOutput struct {
+	DocumentID string `json:"document_id"`
+	CommentID  string `json:"comment_id"`
+	ReplyID    string `json:"reply_id"`
+	Author     string `json:"author"`
+}
+
+type CommentOutput struct {
+	ID          string        `json:"id"`
+	Content     string        `json:"content"`
+	Author      string        `json:"author"`
+	CreatedTime string        `json:"created_time"`
+	Resolved    bool          `json:"resolved"`
+	Anchor      string        `json:"anchor,omitempty"`
+	Replies     []ReplyOutput `json:"replies,omitempty"`
+}
+
+type ReplyOutput struct {
+	ID          string `json:"id"`
+	Content     string `json:"content"`
+	Author      string `json:"author"`
+	CreatedTime string `json:"created_time"`
+}
+
+type ListCommentsOutput struct {
+	DocumentID string          `json:"document_id"`
+	Comments   []CommentOutput `json:"comments"`
+	Count      int             `json:"count"`
+}
+
+type ResolveCommentOutput struct {
+	DocumentID string `json:"document_id"`
+	CommentID  string `json:"comment_id"`
+	Resolved   bool   `json:"resolved"`
+}
+
+type PermissionOutput struct {
+	ID     string `json:"id"`
+	Type   string `json:"type"`
+	Role   string `json:"role"`
+	Email  string `json:"email,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Domain string `json:"domain,omitempty"`
+}
+
+type GetPermissionsOutput struct {
+	DocumentID  string             `json:"document_id"`
+	Permissions []PermissionOutput `json:"permissions"`
+	Count       int                `json:"count"`
+}
+
+type UpdatePermissionOutput struct {
+	DocumentID   string `json:"document_id"`
+	PermissionID string `json:"permission_id"`
+	Role         string `json:"role"`
+}
+
+type RemovePermissionOutput struct {
+	DocumentID   string `json:"document_id"`
+	PermissionID string `json:"permission_id"`
+	Removed      bool   `json:"removed"`
 }
 
 func RegisterCollaborationTools(s *server.MCPServer) {
@@ -63,9 +144,11 @@ func RegisterCollaborationTools(s *server.MCPServer) {
 	createCommentTool := mcp.NewTool("create_comment",
 		mcp.WithDescription("Create a comment on a specific range of text in a Google Docs document"),
 		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
-		mcp.WithNumber("start_index", mcp.Required(), mcp.Description("Start position of the text to comment on")),
-		mcp.WithNumber("end_index", mcp.Required(), mcp.Description("End position of the text to comment on")),
+		mcp.WithNumber("start_index", mcp.Description("Start position of the text to comment on")),
+		mcp.WithNumber("end_index", mcp.Description("End position of the text to comment on")),
 		mcp.WithString("comment", mcp.Required(), mcp.Description("The comment text")),
+		withAnchor(),
+		mcp.WithOutputSchema[CreateCommentOutput](),
 	)
 	s.AddTool(createCommentTool, mcp.NewTypedToolHandler(createCommentHandler))
 
@@ -75,6 +158,7 @@ func RegisterCollaborationTools(s *server.MCPServer) {
 		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
 		mcp.WithString("comment_id", mcp.Required(), mcp.Description("The ID of the comment to reply to")),
 		mcp.WithString("reply", mcp.Required(), mcp.Description("The reply text")),
+		mcp.WithOutputSchema[ReplyToCommentOutput](),
 	)
 	s.AddTool(replyToCommentTool, mcp.NewTypedToolHandler(replyToCommentHandler))
 
@@ -82,6 +166,7 @@ func RegisterCollaborationTools(s *server.MCPServer) {
 	listCommentsTool := mcp.NewTool("list_comments",
 		mcp.WithDescription("List all comments in a Google Docs document"),
 		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
+		mcp.WithOutputSchema[ListCommentsOutput](),
 	)
 	s.AddTool(listCommentsTool, mcp.NewTypedToolHandler(listCommentsHandler))
 
@@ -90,6 +175,7 @@ func RegisterCollaborationTools(s *server.MCPServer) {
 		mcp.WithDescription("Resolve (mark as done) a comment in a Google Docs document"),
 		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
 		mcp.WithString("comment_id", mcp.Required(), mcp.Description("The ID of the comment to resolve")),
+		mcp.WithOutputSchema[ResolveCommentOutput](),
 	)
 	s.AddTool(resolveCommentTool, mcp.NewTypedToolHandler(resolveCommentHandler))
 
@@ -97,6 +183,7 @@ func RegisterCollaborationTools(s *server.MCPServer) {
 	getPermissionsTool := mcp.NewTool("get_permissions",
 		mcp.WithDescription("Get all permissions (sharing settings) for a Google Docs document"),
 		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
+		mcp.WithOutputSchema[GetPermissionsOutput](),
 	)
 	s.AddTool(getPermissionsTool, mcp.NewTypedToolHandler(getPermissionsHandler))
 
@@ -106,6 +193,7 @@ func RegisterCollaborationTools(s *server.MCPServer) {
 		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
 		mcp.WithString("permission_id", mcp.Required(), mcp.Description("The ID of the permission to update")),
 		mcp.WithString("role", mcp.Required(), mcp.Description("New role: 'reader', 'writer', or 'commenter'")),
+		mcp.WithOutputSchema[UpdatePermissionOutput](),
 	)
 	s.AddTool(updatePermissionTool, mcp.NewTypedToolHandler(updatePermissionHandler))
 
@@ -114,6 +202,7 @@ func RegisterCollaborationTools(s *server.MCPServer) {
 		mcp.WithDescription("Remove a permission (stop sharing) from a Google Docs document"),
 		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
 		mcp.WithString("permission_id", mcp.Required(), mcp.Description("The ID of the permission to remove")),
+		mcp.WithOutputSchema[RemovePermissionOutput](),
 	)
 	s.AddTool(removePermissionTool, mcp.NewTypedToolHandler(removePermissionHandler))
 
@@ -121,10 +210,12 @@ func RegisterCollaborationTools(s *server.MCPServer) {
 	createSuggestionTool := mcp.NewTool("create_suggestion",
 		mcp.WithDescription("Create a suggestion for text changes in a Google Docs document (suggestion mode)"),
 		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
-		mcp.WithNumber("start_index", mcp.Required(), mcp.Description("Start position of the text to suggest changes for")),
-		mcp.WithNumber("end_index", mcp.Required(), mcp.Description("End position of the text to suggest changes for")),
+		mcp.WithNumber("start_index", mcp.Description("Start position of the text to suggest changes for")),
+		mcp.WithNumber("end_index", mcp.Description("End position of the text to suggest changes for")),
 		mcp.WithString("suggested_text", mcp.Required(), mcp.Description("The suggested replacement text")),
 		mcp.WithString("suggestion_type", mcp.Description("Type of suggestion: 'REPLACE_TEXT', 'DELETE_TEXT', or 'INSERT_TEXT' (default: 'REPLACE_TEXT')")),
+		withAnchor(),
+		mcp.WithOutputSchema[CreateSuggestionOutput](),
 	)
 	s.AddTool(createSuggestionTool, mcp.NewTypedToolHandler(createSuggestionHandler))
 }
@@ -132,14 +223,15 @@ func RegisterCollaborationTools(s *server.MCPServer) {
 func createCommentHandler(ctx context.Context, request mcp.CallToolRequest, input CreateCommentInput) (*mcp.CallToolResult, error) {
 	driveService := services.GoogleDriveClient()
 
-	if input.StartIndex >= input.EndIndex {
-		return mcp.NewToolResultText("Error: Start index must be less than end index."), nil
+	startIndex, endIndex, errResult := resolveRange(ctx, input.DocumentID, input.StartIndex, input.EndIndex, input.Anchor, nil)
+	if errResult != nil {
+		return errResult, nil
 	}
 
 	// Create a comment with an anchor to the specified range
 	comment := &drive.Comment{
 		Content: input.Comment,
-		Anchor: fmt.Sprintf("kix.%d:%d", input.StartIndex, input.EndIndex),
+		Anchor:  fmt.Sprintf("kix.%d:%d", startIndex, endIndex),
 	}
 
 	createdComment, err := driveService.Comments.Create(input.DocumentID, comment).Context(ctx).Do()
@@ -148,9 +240,18 @@ func createCommentHandler(ctx context.Context, request mcp.CallToolRequest, inpu
 	}
 
 	result := fmt.Sprintf("Comment created successfully!\n\nDocument ID: %s\nComment ID: %s\nRange: %d-%d\nComment: %s\nAuthor: %s",
-		input.DocumentID, createdComment.Id, input.StartIndex, input.EndIndex, input.Comment, createdComment.Author.DisplayName)
+		input.DocumentID, createdComment.Id, startIndex, endIndex, input.Comment, createdComment.Author.DisplayName)
+
+	output := CreateCommentOutput{
+		DocumentID: input.DocumentID,
+		CommentID:  createdComment.Id,
+		StartIndex: startIndex,
+		EndIndex:   endIndex,
+		Content:    input.Comment,
+		Author:     createdComment.Author.DisplayName,
+	}
 
-	return mcp.NewToolResultText(result), nil
+	return mcp.NewToolResultStructured(output, result), nil
 }
 
 func replyToCommentHandler(ctx context.Context, request mcp.CallToolRequest, input ReplyToCommentInput) (*mcp.CallToolResult, error) {
@@ -169,7 +270,14 @@ func replyToCommentHandler(ctx context.Context, request mcp.CallToolRequest, inp
 	result := fmt.Sprintf("Reply created successfully!\n\nDocument ID: %s\nComment ID: %s\nReply ID: %s\nReply: %s\nAuthor: %s",
 		input.DocumentID, input.CommentID, createdReply.Id, input.Reply, createdReply.Author.DisplayName)
 
-	return mcp.NewToolResultText(result), nil
+	output := ReplyToCommentOutput{
+		DocumentID: input.DocumentID,
+		CommentID:  input.CommentID,
+		ReplyID:    createdReply.Id,
+		Author:     createdReply.Author.DisplayName,
+	}
+
+	return mcp.NewToolResultStructured(output, result), nil
 }
 
 func listCommentsHandler(ctx context.Context, request mcp.CallToolRequest, input ListCommentsInput) (*mcp.CallToolResult, error) {
@@ -184,8 +292,17 @@ func listCommentsHandler(ctx context.Context, request mcp.CallToolRequest, input
 		return util.HandleGoogleAPIError("list comments", err), nil
 	}
 
+	output := ListCommentsOutput{
+		DocumentID: input.DocumentID,
+		Comments:   []CommentOutput{},
+		Count:      len(commentsList.Comments),
+	}
+	for _, comment := range commentsList.Comments {
+		output.Comments = append(output.Comments, commentOutput(comment))
+	}
+
 	if len(commentsList.Comments) == 0 {
-		return mcp.NewToolResultText("No comments found in this document."), nil
+		return mcp.NewToolResultStructured(output, "No comments found in this document."), nil
 	}
 
 	var result strings.Builder
@@ -197,7 +314,7 @@ func listCommentsHandler(ctx context.Context, request mcp.CallToolRequest, input
 		result.WriteString(fmt.Sprintf("   Created: %s\n", comment.CreatedTime))
 		result.WriteString(fmt.Sprintf("   Content: %s\n", comment.Content))
 		result.WriteString(fmt.Sprintf("   Resolved: %t\n", comment.Resolved))
-		
+
 		if comment.Anchor != "" {
 			result.WriteString(fmt.Sprintf("   Anchor: %s\n", comment.Anchor))
 		}
@@ -211,7 +328,7 @@ func listCommentsHandler(ctx context.Context, request mcp.CallToolRequest, input
 		result.WriteString("\n")
 	}
 
-	return mcp.NewToolResultText(result.String()), nil
+	return mcp.NewToolResultStructured(output, result.String()), nil
 }
 
 func resolveCommentHandler(ctx context.Context, request mcp.CallToolRequest, input ResolveCommentInput) (*mcp.CallToolResult, error) {
@@ -230,7 +347,13 @@ func resolveCommentHandler(ctx context.Context, request mcp.CallToolRequest, inp
 	result := fmt.Sprintf("Comment resolved successfully!\n\nDocument ID: %s\nComment ID: %s\nResolved: %t",
 		input.DocumentID, input.CommentID, updatedComment.Resolved)
 
-	return mcp.NewToolResultText(result), nil
+	output := ResolveCommentOutput{
+		DocumentID: input.DocumentID,
+		CommentID:  input.CommentID,
+		Resolved:   updatedComment.Resolved,
+	}
+
+	return mcp.NewToolResultStructured(output, result), nil
 }
 
 func getPermissionsHandler(ctx context.Context, request mcp.CallToolRequest, input GetPermissionsInput) (*mcp.CallToolResult, error) {
@@ -245,8 +368,24 @@ func getPermissionsHandler(ctx context.Context, request mcp.CallToolRequest, inp
 		return util.HandleGoogleAPIError("get permissions", err), nil
 	}
 
+	output := GetPermissionsOutput{
+		DocumentID:  input.DocumentID,
+		Permissions: []PermissionOutput{},
+		Count:       len(permissionsList.Permissions),
+	}
+	for _, permission := range permissionsList.Permissions {
+		output.Permissions = append(output.Permissions, PermissionOutput{
+			ID:     permission.Id,
+			Type:   permission.Type,
+			Role:   permission.Role,
+			Email:  permission.EmailAddress,
+			Name:   permission.DisplayName,
+			Domain: permission.Domain,
+		})
+	}
+
 	if len(permissionsList.Permissions) == 0 {
-		return mcp.NewToolResultText("No permissions found for this document."), nil
+		return mcp.NewToolResultStructured(output, "No permissions found for this document."), nil
 	}
 
 	var result strings.Builder
@@ -256,23 +395,23 @@ func getPermissionsHandler(ctx context.Context, request mcp.CallToolRequest, inp
 		result.WriteString(fmt.Sprintf("%d. Permission ID: %s\n", i+1, permission.Id))
 		result.WriteString(fmt.Sprintf("   Type: %s\n", permission.Type))
 		result.WriteString(fmt.Sprintf("   Role: %s\n", permission.Role))
-		
+
 		if permission.EmailAddress != "" {
 			result.WriteString(fmt.Sprintf("   Email: %s\n", permission.EmailAddress))
 		}
-		
+
 		if permission.DisplayName != "" {
 			result.WriteString(fmt.Sprintf("   Name: %s\n", permission.DisplayName))
 		}
-		
+
 		if permission.Domain != "" {
 			result.WriteString(fmt.Sprintf("   Domain: %s\n", permission.Domain))
 		}
-		
+
 		result.WriteString("\n")
 	}
 
-	return mcp.NewToolResultText(result.String()), nil
+	return mcp.NewToolResultStructured(output, result.String()), nil
 }
 
 func updatePermissionHandler(ctx context.Context, request mcp.CallToolRequest, input UpdatePermissionInput) (*mcp.CallToolResult, error) {
@@ -285,7 +424,7 @@ func updatePermissionHandler(ctx context.Context, request mcp.CallToolRequest, i
 		"commenter": true,
 	}
 	if !validRoles[input.Role] {
-		return mcp.NewToolResultText("Error: Invalid role. Must be 'reader', 'writer', or 'commenter'."), nil
+		return mcp.NewToolResultError("Error: Invalid role. Must be 'reader', 'writer', or 'commenter'."), nil
 	}
 
 	// Update the permission
@@ -301,7 +440,13 @@ func updatePermissionHandler(ctx context.Context, request mcp.CallToolRequest, i
 	result := fmt.Sprintf("Permission updated successfully!\n\nDocument ID: %s\nPermission ID: %s\nNew Role: %s",
 		input.DocumentID, input.PermissionID, updatedPermission.Role)
 
-	return mcp.NewToolResultText(result), nil
+	output := UpdatePermissionOutput{
+		DocumentID:   input.DocumentID,
+		PermissionID: input.PermissionID,
+		Role:         updatedPermission.Role,
+	}
+
+	return mcp.NewToolResultStructured(output, result), nil
 }
 
 func removePermissionHandler(ctx context.Context, request mcp.CallToolRequest, input RemovePermissionInput) (*mcp.CallToolResult, error) {
@@ -315,19 +460,26 @@ func removePermissionHandler(ctx context.Context, request mcp.CallToolRequest, i
 	result := fmt.Sprintf("Permission removed successfully!\n\nDocument ID: %s\nRemoved Permission ID: %s",
 		input.DocumentID, input.PermissionID)
 
-	return mcp.NewToolResultText(result), nil
+	output := RemovePermissionOutput{
+		DocumentID:   input.DocumentID,
+		PermissionID: input.PermissionID,
+		Removed:      true,
+	}
+
+	return mcp.NewToolResultStructured(output, result), nil
 }
 
 func createSuggestionHandler(ctx context.Context, request mcp.CallToolRequest, input CreateSuggestionInput) (*mcp.CallToolResult, error) {
 	// Note: Google Docs API doesn't directly support creating suggestions via API
 	// This is a limitation of the current API. Suggestions are typically created
 	// through the web interface when in "Suggesting" mode.
-	
+
 	// As a workaround, we can create a comment that describes the suggested change
 	driveService := services.GoogleDriveClient()
 
-	if input.StartIndex >= input.EndIndex {
-		return mcp.NewToolResultText("Error: Start index must be less than end index."), nil
+	startIndex, endIndex, errResult := resolveRange(ctx, input.DocumentID, input.StartIndex, input.EndIndex, input.Anchor, nil)
+	if errResult != nil {
+		return errResult, nil
 	}
 
 	suggestionType := input.SuggestionType
@@ -342,7 +494,7 @@ func createSuggestionHandler(ctx context.Context, request mcp.CallToolRequest, i
 		"INSERT_TEXT":  true,
 	}
 	if !validTypes[suggestionType] {
-		return mcp.NewToolResultText("Error: Invalid suggestion type. Must be 'REPLACE_TEXT', 'DELETE_TEXT', or 'INSERT_TEXT'."), nil
+		return mcp.NewToolResultError("Error: Invalid suggestion type. Must be 'REPLACE_TEXT', 'DELETE_TEXT', or 'INSERT_TEXT'."), nil
 	}
 
 	// Create a comment that describes the suggestion
@@ -358,7 +510,7 @@ func createSuggestionHandler(ctx context.Context, request mcp.CallToolRequest, i
 
 	comment := &drive.Comment{
 		Content: commentText,
-		Anchor:  fmt.Sprintf("kix.%d:%d", input.StartIndex, input.EndIndex),
+		Anchor:  fmt.Sprintf("kix.%d:%d", startIndex, endIndex),
 	}
 
 	createdComment, err := driveService.Comments.Create(input.DocumentID, comment).Context(ctx).Do()
@@ -367,7 +519,46 @@ func createSuggestionHandler(ctx context.Context, request mcp.CallToolRequest, i
 	}
 
 	result := fmt.Sprintf("Suggestion created successfully!\n\nNote: Google Docs API doesn't directly support suggestions, so this was created as a comment.\n\nDocument ID: %s\nComment ID: %s\nRange: %d-%d\nSuggestion Type: %s\nSuggested Text: %s\nComment: %s",
-		input.DocumentID, createdComment.Id, input.StartIndex, input.EndIndex, suggestionType, input.SuggestedText, commentText)
+		input.DocumentID, createdComment.Id, startIndex, endIndex, suggestionType, input.SuggestedText, commentText)
+
+	output := CreateSuggestionOutput{
+		CreateCommentOutput: CreateCommentOutput{
+			DocumentID: input.DocumentID,
+			CommentID:  createdComment.Id,
+			StartIndex: startIndex,
+			EndIndex:   endIndex,
+			Content:    commentText,
+			Author:     createdComment.Author.DisplayName,
+		},
+		SuggestionType: suggestionType,
+		SuggestedText:  input.SuggestedText,
+	}
 
-	return mcp.NewToolResultText(result), nil
+	return mcp.NewToolResultStructured(output, result), nil
+}
+
+// commentOutput converts a Drive comment and its replies to their structured form
+func commentOutput(comment *drive.Comment) CommentOutput {
+	output := CommentOutput{
+		ID:          comment.Id,
+		Content:     comment.Content,
+		CreatedTime: comment.CreatedTime,
+		Resolved:    comment.Resolved,
+		Anchor:      comment.Anchor,
+	}
+	if comment.Author != nil {
+		output.Author = comment.Author.DisplayName
+	}
+	for _, reply := range comment.Replies {
+		replyOutput := ReplyOutput{
+			ID:          reply.Id,
+			Content:     reply.Content,
+			CreatedTime: reply.CreatedTime,
+		}
+		if reply.Author != nil {
+			replyOutput.Author = reply.Author.DisplayName
+		}
+		output.Replies = append(output.Replies, replyOutput)
+	}
+	return output
 }
@@ -3,6 +3,8 @@ package tools
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/hdbrzgr/docs-mcp/services"
@@ -14,41 +16,138 @@ import (
 
 // Input types for content tools
 type InsertTextInput struct {
-	DocumentID string `json:"document_id" validate:"required"`
-	Text       string `json:"text" validate:"required"`
-	Index      int64  `json:"index,omitempty"` // Position to insert text (default: end of document)
+	DocumentID       string `json:"document_id" validate:"required"`
+	Text             string `json:"text" validate:"required"`
+	Index            int64  `json:"index,omitempty"` // Position to insert text (default: end of document)
+	RevisionID       string `json:"revision_id,omitempty"`
+	TargetRevisionID string `json:"target_revision_id,omitempty"`
 }
 
 type ReplaceTextInput struct {
-	DocumentID string `json:"document_id" validate:"required"`
-	StartIndex int64  `json:"start_index" validate:"required"`
-	EndIndex   int64  `json:"end_index" validate:"required"`
-	Text       string `json:"text" validate:"required"`
+	DocumentID       string       `json:"document_id" validate:"required"`
+	StartIndex       int64        `json:"start_index,omitempty"`
+	EndIndex         int64        `json:"end_index,omitempty"`
+	Text             string       `json:"text" validate:"required"`
+	Anchor           *util.Anchor `json:"anchor,omitempty"`
+	RevisionID       string       `json:"revision_id,omitempty"`
+	TargetRevisionID string       `json:"target_revision_id,omitempty"`
 }
 
 type DeleteTextInput struct {
-	DocumentID string `json:"document_id" validate:"required"`
-	StartIndex int64  `json:"start_index" validate:"required"`
-	EndIndex   int64  `json:"end_index" validate:"required"`
+	DocumentID       string       `json:"document_id" validate:"required"`
+	StartIndex       int64        `json:"start_index,omitempty"`
+	EndIndex         int64        `json:"end_index,omitempty"`
+	Anchor           *util.Anchor `json:"anchor,omitempty"`
+	RevisionID       string       `json:"revision_id,omitempty"`
+	TargetRevisionID string       `json:"target_revision_id,omitempty"`
 }
 
 type AppendTextInput struct {
-	DocumentID string `json:"document_id" validate:"required"`
-	Text       string `json:"text" validate:"required"`
+	DocumentID       string `json:"document_id" validate:"required"`
+	Text             string `json:"text" validate:"required"`
+	RevisionID       string `json:"revision_id,omitempty"`
+	TargetRevisionID string `json:"target_revision_id,omitempty"`
 }
 
 type ReadTextInput struct {
+	DocumentID string       `json:"document_id" validate:"required"`
+	StartIndex int64        `json:"start_index,omitempty"`
+	EndIndex   int64        `json:"end_index,omitempty"`
+	Anchor     *util.Anchor `json:"anchor,omitempty"`
+	PaginationInput
+}
+
+type ReadDocumentMarkdownInput struct {
 	DocumentID string `json:"document_id" validate:"required"`
-	StartIndex int64  `json:"start_index,omitempty"`
-	EndIndex   int64  `json:"end_index,omitempty"`
+}
+
+type WriteMarkdownInput struct {
+	DocumentID       string `json:"document_id" validate:"required"`
+	Markdown         string `json:"markdown" validate:"required"`
+	Mode             string `json:"mode,omitempty"`  // insert, append, replace
+	Index            int64  `json:"index,omitempty"` // Position for insert mode
+	RevisionID       string `json:"revision_id,omitempty"`
+	TargetRevisionID string `json:"target_revision_id,omitempty"`
 }
 
 type FindReplaceInput struct {
-	DocumentID  string `json:"document_id" validate:"required"`
-	FindText    string `json:"find_text" validate:"required"`
-	ReplaceText string `json:"replace_text" validate:"required"`
-	MatchCase   bool   `json:"match_case,omitempty"`
-	ReplaceAll  bool   `json:"replace_all,omitempty"`
+	DocumentID       string `json:"document_id" validate:"required"`
+	FindText         string `json:"find_text" validate:"required"`
+	ReplaceText      string `json:"replace_text" validate:"required"`
+	MatchCase        bool   `json:"match_case,omitempty"`
+	ReplaceAll       bool   `json:"replace_all,omitempty"`
+	Regex            bool   `json:"regex,omitempty"`
+	WholeWord        bool   `json:"whole_word,omitempty"`
+	Occurrences      string `json:"occurrences,omitempty"` // first, last, all, n, or a comma-separated list
+	Section          string `json:"section,omitempty"`     // Heading text or heading ID of the section to search
+	StartIndex       int64  `json:"start_index,omitempty"`
+	EndIndex         int64  `json:"end_index,omitempty"`
+	TableIndex       *int64 `json:"table_index,omitempty"`
+	DryRun           bool   `json:"dry_run,omitempty"`
+	RevisionID       string `json:"revision_id,omitempty"`
+	TargetRevisionID string `json:"target_revision_id,omitempty"`
+}
+
+// Output types for content tools
+type ReadTextOutput struct {
+	DocumentID string     `json:"document_id"`
+	Title      string     `json:"title"`
+	RevisionID string     `json:"revision_id"`
+	Text       string     `json:"text"`
+	StartIndex int64      `json:"start_index"`
+	EndIndex   int64      `json:"end_index"`
+	Length     int64      `json:"length" jsonschema_description:"Length of the text in UTF-16 code units"`
+	Page       PageOutput `json:"page"`
+}
+
+type ReadDocumentMarkdownOutput struct {
+	DocumentID string `json:"document_id"`
+	Title      string `json:"title"`
+	RevisionID string `json:"revision_id"`
+	Markdown   string `json:"markdown"`
+}
+
+type WriteMarkdownOutput struct {
+	EditOutput
+	Mode       string `json:"mode"`
+	Paragraphs int    `json:"paragraphs"`
+	Tables     int    `json:"tables"`
+	Images     int    `json:"images"`
+	Requests   int    `json:"requests"`
+}
+
+type FindReplaceOutput struct {
+	DocumentID  string              `json:"document_id"`
+	RevisionID  string              `json:"revision_id,omitempty"`
+	Occurrences int64               `json:"occurrences" jsonschema_description:"Number of occurrences replaced, or that would be replaced in a dry run"`
+	Matches     int                 `json:"matches" jsonschema_description:"Number of occurrences found in the scope"`
+	DryRun      bool                `json:"dry_run,omitempty"`
+	StartIndex  int64               `json:"start_index,omitempty" jsonschema_description:"Start of the replaced occurrence when only one is replaced"`
+	EndIndex    int64               `json:"end_index,omitempty" jsonschema_description:"End of the replaced occurrence when only one is replaced"`
+	Changes     []ReplacementChange `json:"changes"`
+	Skipped     []SkippedMatch      `json:"skipped,omitempty" jsonschema_description:"Matches left alone because replacing them would delete structure the document needs"`
+}
+
+type ReplacementChange struct {
+	Occurrence    int    `json:"occurrence" jsonschema_description:"Position of the occurrence among the matches, starting at 1"`
+	Segment       string `json:"segment" jsonschema_description:"Where the occurrence is: body, header, footer or footnote"`
+	SegmentID     string `json:"segment_id,omitempty"`
+	StartIndex    int64  `json:"start_index" jsonschema_description:"Start of the occurrence before the replacement"`
+	EndIndex      int64  `json:"end_index" jsonschema_description:"End of the occurrence before the replacement"`
+	Text          string `json:"text"`
+	Replacement   string `json:"replacement"`
+	NewStartIndex int64  `json:"new_start_index" jsonschema_description:"Start of the replacement text after all replacements"`
+	NewEndIndex   int64  `json:"new_end_index" jsonschema_description:"End of the replacement text after all replacements"`
+	Context       string `json:"context"`
+}
+
+type SkippedMatch struct {
+	Segment    string `json:"segment"`
+	SegmentID  string `json:"segment_id,omitempty"`
+	StartIndex int64  `json:"start_index"`
+	EndIndex   int64  `json:"end_index"`
+	Text       string `json:"text"`
+	Reason     string `json:"reason"`
 }
 
 func RegisterContentTools(s *server.MCPServer) {
@@ -58,6 +157,8 @@ func RegisterContentTools(s *server.MCPServer) {
 		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
 		mcp.WithString("text", mcp.Required(), mcp.Description("The text to insert")),
 		mcp.WithNumber("index", mcp.Description("Position to insert text (default: end of document)")),
+		withWriteControl(),
+		mcp.WithOutputSchema[EditOutput](),
 	)
 	s.AddTool(insertTextTool, mcp.NewTypedToolHandler(insertTextHandler))
 
@@ -65,9 +166,12 @@ func RegisterContentTools(s *server.MCPServer) {
 	replaceTextTool := mcp.NewTool("replace_text",
 		mcp.WithDescription("Replace text in a specific range within a Google Docs document"),
 		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
-		mcp.WithNumber("start_index", mcp.Required(), mcp.Description("Start position of the text to replace")),
-		mcp.WithNumber("end_index", mcp.Required(), mcp.Description("End position of the text to replace")),
+		mcp.WithNumber("start_index", mcp.Description("Start position of the text to replace")),
+		mcp.WithNumber("end_index", mcp.Description("End position of the text to replace")),
 		mcp.WithString("text", mcp.Required(), mcp.Description("The replacement text")),
+		withAnchor(),
+		withWriteControl(),
+		mcp.WithOutputSchema[EditOutput](),
 	)
 	s.AddTool(replaceTextTool, mcp.NewTypedToolHandler(replaceTextHandler))
 
@@ -75,8 +179,11 @@ func RegisterContentTools(s *server.MCPServer) {
 	deleteTextTool := mcp.NewTool("delete_text",
 		mcp.WithDescription("Delete text in a specific range within a Google Docs document"),
 		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
-		mcp.WithNumber("start_index", mcp.Required(), mcp.Description("Start position of the text to delete")),
-		mcp.WithNumber("end_index", mcp.Required(), mcp.Description("End position of the text to delete")),
+		mcp.WithNumber("start_index", mcp.Description("Start position of the text to delete")),
+		mcp.WithNumber("end_index", mcp.Description("End position of the text to delete")),
+		withAnchor(),
+		withWriteControl(),
+		mcp.WithOutputSchema[EditOutput](),
 	)
 	s.AddTool(deleteTextTool, mcp.NewTypedToolHandler(deleteTextHandler))
 
@@ -85,26 +192,61 @@ func RegisterContentTools(s *server.MCPServer) {
 		mcp.WithDescription("Append text to the end of a Google Docs document"),
 		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
 		mcp.WithString("text", mcp.Required(), mcp.Description("The text to append")),
+		withWriteControl(),
+		mcp.WithOutputSchema[EditOutput](),
 	)
 	s.AddTool(appendTextTool, mcp.NewTypedToolHandler(appendTextHandler))
 
 	// Read text tool
 	readTextTool := mcp.NewTool("read_text",
-		mcp.WithDescription("Read text content from a Google Docs document, optionally within a specific range"),
+		mcp.WithDescription("Read text content from a Google Docs document, optionally within a specific range. Large content is returned page by page: pass the returned next_cursor to read the next page"),
 		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
-		mcp.WithNumber("start_index", mcp.Description("Start position to read from (default: beginning of document)")),
-		mcp.WithNumber("end_index", mcp.Description("End position to read to (default: end of document)")),
+		mcp.WithNumber("start_index", mcp.Description("Start document index to read from, in the same UTF-16 based positions the editing tools use (default: beginning of document)")),
+		mcp.WithNumber("end_index", mcp.Description("End document index to read to (default: end of document)")),
+		withAnchor(),
+		withPagination(),
+		mcp.WithOutputSchema[ReadTextOutput](),
 	)
 	s.AddTool(readTextTool, mcp.NewTypedToolHandler(readTextHandler))
 
+	// Read document as Markdown tool
+	readMarkdownTool := mcp.NewTool("read_document_markdown",
+		mcp.WithDescription("Read the content of a Google Docs document as Markdown, with headings, bold/italic/strikethrough, links, lists, images and tables converted to their Markdown equivalents"),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
+		mcp.WithOutputSchema[ReadDocumentMarkdownOutput](),
+	)
+	s.AddTool(readMarkdownTool, mcp.NewTypedToolHandler(readDocumentMarkdownHandler))
+
+	// Write Markdown tool
+	writeMarkdownTool := mcp.NewTool("write_markdown",
+		mcp.WithDescription("Write Markdown (CommonMark with GFM tables, strikethrough and task lists) into a Google Docs document as native headings, lists, tables, links and text styles in a single atomic update"),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
+		mcp.WithString("markdown", mcp.Required(), mcp.Description("The Markdown content to write")),
+		mcp.WithString("mode", mcp.Description("Write mode: 'insert' at an index, 'append' to the end, or 'replace' the whole body (default: 'insert' when index is given, otherwise 'append')")),
+		mcp.WithNumber("index", mcp.Description("Position to insert the content in 'insert' mode; should be the start of a paragraph")),
+		withWriteControl(),
+		mcp.WithOutputSchema[WriteMarkdownOutput](),
+	)
+	s.AddTool(writeMarkdownTool, mcp.NewTypedToolHandler(writeMarkdownHandler))
+
 	// Find and replace tool
 	findReplaceTool := mcp.NewTool("find_replace",
-		mcp.WithDescription("Find and replace text in a Google Docs document with options for case sensitivity and replace all"),
+		mcp.WithDescription("Find and replace text in a Google Docs document, by literal text or regular expression with capture groups. Choose which occurrences to replace, limit the search to a section, range or table, and preview the changes with dry_run. Replacements keep the text style of the text they replace. Occurrences are numbered in the order search_document returns them"),
 		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
-		mcp.WithString("find_text", mcp.Required(), mcp.Description("The text to find")),
-		mcp.WithString("replace_text", mcp.Required(), mcp.Description("The replacement text")),
+		mcp.WithString("find_text", mcp.Required(), mcp.Description("The text to find, or a regular expression when regex is true")),
+		mcp.WithString("replace_text", mcp.Required(), mcp.Description("The replacement text. With regex, $1 or ${name} insert capture groups and $$ inserts a dollar sign")),
 		mcp.WithBoolean("match_case", mcp.Description("Whether to match case when searching (default: false)")),
-		mcp.WithBoolean("replace_all", mcp.Description("Whether to replace all occurrences (default: false, replaces first occurrence only)")),
+		mcp.WithBoolean("replace_all", mcp.Description("Whether to replace all occurrences (default: false, replaces first occurrence only); same as occurrences 'all'")),
+		mcp.WithBoolean("regex", mcp.Description("Treat find_text as a regular expression (RE2 syntax) (default: false)")),
+		mcp.WithBoolean("whole_word", mcp.Description("Only match whole words (default: false)")),
+		mcp.WithString("occurrences", mcp.Description("Which occurrences to replace: 'first', 'last', 'all', a number such as '3', or a list such as '1,3,5', counting from 1 (default: 'first', or 'all' with replace_all)")),
+		mcp.WithString("section", mcp.Description("Only replace within the section started by this heading text or heading ID")),
+		mcp.WithNumber("start_index", mcp.Description("Only replace within the range starting at this index")),
+		mcp.WithNumber("end_index", mcp.Description("Only replace within the range ending at this index")),
+		mcp.WithNumber("table_index", mcp.Description("Only replace within this table of the document (0-based)")),
+		mcp.WithBoolean("dry_run", mcp.Description("Return the planned changes without modifying the document (default: false)")),
+		withWriteControl(),
+		mcp.WithOutputSchema[FindReplaceOutput](),
 	)
 	s.AddTool(findReplaceTool, mcp.NewTypedToolHandler(findReplaceHandler))
 }
@@ -112,6 +254,11 @@ func RegisterContentTools(s *server.MCPServer) {
 func insertTextHandler(ctx context.Context, request mcp.CallToolRequest, input InsertTextInput) (*mcp.CallToolResult, error) {
 	docsService := services.GoogleDocsClient()
 
+	writeControl, errResult := newWriteControl(input.RevisionID, input.TargetRevisionID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
 	// Get document to determine insertion index if not provided
 	doc, err := docsService.Documents.Get(input.DocumentID).Context(ctx).Do()
 	if err != nil {
@@ -120,8 +267,12 @@ func insertTextHandler(ctx context.Context, request mcp.CallToolRequest, input I
 
 	insertIndex := input.Index
 	if insertIndex <= 0 {
-		// Insert at the end of the document (before the last character which is usually a newline)
-		insertIndex = int64(len(doc.Body.Content)) - 1
+		if errResult := pinWriteControl(writeControl, doc); errResult != nil {
+			return errResult, nil
+		}
+
+		// Insert at the end of the document (before the final newline)
+		insertIndex = util.BodyEndIndex(doc) - 1
 		if insertIndex < 1 {
 			insertIndex = 1
 		}
@@ -140,53 +291,57 @@ func insertTextHandler(ctx context.Context, request mcp.CallToolRequest, input I
 	}
 
 	batchUpdateRequest := &docs.BatchUpdateDocumentRequest{
-		Requests: requests,
+		Requests:     requests,
+		WriteControl: writeControl,
 	}
 
-	_, err = docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
+	response, err := docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
 	if err != nil {
-		return util.HandleGoogleAPIError("insert text", err), nil
+		return handleWriteError(ctx, "insert text", input.DocumentID, writeControl, err), nil
 	}
 
-	result := fmt.Sprintf("Text inserted successfully!\n\nDocument ID: %s\nInsertion Index: %d\nText Length: %d characters",
-		input.DocumentID, insertIndex, len(input.Text))
+	textLength := util.UTF16Length(input.Text)
+	result := fmt.Sprintf("Text inserted successfully!\n\nDocument ID: %s\nInsertion Index: %d\nInserted Range: %d-%d\nText Length: %d characters",
+		input.DocumentID, insertIndex, insertIndex, insertIndex+textLength, textLength)
 
-	return mcp.NewToolResultText(result), nil
+	output := EditOutput{
+		DocumentID: input.DocumentID,
+		RevisionID: revisionAfter(response),
+		StartIndex: insertIndex,
+		EndIndex:   insertIndex + textLength,
+		Length:     textLength,
+	}
+
+	return mcp.NewToolResultStructured(output, result), nil
 }
 
 func replaceTextHandler(ctx context.Context, request mcp.CallToolRequest, input ReplaceTextInput) (*mcp.CallToolResult, error) {
 	docsService := services.GoogleDocsClient()
 
-	if input.StartIndex >= input.EndIndex {
-		return mcp.NewToolResultText("Error: Start index must be less than end index."), nil
+	writeControl, errResult := newWriteControl(input.RevisionID, input.TargetRevisionID)
+	if errResult != nil {
+		return errResult, nil
 	}
 
-	// Create the batch update request
-	requests := []*docs.Request{
-		{
-			ReplaceAllText: &docs.ReplaceAllTextRequest{
-				ContainsText: &docs.SubstringMatchCriteria{
-					Text: input.Text,
-				},
-				ReplaceText: input.Text,
-			},
-		},
+	startIndex, endIndex, errResult := resolveRange(ctx, input.DocumentID, input.StartIndex, input.EndIndex, input.Anchor, writeControl)
+	if errResult != nil {
+		return errResult, nil
 	}
 
-	// For more precise replacement, we'll use delete and insert
-	requests = []*docs.Request{
+	// Replace the range by deleting it and inserting the new text at its start
+	requests := []*docs.Request{
 		{
 			DeleteContentRange: &docs.DeleteContentRangeRequest{
 				Range: &docs.Range{
-					StartIndex: input.StartIndex,
-					EndIndex:   input.EndIndex,
+					StartIndex: startIndex,
+					EndIndex:   endIndex,
 				},
 			},
 		},
 		{
 			InsertText: &docs.InsertTextRequest{
 				Location: &docs.Location{
-					Index: input.StartIndex,
+					Index: startIndex,
 				},
 				Text: input.Text,
 			},
@@ -194,25 +349,41 @@ func replaceTextHandler(ctx context.Context, request mcp.CallToolRequest, input
 	}
 
 	batchUpdateRequest := &docs.BatchUpdateDocumentRequest{
-		Requests: requests,
+		Requests:     requests,
+		WriteControl: writeControl,
 	}
 
-	_, err := docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
+	response, err := docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
 	if err != nil {
-		return util.HandleGoogleAPIError("replace text", err), nil
+		return handleWriteError(ctx, "replace text", input.DocumentID, writeControl, err), nil
 	}
 
-	result := fmt.Sprintf("Text replaced successfully!\n\nDocument ID: %s\nRange: %d-%d\nReplacement Length: %d characters",
-		input.DocumentID, input.StartIndex, input.EndIndex, len(input.Text))
+	textLength := util.UTF16Length(input.Text)
+	result := fmt.Sprintf("Text replaced successfully!\n\nDocument ID: %s\nRange: %d-%d\nNew Range: %d-%d\nReplacement Length: %d characters",
+		input.DocumentID, startIndex, endIndex, startIndex, startIndex+textLength, textLength)
+
+	output := EditOutput{
+		DocumentID: input.DocumentID,
+		RevisionID: revisionAfter(response),
+		StartIndex: startIndex,
+		EndIndex:   startIndex + textLength,
+		Length:     textLength,
+	}
 
-	return mcp.NewToolResultText(result), nil
+	return mcp.NewToolResultStructured(output, result), nil
 }
 
 func deleteTextHandler(ctx context.Context, request mcp.CallToolRequest, input DeleteTextInput) (*mcp.CallToolResult, error) {
 	docsService := services.GoogleDocsClient()
 
-	if input.StartIndex >= input.EndIndex {
-		return mcp.NewToolResultText("Error: Start index must be less than end index."), nil
+	writeControl, errResult := newWriteControl(input.RevisionID, input.TargetRevisionID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	startIndex, endIndex, errResult := resolveRange(ctx, input.DocumentID, input.StartIndex, input.EndIndex, input.Anchor, writeControl)
+	if errResult != nil {
+		return errResult, nil
 	}
 
 	// Create the batch update request
@@ -220,48 +391,60 @@ func deleteTextHandler(ctx context.Context, request mcp.CallToolRequest, input D
 		{
 			DeleteContentRange: &docs.DeleteContentRangeRequest{
 				Range: &docs.Range{
-					StartIndex: input.StartIndex,
-					EndIndex:   input.EndIndex,
+					StartIndex: startIndex,
+					EndIndex:   endIndex,
 				},
 			},
 		},
 	}
 
 	batchUpdateRequest := &docs.BatchUpdateDocumentRequest{
-		Requests: requests,
+		Requests:     requests,
+		WriteControl: writeControl,
 	}
 
-	_, err := docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
+	response, err := docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
 	if err != nil {
-		return util.HandleGoogleAPIError("delete text", err), nil
+		return handleWriteError(ctx, "delete text", input.DocumentID, writeControl, err), nil
 	}
 
-	deletedLength := input.EndIndex - input.StartIndex
+	deletedLength := endIndex - startIndex
 	result := fmt.Sprintf("Text deleted successfully!\n\nDocument ID: %s\nDeleted Range: %d-%d\nDeleted Length: %d characters",
-		input.DocumentID, input.StartIndex, input.EndIndex, deletedLength)
+		input.DocumentID, startIndex, endIndex, deletedLength)
+
+	output := EditOutput{
+		DocumentID: input.DocumentID,
+		RevisionID: revisionAfter(response),
+		StartIndex: startIndex,
+		EndIndex:   endIndex,
+		Length:     deletedLength,
+	}
 
-	return mcp.NewToolResultText(result), nil
+	return mcp.NewToolResultStructured(output, result), nil
 }
 
 func appendTextHandler(ctx context.Context, request mcp.CallToolRequest, input AppendTextInput) (*mcp.CallToolResult, error) {
 	docsService := services.GoogleDocsClient()
 
+	writeControl, errResult := newWriteControl(input.RevisionID, input.TargetRevisionID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
 	// Get document to determine the end index
 	doc, err := docsService.Documents.Get(input.DocumentID).Context(ctx).Do()
 	if err != nil {
 		return util.HandleGoogleAPIError("get document for text appending", err), nil
 	}
 
-	// Calculate the end index (usually the last index minus 1 for the final newline)
-	endIndex := int64(1)
-	if doc.Body != nil && len(doc.Body.Content) > 0 {
-		// Find the actual end of content
-		for _, element := range doc.Body.Content {
-			if element.EndIndex > endIndex {
-				endIndex = element.EndIndex
-			}
-		}
-		endIndex-- // Insert before the final character
+	if errResult := pinWriteControl(writeControl, doc); errResult != nil {
+		return errResult, nil
+	}
+
+	// Insert before the final newline of the body
+	endIndex := util.BodyEndIndex(doc) - 1
+	if endIndex < 1 {
+		endIndex = 1
 	}
 
 	// Create the batch update request
@@ -277,18 +460,28 @@ func appendTextHandler(ctx context.Context, request mcp.CallToolRequest, input A
 	}
 
 	batchUpdateRequest := &docs.BatchUpdateDocumentRequest{
-		Requests: requests,
+		Requests:     requests,
+		WriteControl: writeControl,
 	}
 
-	_, err = docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
+	response, err := docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
 	if err != nil {
-		return util.HandleGoogleAPIError("append text", err), nil
+		return handleWriteError(ctx, "append text", input.DocumentID, writeControl, err), nil
 	}
 
-	result := fmt.Sprintf("Text appended successfully!\n\nDocument ID: %s\nAppended at Index: %d\nText Length: %d characters",
-		input.DocumentID, endIndex, len(input.Text))
+	textLength := util.UTF16Length(input.Text)
+	result := fmt.Sprintf("Text appended successfully!\n\nDocument ID: %s\nAppended at Index: %d\nInserted Range: %d-%d\nText Length: %d characters",
+		input.DocumentID, endIndex, endIndex, endIndex+textLength, textLength)
+
+	output := EditOutput{
+		DocumentID: input.DocumentID,
+		RevisionID: revisionAfter(response),
+		StartIndex: endIndex,
+		EndIndex:   endIndex + textLength,
+		Length:     textLength,
+	}
 
-	return mcp.NewToolResultText(result), nil
+	return mcp.NewToolResultStructured(output, result), nil
 }
 
 func readTextHandler(ctx context.Context, request mcp.CallToolRequest, input ReadTextInput) (*mcp.CallToolResult, error) {
@@ -299,117 +492,546 @@ func readTextHandler(ctx context.Context, request mcp.CallToolRequest, input Rea
 		return util.HandleGoogleAPIError("get document for reading", err), nil
 	}
 
-	// Extract plain text from the document
-	fullText := util.ExtractPlainText(doc)
+	// Map the plain text to document indices so ranges use the same
+	// UTF-16 based positions as the editing tools
+	textMap := util.NewTextMap(doc)
 
-	// If range is specified, extract the substring
-	if input.StartIndex > 0 || input.EndIndex > 0 {
-		startIdx := int(input.StartIndex)
-		endIdx := int(input.EndIndex)
+	if !input.Anchor.IsZero() {
+		input.StartIndex, input.EndIndex, err = util.ResolveAnchor(doc, input.Anchor)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Error: Could not resolve anchor: %v", err)), nil
+		}
+	}
 
-		if startIdx < 0 {
-			startIdx = 0
+	// Without a cursor, page through the requested range or the whole text
+	rangeStart, rangeEnd := textMap.StartIndex(), textMap.EndIndex()
+	if input.Cursor == "" && (input.StartIndex > 0 || input.EndIndex > 0) {
+		if input.StartIndex > rangeStart {
+			rangeStart = input.StartIndex
 		}
-		if endIdx <= 0 || endIdx > len(fullText) {
-			endIdx = len(fullText)
+		if input.EndIndex > 0 && input.EndIndex < rangeEnd {
+			rangeEnd = input.EndIndex
 		}
-		if startIdx >= endIdx {
-			return mcp.NewToolResultText("Error: Invalid range. Start index must be less than end index."), nil
+		if rangeStart >= rangeEnd {
+			return mcp.NewToolResultError("Error: Invalid range. Start index must be less than end index."), nil
 		}
+	}
 
-		rangeText := fullText[startIdx:endIdx]
-		result := fmt.Sprintf("Text content (range %d-%d):\n\n%s\n\n--- End of Content ---\nTotal characters in range: %d",
-			startIdx, endIdx, rangeText, len(rangeText))
-		return mcp.NewToolResultText(result), nil
+	page, errResult := readPage(doc, textMap, rangeStart, rangeEnd, input.PaginationInput, false)
+	if errResult != nil {
+		return errResult, nil
 	}
 
-	// Return full document text
-	result := fmt.Sprintf("Document: %s\nDocument ID: %s\n\n--- Content ---\n%s\n\n--- End of Content ---\nTotal characters: %d",
-		doc.Title, doc.DocumentId, fullText, len(fullText))
+	pageText := textMap.Slice(page.StartIndex, page.EndIndex)
+	result := fmt.Sprintf("Document: %s\nDocument ID: %s\nRevision ID: %s\n%s\n--- Content ---\n%s\n\n--- End of Content ---",
+		doc.Title, doc.DocumentId, doc.RevisionId, pageHeader(page), pageText)
+
+	output := ReadTextOutput{
+		DocumentID: doc.DocumentId,
+		Title:      doc.Title,
+		RevisionID: doc.RevisionId,
+		Text:       pageText,
+		StartIndex: page.StartIndex,
+		EndIndex:   page.EndIndex,
+		Length:     util.UTF16Length(pageText),
+		Page:       page,
+	}
 
-	return mcp.NewToolResultText(result), nil
+	return mcp.NewToolResultStructured(output, result), nil
 }
 
-func findReplaceHandler(ctx context.Context, request mcp.CallToolRequest, input FindReplaceInput) (*mcp.CallToolResult, error) {
+func readDocumentMarkdownHandler(ctx context.Context, request mcp.CallToolRequest, input ReadDocumentMarkdownInput) (*mcp.CallToolResult, error) {
 	docsService := services.GoogleDocsClient()
 
-	// Create the batch update request
-	requests := []*docs.Request{
-		{
-			ReplaceAllText: &docs.ReplaceAllTextRequest{
-				ContainsText: &docs.SubstringMatchCriteria{
-					Text:      input.FindText,
-					MatchCase: input.MatchCase,
+	doc, err := docsService.Documents.Get(input.DocumentID).Context(ctx).Do()
+	if err != nil {
+		return util.HandleGoogleAPIError("get document for markdown conversion", err), nil
+	}
+
+	markdown := util.ConvertToMarkdown(doc)
+
+	output := ReadDocumentMarkdownOutput{
+		DocumentID: doc.DocumentId,
+		Title:      doc.Title,
+		RevisionID: doc.RevisionId,
+		Markdown:   markdown,
+	}
+
+	return mcp.NewToolResultStructured(output, markdown), nil
+}
+
+func writeMarkdownHandler(ctx context.Context, request mcp.CallToolRequest, input WriteMarkdownInput) (*mcp.CallToolResult, error) {
+	docsService := services.GoogleDocsClient()
+
+	writeControl, errResult := newWriteControl(input.RevisionID, input.TargetRevisionID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	mode := input.Mode
+	if mode == "" {
+		mode = "append"
+		if input.Index > 0 {
+			mode = "insert"
+		}
+	}
+
+	validModes := map[string]bool{
+		"insert":  true,
+		"append":  true,
+		"replace": true,
+	}
+	if !validModes[mode] {
+		return mcp.NewToolResultError("Error: Invalid mode. Must be 'insert', 'append', or 'replace'."), nil
+	}
+
+	if mode == "insert" && input.Index < 1 {
+		return mcp.NewToolResultError("Error: Index must be at least 1 in 'insert' mode."), nil
+	}
+
+	doc, err := docsService.Documents.Get(input.DocumentID).Context(ctx).Do()
+	if err != nil {
+		return util.HandleGoogleAPIError("get document for markdown writing", err), nil
+	}
+
+	// Indices outside insert mode are computed from the document just read
+	if mode != "insert" {
+		if errResult := pinWriteControl(writeControl, doc); errResult != nil {
+			return errResult, nil
+		}
+	}
+
+	// Find the end of the body and whether the final paragraph is empty
+	bodyEnd := int64(1)
+	lastParagraphEmpty := true
+	if doc.Body != nil && len(doc.Body.Content) > 0 {
+		last := doc.Body.Content[len(doc.Body.Content)-1]
+		bodyEnd = last.EndIndex
+		if last.Paragraph != nil {
+			lastParagraphEmpty = last.EndIndex-last.StartIndex <= 1
+		}
+	}
+
+	var requests []*docs.Request
+	insertIndex := input.Index
+	newParagraph := false
+
+	switch mode {
+	case "replace":
+		requests = append(requests, clearBodyRequests(doc)...)
+		insertIndex = 1
+	case "append":
+		insertIndex = bodyEnd - 1
+		if insertIndex < 1 {
+			insertIndex = 1
+		}
+		// Start a new paragraph so the content is not merged into the last one
+		newParagraph = !lastParagraphEmpty
+	}
+
+	compileIndex := insertIndex
+	if newParagraph {
+		compileIndex++
+	}
+	// Outside insert mode the content fills the final paragraph of the body
+	fillParagraph := mode != "insert"
+	batch, err := util.CompileMarkdown(input.Markdown, compileIndex, fillParagraph)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error: Failed to parse Markdown: %v", err)), nil
+	}
+
+	// A leading table starts a new paragraph with the newline InsertTable adds
+	if newParagraph && len(batch.Requests) > 0 && batch.Requests[0].InsertTable != nil {
+		newParagraph = false
+		batch, err = util.CompileMarkdown(input.Markdown, insertIndex, fillParagraph)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Error: Failed to parse Markdown: %v", err)), nil
+		}
+	}
+	if newParagraph {
+		requests = append(requests, &docs.Request{
+			InsertText: &docs.InsertTextRequest{
+				Location: &docs.Location{
+					Index: insertIndex,
 				},
-				ReplaceText: input.ReplaceText,
+				Text: "\n",
 			},
+		})
+	}
+
+	if len(batch.Requests) == 0 {
+		return mcp.NewToolResultError("Error: The Markdown did not contain any content to write."), nil
+	}
+
+	batchUpdateRequest := &docs.BatchUpdateDocumentRequest{
+		Requests:     append(requests, batch.Requests...),
+		WriteControl: writeControl,
+	}
+
+	response, err := docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
+	if err != nil {
+		return handleWriteError(ctx, "write markdown", input.DocumentID, writeControl, err), nil
+	}
+
+	result := fmt.Sprintf("Markdown written successfully!\n\nDocument ID: %s\nMode: %s\nInserted Range: %d-%d\nParagraphs: %d\nTables: %d\nImages: %d\nRequests: %d",
+		input.DocumentID, mode, batch.StartIndex, batch.EndIndex, batch.Paragraphs, batch.Tables, batch.Images, len(batchUpdateRequest.Requests))
+
+	output := WriteMarkdownOutput{
+		EditOutput: EditOutput{
+			DocumentID: input.DocumentID,
+			RevisionID: revisionAfter(response),
+			StartIndex: batch.StartIndex,
+			EndIndex:   batch.EndIndex,
+			Length:     batch.EndIndex - batch.StartIndex,
 		},
+		Mode:       mode,
+		Paragraphs: batch.Paragraphs,
+		Tables:     batch.Tables,
+		Images:     batch.Images,
+		Requests:   len(batchUpdateRequest.Requests),
+	}
+
+	return mcp.NewToolResultStructured(output, result), nil
+}
+
+// clearBodyRequests returns the requests that delete the content of the body
+// except its final newline. Tables and tables of contents are deleted on their
+// own, last to first, so no range splits one or keeps the newline before it.
+func clearBodyRequests(doc *docs.Document) []*docs.Request {
+	if doc.Body == nil {
+		return nil
+	}
+
+	var requests []*docs.Request
+	end := util.BodyEndIndex(doc) - 1
+	content := doc.Body.Content
+	for i := len(content) - 1; i >= 0; i-- {
+		element := content[i]
+		if element.Table == nil && element.TableOfContents == nil {
+			continue
+		}
+		if end > element.EndIndex {
+			requests = append(requests, deleteRangeRequest(element.EndIndex, end))
+		}
+		requests = append(requests, deleteRangeRequest(element.StartIndex, element.EndIndex))
+		end = element.StartIndex
+	}
+	if end > 1 {
+		requests = append(requests, deleteRangeRequest(1, end))
+	}
+	return requests
+}
+
+func findReplaceHandler(ctx context.Context, request mcp.CallToolRequest, input FindReplaceInput) (*mcp.CallToolResult, error) {
+	docsService := services.GoogleDocsClient()
+
+	writeControl, errResult := newWriteControl(input.RevisionID, input.TargetRevisionID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	scopes := 0
+	if input.Section != "" {
+		scopes++
+	}
+	if input.StartIndex > 0 || input.EndIndex > 0 {
+		scopes++
+	}
+	if input.TableIndex != nil {
+		scopes++
+	}
+	if scopes > 1 {
+		return mcp.NewToolResultError("Error: Use only one of section, start_index/end_index, or table_index to limit the scope."), nil
+	}
+
+	// Matches are found in the current revision, which moves a target
+	// revision forward, so a scope given as indices of an older revision
+	// would cover the wrong text
+	if writeControl != nil && writeControl.TargetRevisionId != "" && (input.StartIndex > 0 || input.EndIndex > 0) {
+		return mcp.NewToolResultError("Error: start_index/end_index cannot be combined with target_revision_id, since matches are found in the current revision. Limit the scope with section or table_index, or use revision_id instead."), nil
+	}
+
+	re, err := searchPattern(input.FindText, input.Regex, input.MatchCase)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error: Invalid regular expression: %v", err)), nil
+	}
+
+	doc, err := docsService.Documents.Get(input.DocumentID).Context(ctx).Do()
+	if err != nil {
+		return util.HandleGoogleAPIError("get document for find/replace", err), nil
+	}
+
+	if errResult := pinWriteControl(writeControl, doc); errResult != nil {
+		return errResult, nil
 	}
 
-	// If not replace all, we need a different approach
-	// The Google Docs API's ReplaceAllText always replaces all occurrences
-	// To replace only the first occurrence, we would need to:
-	// 1. Read the document
-	// 2. Find the first occurrence
-	// 3. Use replace text with specific indices
-	if !input.ReplaceAll {
-		// Get document content first
-		doc, err := docsService.Documents.Get(input.DocumentID).Context(ctx).Do()
+	// Without a scope the body, headers, footers and footnotes are searched;
+	// scopes are ranges of the body
+	segments := searchSegments(doc, "all")
+	scopeStart, scopeEnd := int64(0), int64(-1)
+	scopeName := "whole document"
+	switch {
+	case input.Section != "":
+		section, err := util.FindSection(doc, input.Section)
 		if err != nil {
-			return util.HandleGoogleAPIError("get document for find/replace", err), nil
+			return mcp.NewToolResultError(fmt.Sprintf("Error: %v", err)), nil
 		}
+		scopeStart, scopeEnd = section.StartIndex, section.EndIndex
+		scopeName = fmt.Sprintf("section '%s'", section.Text)
+	case input.StartIndex > 0 || input.EndIndex > 0:
+		scopeStart, scopeEnd = input.StartIndex, input.EndIndex
+		if scopeStart <= 0 {
+			scopeStart = 1
+		}
+		if scopeEnd <= 0 {
+			scopeEnd = util.BodyEndIndex(doc)
+		}
+		if scopeEnd <= scopeStart {
+			return mcp.NewToolResultError("Error: end_index must be greater than start_index."), nil
+		}
+		scopeName = fmt.Sprintf("range %d-%d", scopeStart, scopeEnd)
+	case input.TableIndex != nil:
+		table := findTableElement(doc, *input.TableIndex)
+		if table == nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Error: Table with index %d not found in document.", *input.TableIndex)), nil
+		}
+		scopeStart, scopeEnd = table.StartIndex, table.EndIndex
+		scopeName = fmt.Sprintf("table %d", *input.TableIndex)
+	}
+	if scopes > 0 {
+		segments = searchSegments(doc, "body")
+	}
+
+	// Collect the occurrences in search_document order
+	type occurrence struct {
+		segment searchSegment
+		textMap *util.TextMap
+		match   util.TextMatch
+	}
+	// A regular expression can match across paragraphs, so leave out the
+	// matches whose deletion would break a table or remove a newline the
+	// document needs
+	var found []occurrence
+	var skipped []SkippedMatch
+	for _, segment := range segments {
+		textMap := util.NewTextMapFromElements(segment.elements)
+		for _, match := range textMap.FindAllRegexp(re) {
+			if scopes > 0 && (match.StartIndex < scopeStart || match.EndIndex > scopeEnd) {
+				continue
+			}
+			if input.WholeWord && !isWholeWord(textMap.Text, match.TextStart, match.TextEnd) {
+				continue
+			}
+			if err := textMap.CheckDeletable(match.TextStart, match.TextEnd); err != nil {
+				skipped = append(skipped, SkippedMatch{
+					Segment:    segment.kind,
+					SegmentID:  segment.id,
+					StartIndex: match.StartIndex,
+					EndIndex:   match.EndIndex,
+					Text:       textMap.Text[match.TextStart:match.TextEnd],
+					Reason:     err.Error(),
+				})
+				continue
+			}
+			found = append(found, occurrence{segment: segment, textMap: textMap, match: match})
+		}
+	}
 
-		fullText := util.ExtractPlainText(doc)
-		
-		// Find the first occurrence
-		var findIndex int
-		if input.MatchCase {
-			findIndex = strings.Index(fullText, input.FindText)
+	output := FindReplaceOutput{
+		DocumentID: input.DocumentID,
+		Matches:    len(found),
+		DryRun:     input.DryRun,
+		Changes:    []ReplacementChange{},
+		Skipped:    skipped,
+	}
+
+	if len(found) == 0 {
+		var result strings.Builder
+		if len(output.Skipped) == 0 {
+			result.WriteString(fmt.Sprintf("Text '%s' not found in the %s.\n", input.FindText, scopeName))
 		} else {
-			findIndex = strings.Index(strings.ToLower(fullText), strings.ToLower(input.FindText))
+			result.WriteString(fmt.Sprintf("No match of '%s' in the %s can be replaced.\n", input.FindText, scopeName))
 		}
+		writeSkippedMatches(&result, output.Skipped)
+		return mcp.NewToolResultStructured(output, result.String()), nil
+	}
+
+	spec := input.Occurrences
+	if spec == "" && input.ReplaceAll {
+		spec = "all"
+	}
+	selected, err := parseOccurrences(spec, len(found))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error: %v", err)), nil
+	}
 
-		if findIndex == -1 {
-			return mcp.NewToolResultText(fmt.Sprintf("Text '%s' not found in the document.", input.FindText)), nil
+	// Work out where each replacement ends up once the earlier ones in the
+	// same segment have changed its length
+	shifts := make(map[string]int64)
+	for _, position := range selected {
+		occ := found[position]
+		match := occ.match
+
+		replacement := input.ReplaceText
+		if input.Regex {
+			replacement = string(re.ExpandString(nil, input.ReplaceText, occ.textMap.Text, match.Submatches))
 		}
 
-		// Replace only the first occurrence
-		requests = []*docs.Request{
-			{
-				DeleteContentRange: &docs.DeleteContentRangeRequest{
-					Range: &docs.Range{
-						StartIndex: int64(findIndex + 1), // +1 because Google Docs uses 1-based indexing
-						EndIndex:   int64(findIndex + 1 + len(input.FindText)),
-					},
-				},
-			},
-			{
-				InsertText: &docs.InsertTextRequest{
-					Location: &docs.Location{
-						Index: int64(findIndex + 1),
-					},
-					Text: input.ReplaceText,
+		key := occ.segment.kind + "/" + occ.segment.id
+		newStart := match.StartIndex + shifts[key]
+		newEnd := newStart + util.UTF16Length(replacement)
+		shifts[key] += newEnd - newStart - (match.EndIndex - match.StartIndex)
+
+		output.Changes = append(output.Changes, ReplacementChange{
+			Occurrence:    position + 1,
+			Segment:       occ.segment.kind,
+			SegmentID:     occ.segment.id,
+			StartIndex:    match.StartIndex,
+			EndIndex:      match.EndIndex,
+			Text:          occ.textMap.Text[match.TextStart:match.TextEnd],
+			Replacement:   replacement,
+			NewStartIndex: newStart,
+			NewEndIndex:   newEnd,
+			Context:       matchContext(occ.textMap.Text, match.TextStart, match.TextEnd, defaultSearchContext),
+		})
+	}
+	output.Occurrences = int64(len(output.Changes))
+	if len(output.Changes) == 1 {
+		output.StartIndex = output.Changes[0].StartIndex
+		output.EndIndex = output.Changes[0].EndIndex
+	}
+
+	if input.DryRun {
+		var result strings.Builder
+		result.WriteString(fmt.Sprintf("Dry run: no changes made.\n\nDocument ID: %s\nFound Text: '%s'\nScope: %s\nMatches: %d\nWould Replace: %d\n\n",
+			input.DocumentID, input.FindText, scopeName, output.Matches, output.Occurrences))
+		writeReplacementChanges(&result, output.Changes)
+		writeSkippedMatches(&result, output.Skipped)
+		return mcp.NewToolResultStructured(output, result.String()), nil
+	}
+
+	// Replace from the end of each segment backwards so the indices of the
+	// remaining occurrences stay valid. The replacement takes the style of the
+	// text it replaces rather than of the character before it.
+	var requests []*docs.Request
+	for i := len(selected) - 1; i >= 0; i-- {
+		occ := found[selected[i]]
+		change := output.Changes[i]
+		segmentID := occ.segment.id
+
+		deleteRequest := deleteRangeRequest(change.StartIndex, change.EndIndex)
+		deleteRequest.DeleteContentRange.Range.SegmentId = segmentID
+		requests = append(requests, deleteRequest)
+
+		if change.Replacement == "" {
+			continue
+		}
+
+		insertRequest := insertTextRequest(change.StartIndex, change.Replacement)
+		insertRequest.InsertText.Location.SegmentId = segmentID
+		requests = append(requests, insertRequest)
+
+		style := util.TextStyleAt(occ.segment.elements, change.StartIndex)
+		if style == nil {
+			style = &docs.TextStyle{}
+		}
+		requests = append(requests, &docs.Request{
+			UpdateTextStyle: &docs.UpdateTextStyleRequest{
+				Range: &docs.Range{
+					SegmentId:  segmentID,
+					StartIndex: change.StartIndex,
+					EndIndex:   change.StartIndex + util.UTF16Length(change.Replacement),
 				},
+				TextStyle: style,
+				Fields:    "*",
 			},
-		}
+		})
 	}
 
 	batchUpdateRequest := &docs.BatchUpdateDocumentRequest{
-		Requests: requests,
+		Requests:     requests,
+		WriteControl: writeControl,
 	}
 
 	response, err := docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
 	if err != nil {
-		return util.HandleGoogleAPIError("find and replace text", err), nil
+		return handleWriteError(ctx, "find and replace text", input.DocumentID, writeControl, err), nil
+	}
+	output.RevisionID = revisionAfter(response)
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Find and replace completed successfully!\n\nDocument ID: %s\nFound Text: '%s'\nReplacement Text: '%s'\nScope: %s\nMatches: %d\nReplaced: %d\nMatch Case: %t\n",
+		input.DocumentID, input.FindText, input.ReplaceText, scopeName, output.Matches, output.Occurrences, input.MatchCase))
+	if output.RevisionID != "" {
+		result.WriteString(fmt.Sprintf("Revision ID: %s\n", output.RevisionID))
+	}
+	result.WriteString("\n")
+	writeReplacementChanges(&result, output.Changes)
+	writeSkippedMatches(&result, output.Skipped)
+
+	return mcp.NewToolResultStructured(output, result.String()), nil
+}
+
+// parseOccurrences returns the positions, counting from 0, of the occurrences
+// selected by spec out of count: first, last, all, a number, or a
+// comma-separated list of numbers counting from 1
+func parseOccurrences(spec string, count int) ([]int, error) {
+	switch strings.ToLower(strings.TrimSpace(spec)) {
+	case "", "first":
+		return []int{0}, nil
+	case "last":
+		return []int{count - 1}, nil
+	case "all":
+		positions := make([]int, count)
+		for i := range positions {
+			positions[i] = i
+		}
+		return positions, nil
 	}
 
-	replaceCount := "all occurrences"
-	if !input.ReplaceAll {
-		replaceCount = "first occurrence"
+	selected := make(map[int]bool)
+	for _, part := range strings.Split(spec, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid occurrences '%s'. Use 'first', 'last', 'all', a number, or a comma-separated list of numbers", spec)
+		}
+		if n > count {
+			return nil, fmt.Errorf("occurrence %d requested but only %d found", n, count)
+		}
+		selected[n-1] = true
 	}
 
-	result := fmt.Sprintf("Find and replace completed successfully!\n\nDocument ID: %s\nFound Text: '%s'\nReplacement Text: '%s'\nReplaced: %s\nMatch Case: %t\nRevision ID: %s",
-		input.DocumentID, input.FindText, input.ReplaceText, replaceCount, input.MatchCase, response.DocumentId)
+	positions := make([]int, 0, len(selected))
+	for position := range selected {
+		positions = append(positions, position)
+	}
+	sort.Ints(positions)
+	return positions, nil
+}
 
-	return mcp.NewToolResultText(result), nil
+// writeReplacementChanges lists the replacements of a find and replace
+func writeReplacementChanges(sb *strings.Builder, changes []ReplacementChange) {
+	for _, change := range changes {
+		location := ""
+		if change.Segment != "body" {
+			location = fmt.Sprintf(" in %s %s", change.Segment, change.SegmentID)
+		}
+		sb.WriteString(fmt.Sprintf("%d. Range: %d-%d%s '%s' -> '%s'\n", change.Occurrence, change.StartIndex, change.EndIndex, location, change.Text, change.Replacement))
+		sb.WriteString(fmt.Sprintf("   %s\n", change.Context))
+	}
+}
+
+// writeSkippedMatches lists the matches a find and replace left alone
+func writeSkippedMatches(sb *strings.Builder, skipped []SkippedMatch) {
+	if len(skipped) == 0 {
+		return
+	}
+	sb.WriteString(fmt.Sprintf("\nSkipped %d match(es) that cannot be replaced:\n", len(skipped)))
+	for _, match := range skipped {
+		location := ""
+		if match.Segment != "body" {
+			location = fmt.Sprintf(" in %s %s", match.Segment, match.SegmentID)
+		}
+		sb.WriteString(fmt.Sprintf("- Range: %d-%d%s %q: %s\n", match.StartIndex, match.EndIndex, location, match.Text, match.Reason))
+	}
 }
@@ -3,6 +3,7 @@ package tools
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/hdbrzgr/docs-mcp/services"
@@ -11,20 +12,27 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	"google.golang.org/api/docs/v1"
 	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
 )
 
 // Input types for document tools
 type CreateDocumentInput struct {
-	Title string `json:"title" validate:"required"`
+	Title          string `json:"title" validate:"required"`
+	ParentFolderID string `json:"parent_folder_id,omitempty"`
 }
 
 type GetDocumentInput struct {
 	DocumentID string `json:"document_id" validate:"required"`
+	PaginationInput
 }
 
 type ListDocumentsInput struct {
-	Query    string `json:"query,omitempty"`
-	MaxCount int64  `json:"max_count,omitempty"`
+	Query     string `json:"query,omitempty"`
+	MaxCount  int64  `json:"max_count,omitempty"`
+	PageToken string `json:"page_token,omitempty"`
+	OrderBy   string `json:"order_by,omitempty"` // modifiedTime, name, viewedByMeTime, each optionally followed by desc
+	Fields    string `json:"fields,omitempty"`   // Comma-separated Drive file fields
+	Compact   bool   `json:"compact,omitempty"`
 }
 
 type DeleteDocumentInput struct {
@@ -32,8 +40,9 @@ type DeleteDocumentInput struct {
 }
 
 type CopyDocumentInput struct {
-	DocumentID string `json:"document_id" validate:"required"`
-	NewTitle   string `json:"new_title" validate:"required"`
+	DocumentID     string `json:"document_id" validate:"required"`
+	NewTitle       string `json:"new_title" validate:"required"`
+	ParentFolderID string `json:"parent_folder_id,omitempty"`
 }
 
 type ShareDocumentInput struct {
@@ -43,26 +52,81 @@ type ShareDocumentInput struct {
 	Type       string `json:"type,omitempty"` // user, group, domain, anyone
 }
 
+type ExportDocumentInput struct {
+	DocumentID string `json:"document_id" validate:"required"`
+	Format     string `json:"format,omitempty"` // pdf, docx, odt, rtf, epub, txt, html, md
+}
+
+// Output types for document tools
+type DocumentOutput struct {
+	DocumentID string `json:"document_id"`
+	Title      string `json:"title"`
+	RevisionID string `json:"revision_id,omitempty"`
+	URL        string `json:"url"`
+}
+
+type GetDocumentOutput struct {
+	DocumentOutput
+	EndIndex int64      `json:"end_index" jsonschema_description:"End index of the document body"`
+	Text     string     `json:"text" jsonschema_description:"Plain text content of the page"`
+	Page     PageOutput `json:"page"`
+}
+
+type ListDocumentsOutput struct {
+	Documents     []DriveFileOutput `json:"documents"`
+	Count         int               `json:"count"`
+	NextPageToken string            `json:"next_page_token,omitempty" jsonschema_description:"Pass as page_token to list the next page; absent on the last page"`
+}
+
+type DeleteDocumentOutput struct {
+	DocumentID string `json:"document_id"`
+	Trashed    bool   `json:"trashed"`
+}
+
+type CopyDocumentOutput struct {
+	SourceDocumentID string `json:"source_document_id"`
+	DocumentID       string `json:"document_id"`
+	Title            string `json:"title"`
+	URL              string `json:"url"`
+}
+
+type ShareDocumentOutput struct {
+	DocumentID   string `json:"document_id"`
+	PermissionID string `json:"permission_id"`
+	Email        string `json:"email"`
+	Role         string `json:"role"`
+	Type         string `json:"type"`
+}
+
 func RegisterDocumentTools(s *server.MCPServer) {
 	// Create document tool
 	createDocTool := mcp.NewTool("create_document",
 		mcp.WithDescription("Create a new Google Docs document with the specified title"),
 		mcp.WithString("title", mcp.Required(), mcp.Description("The title of the new document")),
+		mcp.WithString("parent_folder_id", mcp.Description("ID of the Drive folder to create the document in (default: root of My Drive)")),
+		mcp.WithOutputSchema[DocumentOutput](),
 	)
 	s.AddTool(createDocTool, mcp.NewTypedToolHandler(createDocumentHandler))
 
 	// Get document tool
 	getDocTool := mcp.NewTool("get_document",
-		mcp.WithDescription("Retrieve detailed information about a specific Google Docs document including its content, structure, and metadata"),
+		mcp.WithDescription("Retrieve detailed information about a specific Google Docs document including its content, structure, and metadata. Large documents are returned page by page: pass the returned next_cursor to read the next page"),
 		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the Google Docs document")),
+		withPagination(),
+		mcp.WithOutputSchema[GetDocumentOutput](),
 	)
 	s.AddTool(getDocTool, mcp.NewTypedToolHandler(getDocumentHandler))
 
 	// List documents tool
 	listDocsTool := mcp.NewTool("list_documents",
-		mcp.WithDescription("List Google Docs documents accessible to the authenticated user. Can filter by query and limit results"),
+		mcp.WithDescription("List Google Docs documents accessible to the authenticated user. Can filter by query, sort, and page through all results with next_page_token"),
 		mcp.WithString("query", mcp.Description("Search query to filter documents (e.g., 'name contains \"report\"', 'modifiedTime > \"2023-01-01\"')")),
-		mcp.WithNumber("max_count", mcp.Description("Maximum number of documents to return (default: 10, max: 100)")),
+		mcp.WithNumber("max_count", mcp.Description("Maximum number of documents to return per page (default: 10, max: 100)")),
+		mcp.WithString("page_token", mcp.Description("The next_page_token returned by the previous page, to continue the listing")),
+		mcp.WithString("order_by", mcp.Description("Sort order: 'modifiedTime', 'name', or 'viewedByMeTime', optionally followed by ' desc'; several can be separated by commas (e.g., 'modifiedTime desc,name')")),
+		mcp.WithString("fields", mcp.Description("Comma-separated file fields to return besides the ID: "+strings.Join(listDocumentFields, ", ")+" (default: all but viewedByMeTime, or name, modifiedTime and owners in compact mode)")),
+		mcp.WithBoolean("compact", mcp.Description("Show one line per document (default: false)")),
+		mcp.WithOutputSchema[ListDocumentsOutput](),
 	)
 	s.AddTool(listDocsTool, mcp.NewTypedToolHandler(listDocumentsHandler))
 
@@ -70,6 +134,7 @@ func RegisterDocumentTools(s *server.MCPServer) {
 	deleteDocTool := mcp.NewTool("delete_document",
 		mcp.WithDescription("Move a Google Docs document to trash. The document can be restored from trash if needed"),
 		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document to delete")),
+		mcp.WithOutputSchema[DeleteDocumentOutput](),
 	)
 	s.AddTool(deleteDocTool, mcp.NewTypedToolHandler(deleteDocumentHandler))
 
@@ -78,6 +143,8 @@ func RegisterDocumentTools(s *server.MCPServer) {
 		mcp.WithDescription("Create a copy of an existing Google Docs document with a new title"),
 		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document to copy")),
 		mcp.WithString("new_title", mcp.Required(), mcp.Description("The title for the copied document")),
+		mcp.WithString("parent_folder_id", mcp.Description("ID of the Drive folder to put the copy in (default: the folder of the original)")),
+		mcp.WithOutputSchema[CopyDocumentOutput](),
 	)
 	s.AddTool(copyDocTool, mcp.NewTypedToolHandler(copyDocumentHandler))
 
@@ -88,13 +155,49 @@ func RegisterDocumentTools(s *server.MCPServer) {
 		mcp.WithString("email", mcp.Required(), mcp.Description("Email address of the user or group to share with")),
 		mcp.WithString("role", mcp.Description("Permission role: 'reader', 'writer', or 'commenter' (default: 'reader')")),
 		mcp.WithString("type", mcp.Description("Type of permission: 'user', 'group', 'domain', or 'anyone' (default: 'user')")),
+		mcp.WithOutputSchema[ShareDocumentOutput](),
 	)
 	s.AddTool(shareDocTool, mcp.NewTypedToolHandler(shareDocumentHandler))
+
+	// Export document tool
+	exportDocTool := mcp.NewTool("export_document",
+		mcp.WithDescription("Download the current version of a Google Docs document in various formats. Text formats (txt, html, md) are returned inline; binary formats are returned as an embedded resource, or saved to the server's export directory when DOCS_EXPORT_DIR is set"),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document to export")),
+		mcp.WithString("format", mcp.Description("Export format: 'pdf', 'docx', 'odt', 'rtf', 'epub', 'txt', 'html', or 'md' (default: 'pdf')")),
+		mcp.WithOutputSchema[ExportOutput](),
+	)
+	s.AddTool(exportDocTool, mcp.NewTypedToolHandler(exportDocumentHandler))
 }
 
 func createDocumentHandler(ctx context.Context, request mcp.CallToolRequest, input CreateDocumentInput) (*mcp.CallToolResult, error) {
 	docsService := services.GoogleDocsClient()
 
+	// The Docs API always creates documents in the root folder, so documents
+	// for another folder are created through Drive
+	if input.ParentFolderID != "" {
+		driveService := services.GoogleDriveClient()
+
+		createdFile, err := driveService.Files.Create(&drive.File{
+			Name:     input.Title,
+			MimeType: googleDocsMimeType,
+			Parents:  []string{input.ParentFolderID},
+		}).Context(ctx).Do()
+		if err != nil {
+			return util.HandleGoogleAPIError("create document", err), nil
+		}
+
+		result := fmt.Sprintf("Document created successfully!\n\nTitle: %s\nDocument ID: %s\nFolder ID: %s\nURL: %s",
+			createdFile.Name, createdFile.Id, input.ParentFolderID, documentURL(createdFile.Id))
+
+		output := DocumentOutput{
+			DocumentID: createdFile.Id,
+			Title:      createdFile.Name,
+			URL:        documentURL(createdFile.Id),
+		}
+
+		return mcp.NewToolResultStructured(output, result), nil
+	}
+
 	// Create a new document
 	doc := &docs.Document{
 		Title: input.Title,
@@ -108,7 +211,14 @@ func createDocumentHandler(ctx context.Context, request mcp.CallToolRequest, inp
 	result := fmt.Sprintf("Document created successfully!\n\nTitle: %s\nDocument ID: %s\nURL: https://docs.google.com/document/d/%s/edit",
 		createdDoc.Title, createdDoc.DocumentId, createdDoc.DocumentId)
 
-	return mcp.NewToolResultText(result), nil
+	output := DocumentOutput{
+		DocumentID: createdDoc.DocumentId,
+		Title:      createdDoc.Title,
+		RevisionID: createdDoc.RevisionId,
+		URL:        documentURL(createdDoc.DocumentId),
+	}
+
+	return mcp.NewToolResultStructured(output, result), nil
 }
 
 func getDocumentHandler(ctx context.Context, request mcp.CallToolRequest, input GetDocumentInput) (*mcp.CallToolResult, error) {
@@ -119,17 +229,43 @@ func getDocumentHandler(ctx context.Context, request mcp.CallToolRequest, input
 		return util.HandleGoogleAPIError("get document", err), nil
 	}
 
+	// Pages of the formatted document always hold whole paragraphs and tables
+	textMap := util.NewTextMap(doc)
+	page, errResult := readPage(doc, textMap, 1, util.BodyEndIndex(doc), input.PaginationInput, true)
+	if errResult != nil {
+		return errResult, nil
+	}
+
 	// Format the document using the utility function
-	formattedDoc := util.FormatGoogleDoc(doc)
+	var formattedDoc string
+	if page.StartIndex <= page.RangeStartIndex && page.NextCursor == "" {
+		formattedDoc = util.FormatGoogleDoc(doc)
+	} else {
+		formattedDoc = fmt.Sprintf("Document ID: %s\nTitle: %s\n\n--- Document Content ---\n%s\nRevision ID: %s\n",
+			doc.DocumentId, doc.Title, util.FormatStructuralElements(sectionElements(doc, page.StartIndex, page.EndIndex)), doc.RevisionId)
+	}
+	formattedDoc = pageHeader(page) + "\n" + formattedDoc
+
+	output := GetDocumentOutput{
+		DocumentOutput: DocumentOutput{
+			DocumentID: doc.DocumentId,
+			Title:      doc.Title,
+			RevisionID: doc.RevisionId,
+			URL:        documentURL(doc.DocumentId),
+		},
+		EndIndex: util.BodyEndIndex(doc),
+		Text:     textMap.Slice(page.StartIndex, page.EndIndex),
+		Page:     page,
+	}
 
-	return mcp.NewToolResultText(formattedDoc), nil
+	return mcp.NewToolResultStructured(output, formattedDoc), nil
 }
 
 func listDocumentsHandler(ctx context.Context, request mcp.CallToolRequest, input ListDocumentsInput) (*mcp.CallToolResult, error) {
 	driveService := services.GoogleDriveClient()
 
 	// Build the query
-	query := "mimeType='application/vnd.google-apps.document'"
+	query := fmt.Sprintf("mimeType='%s'", googleDocsMimeType)
 	if input.Query != "" {
 		query += " and " + input.Query
 	}
@@ -143,31 +279,115 @@ func listDocumentsHandler(ctx context.Context, request mcp.CallToolRequest, inpu
 		maxCount = 100
 	}
 
+	orderBy, err := listDocumentsOrder(input.OrderBy)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error: %v", err)), nil
+	}
+
+	fields, err := listDocumentsFields(input.Fields, input.Compact)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error: %v", err)), nil
+	}
+
 	// List documents
-	filesList, err := driveService.Files.List().
+	call := driveService.Files.List().
 		Q(query).
 		PageSize(maxCount).
-		Fields("files(id,name,mimeType,createdTime,modifiedTime,owners,size,webViewLink)").
-		Context(ctx).
-		Do()
+		Fields(googleapi.Field(fmt.Sprintf("nextPageToken,files(%s)", fields)))
+	if orderBy != "" {
+		call = call.OrderBy(orderBy)
+	}
+	if input.PageToken != "" {
+		call = call.PageToken(input.PageToken)
+	}
 
+	filesList, err := call.Context(ctx).Do()
 	if err != nil {
 		return util.HandleGoogleAPIError("list documents", err), nil
 	}
 
+	output := ListDocumentsOutput{
+		Documents:     []DriveFileOutput{},
+		Count:         len(filesList.Files),
+		NextPageToken: filesList.NextPageToken,
+	}
+	for _, file := range filesList.Files {
+		output.Documents = append(output.Documents, driveFileOutput(file))
+	}
+
 	if len(filesList.Files) == 0 {
-		return mcp.NewToolResultText("No documents found matching the criteria."), nil
+		return mcp.NewToolResultStructured(output, "No documents found matching the criteria."), nil
 	}
 
 	var result strings.Builder
 	result.WriteString(fmt.Sprintf("Found %d Google Docs documents:\n\n", len(filesList.Files)))
 
 	for i, file := range filesList.Files {
+		if input.Compact {
+			result.WriteString(fmt.Sprintf("%d. %s\n", i+1, util.FormatDriveFileCompact(file)))
+			continue
+		}
 		result.WriteString(fmt.Sprintf("%d. %s\n", i+1, util.FormatDriveFile(file)))
 		result.WriteString("\n")
 	}
 
-	return mcp.NewToolResultText(result.String()), nil
+	if output.NextPageToken != "" {
+		if input.Compact {
+			result.WriteString("\n")
+		}
+		result.WriteString(fmt.Sprintf("More documents available. Next Page Token: %s\n", output.NextPageToken))
+	}
+
+	return mcp.NewToolResultStructured(output, result.String()), nil
+}
+
+// listDocumentFields are the Drive file fields list_documents can return
+var listDocumentFields = []string{"name", "mimeType", "createdTime", "modifiedTime", "viewedByMeTime", "owners", "size", "webViewLink"}
+
+// listDocumentsFields validates the requested file fields and returns them as
+// a Drive field list, always including the ID
+func listDocumentsFields(value string, compact bool) (string, error) {
+	if value == "" {
+		if compact {
+			return "id,name,modifiedTime,owners", nil
+		}
+		return "id,name,mimeType,createdTime,modifiedTime,owners,size,webViewLink", nil
+	}
+
+	fields := []string{"id"}
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" || field == "id" {
+			continue
+		}
+		if !slices.Contains(listDocumentFields, field) {
+			return "", fmt.Errorf("invalid field '%s'. Must be one of: %s", field, strings.Join(listDocumentFields, ", "))
+		}
+		fields = append(fields, field)
+	}
+	return strings.Join(fields, ","), nil
+}
+
+// listDocumentsOrder validates a sort order of one or more sort keys, each
+// optionally followed by desc
+func listDocumentsOrder(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	var keys []string
+	for _, part := range strings.Split(value, ",") {
+		words := strings.Fields(part)
+		valid := len(words) == 1 || (len(words) == 2 && strings.EqualFold(words[1], "desc"))
+		if valid {
+			valid = slices.Contains([]string{"modifiedTime", "name", "viewedByMeTime"}, words[0])
+		}
+		if !valid {
+			return "", fmt.Errorf("invalid order_by '%s'. Use 'modifiedTime', 'name', or 'viewedByMeTime', optionally followed by ' desc'", strings.TrimSpace(part))
+		}
+		keys = append(keys, strings.Join(words, " "))
+	}
+	return strings.Join(keys, ","), nil
 }
 
 func deleteDocumentHandler(ctx context.Context, request mcp.CallToolRequest, input DeleteDocumentInput) (*mcp.CallToolResult, error) {
@@ -185,17 +405,19 @@ func deleteDocumentHandler(ctx context.Context, request mcp.CallToolRequest, inp
 	result := fmt.Sprintf("Document moved to trash successfully!\n\nDocument ID: %s\n\nNote: The document can be restored from Google Drive trash if needed.",
 		input.DocumentID)
 
-	return mcp.NewToolResultText(result), nil
+	output := DeleteDocumentOutput{
+		DocumentID: input.DocumentID,
+		Trashed:    true,
+	}
+
+	return mcp.NewToolResultStructured(output, result), nil
 }
 
 func copyDocumentHandler(ctx context.Context, request mcp.CallToolRequest, input CopyDocumentInput) (*mcp.CallToolResult, error) {
 	driveService := services.GoogleDriveClient()
 
 	// Copy the document
-	copiedFile, err := driveService.Files.Copy(input.DocumentID, &drive.File{
-		Name: input.NewTitle,
-	}).Context(ctx).Do()
-
+	copiedFile, err := copyDocument(ctx, driveService, input.DocumentID, input.NewTitle, input.ParentFolderID)
 	if err != nil {
 		return util.HandleGoogleAPIError("copy document", err), nil
 	}
@@ -203,31 +425,76 @@ func copyDocumentHandler(ctx context.Context, request mcp.CallToolRequest, input
 	result := fmt.Sprintf("Document copied successfully!\n\nOriginal Document ID: %s\nNew Document ID: %s\nNew Title: %s\nURL: https://docs.google.com/document/d/%s/edit",
 		input.DocumentID, copiedFile.Id, copiedFile.Name, copiedFile.Id)
 
-	return mcp.NewToolResultText(result), nil
+	output := CopyDocumentOutput{
+		SourceDocumentID: input.DocumentID,
+		DocumentID:       copiedFile.Id,
+		Title:            copiedFile.Name,
+		URL:              documentURL(copiedFile.Id),
+	}
+
+	return mcp.NewToolResultStructured(output, result), nil
 }
 
 func shareDocumentHandler(ctx context.Context, request mcp.CallToolRequest, input ShareDocumentInput) (*mcp.CallToolResult, error) {
 	driveService := services.GoogleDriveClient()
 
+	role, permissionType, errResult := shareSettings(input.Role, input.Type)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	// Add the permission
+	createdPermission, err := shareDocument(ctx, driveService, input.DocumentID, input.Email, role, permissionType)
+	if err != nil {
+		return util.HandleGoogleAPIError("share document", err), nil
+	}
+
+	result := fmt.Sprintf("Document shared successfully!\n\nDocument ID: %s\nShared with: %s\nRole: %s\nPermission ID: %s",
+		input.DocumentID, input.Email, role, createdPermission.Id)
+
+	output := ShareDocumentOutput{
+		DocumentID:   input.DocumentID,
+		PermissionID: createdPermission.Id,
+		Email:        input.Email,
+		Role:         role,
+		Type:         permissionType,
+	}
+
+	return mcp.NewToolResultStructured(output, result), nil
+}
+
+// copyDocument copies a document under a new title, into a folder when
+// parentFolderID is set and otherwise next to the original
+func copyDocument(ctx context.Context, driveService *drive.Service, documentID, title, parentFolderID string) (*drive.File, error) {
+	copyFile := &drive.File{
+		Name: title,
+	}
+	if parentFolderID != "" {
+		copyFile.Parents = []string{parentFolderID}
+	}
+
+	return driveService.Files.Copy(documentID, copyFile).Context(ctx).Do()
+}
+
+// shareSettings applies the defaults to a sharing role and permission type and
+// validates them. A non-nil result is an error to return.
+func shareSettings(role, permissionType string) (string, string, *mcp.CallToolResult) {
 	// Set default values
-	role := input.Role
 	if role == "" {
 		role = "reader"
 	}
-
-	permissionType := input.Type
 	if permissionType == "" {
 		permissionType = "user"
 	}
 
 	// Validate role
 	validRoles := map[string]bool{
-		"reader":     true,
-		"writer":     true,
-		"commenter":  true,
+		"reader":    true,
+		"writer":    true,
+		"commenter": true,
 	}
 	if !validRoles[role] {
-		return mcp.NewToolResultText("Error: Invalid role. Must be 'reader', 'writer', or 'commenter'."), nil
+		return "", "", mcp.NewToolResultError("Error: Invalid role. Must be 'reader', 'writer', or 'commenter'.")
 	}
 
 	// Validate type
@@ -238,28 +505,67 @@ func shareDocumentHandler(ctx context.Context, request mcp.CallToolRequest, inpu
 		"anyone": true,
 	}
 	if !validTypes[permissionType] {
-		return mcp.NewToolResultText("Error: Invalid type. Must be 'user', 'group', 'domain', or 'anyone'."), nil
+		return "", "", mcp.NewToolResultError("Error: Invalid type. Must be 'user', 'group', 'domain', or 'anyone'.")
 	}
 
-	// Create permission
+	return role, permissionType, nil
+}
+
+// shareDocument grants a permission on a document and notifies the recipient
+func shareDocument(ctx context.Context, driveService *drive.Service, documentID, email, role, permissionType string) (*drive.Permission, error) {
 	permission := &drive.Permission{
 		Role:         role,
 		Type:         permissionType,
-		EmailAddress: input.Email,
+		EmailAddress: email,
 	}
 
-	// Add the permission
-	createdPermission, err := driveService.Permissions.Create(input.DocumentID, permission).
+	return driveService.Permissions.Create(documentID, permission).
 		SendNotificationEmail(true).
 		Context(ctx).
 		Do()
+}
+
+func exportDocumentHandler(ctx context.Context, request mcp.CallToolRequest, input ExportDocumentInput) (*mcp.CallToolResult, error) {
+	driveService := services.GoogleDriveClient()
+
+	// Set default format if not provided
+	formatName := input.Format
+	if formatName == "" {
+		formatName = "pdf"
+	}
+
+	format, ok := exportFormats[formatName]
+	if !ok {
+		return mcp.NewToolResultError(fmt.Sprintf("Error: Invalid format. Must be one of: %s", exportFormatNames())), nil
+	}
+
+	file, err := driveService.Files.Get(input.DocumentID).
+		Fields("name,mimeType").
+		Context(ctx).
+		Do()
 
 	if err != nil {
-		return util.HandleGoogleAPIError("share document", err), nil
+		return util.HandleGoogleAPIError("get document info for export", err), nil
 	}
 
-	result := fmt.Sprintf("Document shared successfully!\n\nDocument ID: %s\nShared with: %s\nRole: %s\nPermission ID: %s",
-		input.DocumentID, input.Email, role, createdPermission.Id)
+	if file.MimeType != googleDocsMimeType {
+		return mcp.NewToolResultError(fmt.Sprintf("Error: File is not a Google Docs document (MIME type: %s).", file.MimeType)), nil
+	}
+
+	content, err := exportDocument(ctx, input.DocumentID, format.MimeType)
+	if err != nil {
+		return util.HandleGoogleAPIError("export document", err), nil
+	}
+
+	header := fmt.Sprintf("Document exported successfully!\n\nDocument ID: %s\nTitle: %s\nFormat: %s\nMIME Type: %s\n",
+		input.DocumentID, file.Name, formatName, format.MimeType)
+	uri := fmt.Sprintf("gdoc://%s", input.DocumentID)
+
+	output := ExportOutput{
+		DocumentID: input.DocumentID,
+		Format:     formatName,
+		MimeType:   format.MimeType,
+	}
 
-	return mcp.NewToolResultText(result), nil
+	return exportResult(header, uri, exportFileName(file.Name), format, content, output), nil
 }
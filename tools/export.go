@@ -0,0 +1,162 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hdbrzgr/docs-mcp/services"
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/api/drive/v3"
+)
+
+const docxMimeType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+
+// exportDirEnv names the environment variable of the directory binary exports
+// are written to. When it is not set, binary exports are returned as embedded
+// resources.
+const exportDirEnv = "DOCS_EXPORT_DIR"
+
+// exportFormat describes a format documents and revisions can be exported to
+type exportFormat struct {
+	MimeType  string
+	Extension string
+	Text      bool // Returned inline as text content
+}
+
+// ExportOutput is the structured result of the export tools
+type ExportOutput struct {
+	DocumentID  string `json:"document_id"`
+	RevisionID  string `json:"revision_id,omitempty"`
+	Format      string `json:"format"`
+	MimeType    string `json:"mime_type"`
+	Size        int    `json:"size" jsonschema_description:"Size of the export in bytes"`
+	Content     string `json:"content,omitempty" jsonschema_description:"Exported content for text formats"`
+	SavedTo     string `json:"saved_to,omitempty" jsonschema_description:"Path of the exported file when the export directory is configured"`
+	ResourceURI string `json:"resource_uri,omitempty" jsonschema_description:"URI of the embedded resource holding a binary export"`
+}
+
+// exportFormats lists the supported export formats by name
+var exportFormats = map[string]exportFormat{
+	"pdf":  {MimeType: "application/pdf", Extension: "pdf"},
+	"docx": {MimeType: docxMimeType, Extension: "docx"},
+	"odt":  {MimeType: "application/vnd.oasis.opendocument.text", Extension: "odt"},
+	"rtf":  {MimeType: "application/rtf", Extension: "rtf"},
+	"epub": {MimeType: "application/epub+zip", Extension: "epub"},
+	"txt":  {MimeType: "text/plain", Extension: "txt", Text: true},
+	"html": {MimeType: "text/html", Extension: "html", Text: true},
+	"md":   {MimeType: "text/markdown", Extension: "md", Text: true},
+}
+
+// exportFormatNames returns the supported format names for error messages
+func exportFormatNames() string {
+	var names []string
+	for name := range exportFormats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// downloadRevision downloads the content of a revision in the given export format
+func downloadRevision(ctx context.Context, revision *drive.Revision, mimeType string) ([]byte, error) {
+	exportLink := revision.ExportLinks[mimeType]
+	if exportLink == "" {
+		return nil, fmt.Errorf("export format %s is not available for revision %s", mimeType, revision.Id)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, exportLink, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := services.GoogleHTTPClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("export request returned %s", resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// exportDocument exports the current content of a document in the given format
+func exportDocument(ctx context.Context, documentID, mimeType string) ([]byte, error) {
+	driveService := services.GoogleDriveClient()
+
+	resp, err := driveService.Files.Export(documentID, mimeType).Context(ctx).Download()
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return io.ReadAll(resp.Body)
+}
+
+// normalizeExportedText strips the byte order mark and Windows line endings
+// that Drive adds to plain text exports
+func normalizeExportedText(content []byte) string {
+	text := strings.TrimPrefix(string(content), "\ufeff")
+	return strings.ReplaceAll(text, "\r\n", "\n")
+}
+
+// exportResult returns exported content to the client. Text formats are
+// returned inline; binary formats are written to the export directory when one
+// is configured, and otherwise returned as an embedded blob resource. The
+// output is completed with the content or its location.
+func exportResult(header, uri, fileName string, format exportFormat, content []byte, output ExportOutput) *mcp.CallToolResult {
+	header += fmt.Sprintf("Size: %d bytes\n", len(content))
+	output.Size = len(content)
+
+	if format.Text {
+		output.Content = normalizeExportedText(content)
+		return mcp.NewToolResultStructured(output, header+"\n--- Content ---\n"+output.Content)
+	}
+
+	if dir := os.Getenv(exportDirEnv); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Error: Failed to create export directory: %v", err))
+		}
+
+		path := filepath.Join(dir, fileName+"."+format.Extension)
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Error: Failed to write export file: %v", err))
+		}
+
+		output.SavedTo = path
+		return mcp.NewToolResultStructured(output, header+fmt.Sprintf("Saved To: %s", path))
+	}
+
+	output.ResourceURI = uri
+	result := mcp.NewToolResultResource(header+"\nThe exported file is attached as an embedded resource.", mcp.BlobResourceContents{
+		URI:      uri,
+		MIMEType: format.MimeType,
+		Blob:     base64.StdEncoding.EncodeToString(content),
+	})
+	result.StructuredContent = output
+
+	return result
+}
+
+// exportFileName turns a document title into a safe file name
+func exportFileName(title string) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) || r < 0x20 {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(title))
+	if name == "" {
+		name = "document"
+	}
+	return name
+}
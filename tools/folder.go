@@ -0,0 +1,322 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hdbrzgr/docs-mcp/services"
+	"github.com/hdbrzgr/docs-mcp/util"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"google.golang.org/api/drive/v3"
+)
+
+// Drive MIME types of folders and Google Docs documents
+const (
+	folderMimeType     = "application/vnd.google-apps.folder"
+	googleDocsMimeType = "application/vnd.google-apps.document"
+)
+
+// Input types for folder tools
+type CreateFolderInput struct {
+	Name           string `json:"name" validate:"required"`
+	ParentFolderID string `json:"parent_folder_id,omitempty"`
+}
+
+type ListFolderInput struct {
+	FolderID      string `json:"folder_id,omitempty"` // Defaults to the root of My Drive
+	Depth         int    `json:"depth,omitempty"`
+	DocumentsOnly bool   `json:"documents_only,omitempty"`
+	MaxItems      int    `json:"max_items,omitempty"`
+}
+
+type MoveDocumentInput struct {
+	DocumentID          string `json:"document_id" validate:"required"`
+	FolderID            string `json:"folder_id" validate:"required"`
+	KeepExistingParents bool   `json:"keep_existing_parents,omitempty"`
+}
+
+// Output types for folder tools
+type FolderOutput struct {
+	FolderID       string `json:"folder_id"`
+	Name           string `json:"name"`
+	ParentFolderID string `json:"parent_folder_id,omitempty"`
+	URL            string `json:"url,omitempty"`
+}
+
+type ListFolderOutput struct {
+	FolderID  string       `json:"folder_id"`
+	Name      string       `json:"name"`
+	Items     []FolderItem `json:"items" jsonschema_description:"Folder contents in tree order, each item followed by the contents of its subfolders"`
+	Count     int          `json:"count"`
+	Truncated bool         `json:"truncated,omitempty" jsonschema_description:"Whether items were left out because of max_items"`
+}
+
+type FolderItem struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Type         string `json:"type" jsonschema_description:"folder, document or file"`
+	MimeType     string `json:"mime_type"`
+	ModifiedTime string `json:"modified_time,omitempty"`
+	URL          string `json:"url,omitempty"`
+	Parent       int    `json:"parent" jsonschema_description:"Position in the items list of the containing folder, or -1 for items directly in the listed folder"`
+	Depth        int    `json:"depth" jsonschema_description:"Nesting level below the listed folder, starting at 0"`
+}
+
+type MoveDocumentOutput struct {
+	DocumentID      string   `json:"document_id"`
+	Name            string   `json:"name"`
+	FolderID        string   `json:"folder_id"`
+	PreviousParents []string `json:"previous_parents"`
+	Parents         []string `json:"parents"`
+}
+
+func RegisterFolderTools(s *server.MCPServer) {
+	// Create folder tool
+	createFolderTool := mcp.NewTool("create_folder",
+		mcp.WithDescription("Create a folder in Google Drive to organize documents"),
+		mcp.WithString("name", mcp.Required(), mcp.Description("The name of the new folder")),
+		mcp.WithString("parent_folder_id", mcp.Description("ID of the folder to create it in (default: root of My Drive)")),
+		mcp.WithOutputSchema[FolderOutput](),
+	)
+	s.AddTool(createFolderTool, mcp.NewTypedToolHandler(createFolderHandler))
+
+	// List folder tool
+	listFolderTool := mcp.NewTool("list_folder",
+		mcp.WithDescription("List the contents of a Google Drive folder as a tree, including the contents of its subfolders down to the given depth"),
+		mcp.WithString("folder_id", mcp.Description("ID of the folder to list (default: root of My Drive)")),
+		mcp.WithNumber("depth", mcp.Description("How many levels of subfolders to expand; 1 lists only the folder itself (default: 1, max: 5)")),
+		mcp.WithBoolean("documents_only", mcp.Description("Only list Google Docs documents and folders (default: false)")),
+		mcp.WithNumber("max_items", mcp.Description("Maximum number of items to return (default: 200, max: 1000)")),
+		mcp.WithOutputSchema[ListFolderOutput](),
+	)
+	s.AddTool(listFolderTool, mcp.NewTypedToolHandler(listFolderHandler))
+
+	// Move document tool
+	moveDocumentTool := mcp.NewTool("move_document",
+		mcp.WithDescription("Move a Google Docs document into a Drive folder, taking it out of the folders it was in"),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document to move")),
+		mcp.WithString("folder_id", mcp.Required(), mcp.Description("ID of the destination folder, or 'root' for My Drive")),
+		mcp.WithBoolean("keep_existing_parents", mcp.Description("Add the folder without removing the document from its current folders (default: false)")),
+		mcp.WithOutputSchema[MoveDocumentOutput](),
+	)
+	s.AddTool(moveDocumentTool, mcp.NewTypedToolHandler(moveDocumentHandler))
+}
+
+func createFolderHandler(ctx context.Context, request mcp.CallToolRequest, input CreateFolderInput) (*mcp.CallToolResult, error) {
+	driveService := services.GoogleDriveClient()
+
+	folder := &drive.File{
+		Name:     input.Name,
+		MimeType: folderMimeType,
+	}
+	if input.ParentFolderID != "" {
+		folder.Parents = []string{input.ParentFolderID}
+	}
+
+	createdFolder, err := driveService.Files.Create(folder).
+		Fields("id,name,parents,webViewLink").
+		Context(ctx).
+		Do()
+	if err != nil {
+		return util.HandleGoogleAPIError("create folder", err), nil
+	}
+
+	output := FolderOutput{
+		FolderID: createdFolder.Id,
+		Name:     createdFolder.Name,
+		URL:      createdFolder.WebViewLink,
+	}
+	if len(createdFolder.Parents) > 0 {
+		output.ParentFolderID = createdFolder.Parents[0]
+	}
+
+	result := fmt.Sprintf("Folder created successfully!\n\nName: %s\nFolder ID: %s", output.Name, output.FolderID)
+	if output.ParentFolderID != "" {
+		result += fmt.Sprintf("\nParent Folder ID: %s", output.ParentFolderID)
+	}
+	if output.URL != "" {
+		result += fmt.Sprintf("\nURL: %s", output.URL)
+	}
+
+	return mcp.NewToolResultStructured(output, result), nil
+}
+
+func listFolderHandler(ctx context.Context, request mcp.CallToolRequest, input ListFolderInput) (*mcp.CallToolResult, error) {
+	driveService := services.GoogleDriveClient()
+
+	folderID := input.FolderID
+	if folderID == "" {
+		folderID = "root"
+	}
+
+	depth := input.Depth
+	if depth <= 0 {
+		depth = 1
+	}
+	if depth > 5 {
+		depth = 5
+	}
+
+	maxItems := input.MaxItems
+	if maxItems <= 0 {
+		maxItems = 200
+	}
+	if maxItems > 1000 {
+		maxItems = 1000
+	}
+
+	folder, err := driveService.Files.Get(folderID).Fields("id,name,mimeType").Context(ctx).Do()
+	if err != nil {
+		return util.HandleGoogleAPIError("get folder", err), nil
+	}
+	if folder.MimeType != folderMimeType {
+		return mcp.NewToolResultError(fmt.Sprintf("Error: '%s' is not a folder.", folder.Name)), nil
+	}
+
+	output := ListFolderOutput{
+		FolderID: folder.Id,
+		Name:     folder.Name,
+		Items:    []FolderItem{},
+	}
+
+	// Walk the folder depth first so each folder is followed by its contents
+	var walk func(id string, parent, level int) error
+	walk = func(id string, parent, level int) error {
+		query := fmt.Sprintf("%s in parents and trashed=false", driveQueryString(id))
+		if input.DocumentsOnly {
+			query += fmt.Sprintf(" and (mimeType='%s' or mimeType='%s')", folderMimeType, googleDocsMimeType)
+		}
+
+		call := driveService.Files.List().
+			Q(query).
+			OrderBy("folder,name").
+			PageSize(100).
+			Fields("nextPageToken,files(id,name,mimeType,modifiedTime,webViewLink)")
+
+		pageToken := ""
+		for {
+			if pageToken != "" {
+				call = call.PageToken(pageToken)
+			}
+			filesList, err := call.Context(ctx).Do()
+			if err != nil {
+				return err
+			}
+
+			for _, file := range filesList.Files {
+				if len(output.Items) == maxItems {
+					output.Truncated = true
+					return nil
+				}
+
+				position := len(output.Items)
+				output.Items = append(output.Items, folderItem(file, parent, level))
+
+				if file.MimeType == folderMimeType && level+1 < depth {
+					if err := walk(file.Id, position, level+1); err != nil {
+						return err
+					}
+					if output.Truncated {
+						return nil
+					}
+				}
+			}
+
+			pageToken = filesList.NextPageToken
+			if pageToken == "" {
+				return nil
+			}
+		}
+	}
+
+	if err := walk(folder.Id, -1, 0); err != nil {
+		return util.HandleGoogleAPIError("list folder", err), nil
+	}
+	output.Count = len(output.Items)
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Folder: %s\n\nFolder ID: %s\nItems: %d\n", folder.Name, folder.Id, output.Count))
+	if output.Truncated {
+		result.WriteString(fmt.Sprintf("Showing the first %d items\n", output.Count))
+	}
+
+	if output.Count == 0 {
+		result.WriteString("\nThe folder is empty.\n")
+		return mcp.NewToolResultStructured(output, result.String()), nil
+	}
+
+	result.WriteString("\n")
+	for _, item := range output.Items {
+		indent := strings.Repeat("  ", item.Depth)
+		name := item.Name
+		if item.Type == "folder" {
+			name += "/"
+		}
+		result.WriteString(fmt.Sprintf("%s- %s (%s, ID: %s)\n", indent, name, item.Type, item.ID))
+	}
+
+	return mcp.NewToolResultStructured(output, result.String()), nil
+}
+
+// folderItem converts a Drive file inside a listed folder to its structured form
+func folderItem(file *drive.File, parent, depth int) FolderItem {
+	item := FolderItem{
+		ID:           file.Id,
+		Name:         file.Name,
+		Type:         "file",
+		MimeType:     file.MimeType,
+		ModifiedTime: file.ModifiedTime,
+		URL:          file.WebViewLink,
+		Parent:       parent,
+		Depth:        depth,
+	}
+	switch file.MimeType {
+	case folderMimeType:
+		item.Type = "folder"
+	case googleDocsMimeType:
+		item.Type = "document"
+	}
+	return item
+}
+
+func moveDocumentHandler(ctx context.Context, request mcp.CallToolRequest, input MoveDocumentInput) (*mcp.CallToolResult, error) {
+	driveService := services.GoogleDriveClient()
+
+	file, err := driveService.Files.Get(input.DocumentID).Fields("id,name,parents").Context(ctx).Do()
+	if err != nil {
+		return util.HandleGoogleAPIError("get document for move", err), nil
+	}
+
+	call := driveService.Files.Update(input.DocumentID, &drive.File{}).
+		AddParents(input.FolderID).
+		Fields("id,name,parents")
+	if !input.KeepExistingParents && len(file.Parents) > 0 {
+		call = call.RemoveParents(strings.Join(file.Parents, ","))
+	}
+
+	movedFile, err := call.Context(ctx).Do()
+	if err != nil {
+		return util.HandleGoogleAPIError("move document", err), nil
+	}
+
+	output := MoveDocumentOutput{
+		DocumentID:      movedFile.Id,
+		Name:            movedFile.Name,
+		FolderID:        input.FolderID,
+		PreviousParents: file.Parents,
+		Parents:         movedFile.Parents,
+	}
+	if output.PreviousParents == nil {
+		output.PreviousParents = []string{}
+	}
+	if output.Parents == nil {
+		output.Parents = []string{}
+	}
+
+	result := fmt.Sprintf("Document moved successfully!\n\nDocument: %s\nDocument ID: %s\nFolder ID: %s\nPrevious Folders: %s\nCurrent Folders: %s",
+		output.Name, output.DocumentID, output.FolderID, strings.Join(output.PreviousParents, ", "), strings.Join(output.Parents, ", "))
+
+	return mcp.NewToolResultStructured(output, result), nil
+}
@@ -13,43 +13,58 @@ import (
 
 // Input types for formatting tools
 type FormatTextInput struct {
-	DocumentID string `json:"document_id" validate:"required"`
-	StartIndex int64  `json:"start_index" validate:"required"`
-	EndIndex   int64  `json:"end_index" validate:"required"`
-	Bold       *bool  `json:"bold,omitempty"`
-	Italic     *bool  `json:"italic,omitempty"`
-	Underline  *bool  `json:"underline,omitempty"`
-	FontSize   *int64 `json:"font_size,omitempty"`
-	FontFamily string `json:"font_family,omitempty"`
+	DocumentID       string       `json:"document_id" validate:"required"`
+	StartIndex       int64        `json:"start_index,omitempty"`
+	EndIndex         int64        `json:"end_index,omitempty"`
+	Bold             *bool        `json:"bold,omitempty"`
+	Italic           *bool        `json:"italic,omitempty"`
+	Underline        *bool        `json:"underline,omitempty"`
+	FontSize         *int64       `json:"font_size,omitempty"`
+	FontFamily       string       `json:"font_family,omitempty"`
+	Anchor           *util.Anchor `json:"anchor,omitempty"`
+	RevisionID       string       `json:"revision_id,omitempty"`
+	TargetRevisionID string       `json:"target_revision_id,omitempty"`
 }
 
 type SetTextColorInput struct {
-	DocumentID string `json:"document_id" validate:"required"`
-	StartIndex int64  `json:"start_index" validate:"required"`
-	EndIndex   int64  `json:"end_index" validate:"required"`
-	Color      string `json:"color" validate:"required"` // Hex color code (e.g., "#FF0000" for red)
+	DocumentID       string       `json:"document_id" validate:"required"`
+	StartIndex       int64        `json:"start_index,omitempty"`
+	EndIndex         int64        `json:"end_index,omitempty"`
+	Color            string       `json:"color" validate:"required"` // Hex color code (e.g., "#FF0000" for red)
+	Anchor           *util.Anchor `json:"anchor,omitempty"`
+	RevisionID       string       `json:"revision_id,omitempty"`
+	TargetRevisionID string       `json:"target_revision_id,omitempty"`
 }
 
 type SetBackgroundColorInput struct {
-	DocumentID string `json:"document_id" validate:"required"`
-	StartIndex int64  `json:"start_index" validate:"required"`
-	EndIndex   int64  `json:"end_index" validate:"required"`
-	Color      string `json:"color" validate:"required"` // Hex color code (e.g., "#FFFF00" for yellow)
+	DocumentID       string       `json:"document_id" validate:"required"`
+	StartIndex       int64        `json:"start_index,omitempty"`
+	EndIndex         int64        `json:"end_index,omitempty"`
+	Color            string       `json:"color" validate:"required"` // Hex color code (e.g., "#FFFF00" for yellow)
+	Anchor           *util.Anchor `json:"anchor,omitempty"`
+	RevisionID       string       `json:"revision_id,omitempty"`
+	TargetRevisionID string       `json:"target_revision_id,omitempty"`
 }
 
 type SetParagraphStyleInput struct {
-	DocumentID string `json:"document_id" validate:"required"`
-	StartIndex int64  `json:"start_index" validate:"required"`
-	EndIndex   int64  `json:"end_index" validate:"required"`
-	StyleType  string `json:"style_type" validate:"required"` // NORMAL_TEXT, HEADING_1, HEADING_2, etc.
-	Alignment  string `json:"alignment,omitempty"`            // START, CENTER, END, JUSTIFY
+	DocumentID       string       `json:"document_id" validate:"required"`
+	StartIndex       int64        `json:"start_index,omitempty"`
+	EndIndex         int64        `json:"end_index,omitempty"`
+	StyleType        string       `json:"style_type" validate:"required"` // NORMAL_TEXT, HEADING_1, HEADING_2, etc.
+	Alignment        string       `json:"alignment,omitempty"`            // START, CENTER, END, JUSTIFY
+	Anchor           *util.Anchor `json:"anchor,omitempty"`
+	RevisionID       string       `json:"revision_id,omitempty"`
+	TargetRevisionID string       `json:"target_revision_id,omitempty"`
 }
 
 type SetLineSpacingInput struct {
-	DocumentID string  `json:"document_id" validate:"required"`
-	StartIndex int64   `json:"start_index" validate:"required"`
-	EndIndex   int64   `json:"end_index" validate:"required"`
-	Spacing    float64 `json:"spacing" validate:"required"` // Line spacing (e.g., 1.0, 1.5, 2.0)
+	DocumentID       string       `json:"document_id" validate:"required"`
+	StartIndex       int64        `json:"start_index,omitempty"`
+	EndIndex         int64        `json:"end_index,omitempty"`
+	Spacing          float64      `json:"spacing" validate:"required"` // Line spacing (e.g., 1.0, 1.5, 2.0)
+	Anchor           *util.Anchor `json:"anchor,omitempty"`
+	RevisionID       string       `json:"revision_id,omitempty"`
+	TargetRevisionID string       `json:"target_revision_id,omitempty"`
 }
 
 func RegisterFormattingTools(s *server.MCPServer) {
@@ -57,13 +72,16 @@ func RegisterFormattingTools(s *server.MCPServer) {
 	formatTextTool := mcp.NewTool("format_text",
 		mcp.WithDescription("Apply text formatting (bold, italic, underline, font size, font family) to a range of text in a Google Docs document"),
 		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
-		mcp.WithNumber("start_index", mcp.Required(), mcp.Description("Start position of the text to format")),
-		mcp.WithNumber("end_index", mcp.Required(), mcp.Description("End position of the text to format")),
+		mcp.WithNumber("start_index", mcp.Description("Start position of the text to format")),
+		mcp.WithNumber("end_index", mcp.Description("End position of the text to format")),
 		mcp.WithBoolean("bold", mcp.Description("Apply bold formatting (true/false)")),
 		mcp.WithBoolean("italic", mcp.Description("Apply italic formatting (true/false)")),
 		mcp.WithBoolean("underline", mcp.Description("Apply underline formatting (true/false)")),
 		mcp.WithNumber("font_size", mcp.Description("Font size in points (e.g., 12, 14, 16)")),
 		mcp.WithString("font_family", mcp.Description("Font family name (e.g., 'Arial', 'Times New Roman', 'Calibri')")),
+		withAnchor(),
+		withWriteControl(),
+		mcp.WithOutputSchema[EditOutput](),
 	)
 	s.AddTool(formatTextTool, mcp.NewTypedToolHandler(formatTextHandler))
 
@@ -71,9 +89,12 @@ func RegisterFormattingTools(s *server.MCPServer) {
 	setTextColorTool := mcp.NewTool("set_text_color",
 		mcp.WithDescription("Set the text color for a range of text in a Google Docs document"),
 		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
-		mcp.WithNumber("start_index", mcp.Required(), mcp.Description("Start position of the text to color")),
-		mcp.WithNumber("end_index", mcp.Required(), mcp.Description("End position of the text to color")),
+		mcp.WithNumber("start_index", mcp.Description("Start position of the text to color")),
+		mcp.WithNumber("end_index", mcp.Description("End position of the text to color")),
 		mcp.WithString("color", mcp.Required(), mcp.Description("Hex color code (e.g., '#FF0000' for red, '#0000FF' for blue)")),
+		withAnchor(),
+		withWriteControl(),
+		mcp.WithOutputSchema[EditOutput](),
 	)
 	s.AddTool(setTextColorTool, mcp.NewTypedToolHandler(setTextColorHandler))
 
@@ -81,9 +102,12 @@ func RegisterFormattingTools(s *server.MCPServer) {
 	setBackgroundColorTool := mcp.NewTool("set_background_color",
 		mcp.WithDescription("Set the background color for a range of text in a Google Docs document"),
 		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
-		mcp.WithNumber("start_index", mcp.Required(), mcp.Description("Start position of the text to highlight")),
-		mcp.WithNumber("end_index", mcp.Required(), mcp.Description("End position of the text to highlight")),
+		mcp.WithNumber("start_index", mcp.Description("Start position of the text to highlight")),
+		mcp.WithNumber("end_index", mcp.Description("End position of the text to highlight")),
 		mcp.WithString("color", mcp.Required(), mcp.Description("Hex color code (e.g., '#FFFF00' for yellow, '#00FF00' for green)")),
+		withAnchor(),
+		withWriteControl(),
+		mcp.WithOutputSchema[EditOutput](),
 	)
 	s.AddTool(setBackgroundColorTool, mcp.NewTypedToolHandler(setBackgroundColorHandler))
 
@@ -91,10 +115,13 @@ func RegisterFormattingTools(s *server.MCPServer) {
 	setParagraphStyleTool := mcp.NewTool("set_paragraph_style",
 		mcp.WithDescription("Set paragraph style and alignment for a range of text in a Google Docs document"),
 		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
-		mcp.WithNumber("start_index", mcp.Required(), mcp.Description("Start position of the paragraph to style")),
-		mcp.WithNumber("end_index", mcp.Required(), mcp.Description("End position of the paragraph to style")),
+		mcp.WithNumber("start_index", mcp.Description("Start position of the paragraph to style")),
+		mcp.WithNumber("end_index", mcp.Description("End position of the paragraph to style")),
 		mcp.WithString("style_type", mcp.Required(), mcp.Description("Style type: 'NORMAL_TEXT', 'HEADING_1', 'HEADING_2', 'HEADING_3', 'HEADING_4', 'HEADING_5', 'HEADING_6', 'TITLE', 'SUBTITLE'")),
 		mcp.WithString("alignment", mcp.Description("Text alignment: 'START', 'CENTER', 'END', 'JUSTIFY'")),
+		withAnchor(),
+		withWriteControl(),
+		mcp.WithOutputSchema[EditOutput](),
 	)
 	s.AddTool(setParagraphStyleTool, mcp.NewTypedToolHandler(setParagraphStyleHandler))
 
@@ -102,9 +129,12 @@ func RegisterFormattingTools(s *server.MCPServer) {
 	setLineSpacingTool := mcp.NewTool("set_line_spacing",
 		mcp.WithDescription("Set line spacing for a range of text in a Google Docs document"),
 		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
-		mcp.WithNumber("start_index", mcp.Required(), mcp.Description("Start position of the text to adjust spacing")),
-		mcp.WithNumber("end_index", mcp.Required(), mcp.Description("End position of the text to adjust spacing")),
+		mcp.WithNumber("start_index", mcp.Description("Start position of the text to adjust spacing")),
+		mcp.WithNumber("end_index", mcp.Description("End position of the text to adjust spacing")),
 		mcp.WithNumber("spacing", mcp.Required(), mcp.Description("Line spacing value (e.g., 1.0 for single, 1.5 for 1.5x, 2.0 for double)")),
+		withAnchor(),
+		withWriteControl(),
+		mcp.WithOutputSchema[EditOutput](),
 	)
 	s.AddTool(setLineSpacingTool, mcp.NewTypedToolHandler(setLineSpacingHandler))
 }
@@ -112,8 +142,14 @@ func RegisterFormattingTools(s *server.MCPServer) {
 func formatTextHandler(ctx context.Context, request mcp.CallToolRequest, input FormatTextInput) (*mcp.CallToolResult, error) {
 	docsService := services.GoogleDocsClient()
 
-	if input.StartIndex >= input.EndIndex {
-		return mcp.NewToolResultText("Error: Start index must be less than end index."), nil
+	writeControl, errResult := newWriteControl(input.RevisionID, input.TargetRevisionID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	startIndex, endIndex, errResult := resolveRange(ctx, input.DocumentID, input.StartIndex, input.EndIndex, input.Anchor, writeControl)
+	if errResult != nil {
+		return errResult, nil
 	}
 
 	var requests []*docs.Request
@@ -156,8 +192,8 @@ func formatTextHandler(ctx context.Context, request mcp.CallToolRequest, input F
 		requests = append(requests, &docs.Request{
 			UpdateTextStyle: &docs.UpdateTextStyleRequest{
 				Range: &docs.Range{
-					StartIndex: input.StartIndex,
-					EndIndex:   input.EndIndex,
+					StartIndex: startIndex,
+					EndIndex:   endIndex,
 				},
 				TextStyle: textStyle,
 				Fields:    "*", // Update all specified fields
@@ -166,43 +202,57 @@ func formatTextHandler(ctx context.Context, request mcp.CallToolRequest, input F
 	}
 
 	if len(requests) == 0 {
-		return mcp.NewToolResultText("No formatting changes specified."), nil
+		return mcp.NewToolResultStructured(EditOutput{DocumentID: input.DocumentID}, "No formatting changes specified."), nil
 	}
 
 	batchUpdateRequest := &docs.BatchUpdateDocumentRequest{
-		Requests: requests,
+		Requests:     requests,
+		WriteControl: writeControl,
 	}
 
-	_, err := docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
+	response, err := docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
 	if err != nil {
-		return util.HandleGoogleAPIError("format text", err), nil
+		return handleWriteError(ctx, "format text", input.DocumentID, writeControl, err), nil
 	}
 
 	result := fmt.Sprintf("Text formatting applied successfully!\n\nDocument ID: %s\nRange: %d-%d\nFormatting changes applied.",
-		input.DocumentID, input.StartIndex, input.EndIndex)
+		input.DocumentID, startIndex, endIndex)
 
-	return mcp.NewToolResultText(result), nil
+	output := EditOutput{
+		DocumentID: input.DocumentID,
+		RevisionID: revisionAfter(response),
+		StartIndex: startIndex,
+		EndIndex:   endIndex,
+	}
+
+	return mcp.NewToolResultStructured(output, result), nil
 }
 
 func setTextColorHandler(ctx context.Context, request mcp.CallToolRequest, input SetTextColorInput) (*mcp.CallToolResult, error) {
 	docsService := services.GoogleDocsClient()
 
-	if input.StartIndex >= input.EndIndex {
-		return mcp.NewToolResultText("Error: Start index must be less than end index."), nil
+	writeControl, errResult := newWriteControl(input.RevisionID, input.TargetRevisionID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	startIndex, endIndex, errResult := resolveRange(ctx, input.DocumentID, input.StartIndex, input.EndIndex, input.Anchor, writeControl)
+	if errResult != nil {
+		return errResult, nil
 	}
 
 	// Parse hex color
 	color, err := parseHexColor(input.Color)
 	if err != nil {
-		return mcp.NewToolResultText(fmt.Sprintf("Error: Invalid color format. Use hex format like '#FF0000'. %v", err)), nil
+		return mcp.NewToolResultError(fmt.Sprintf("Error: Invalid color format. Use hex format like '#FF0000'. %v", err)), nil
 	}
 
 	requests := []*docs.Request{
 		{
 			UpdateTextStyle: &docs.UpdateTextStyleRequest{
 				Range: &docs.Range{
-					StartIndex: input.StartIndex,
-					EndIndex:   input.EndIndex,
+					StartIndex: startIndex,
+					EndIndex:   endIndex,
 				},
 				TextStyle: &docs.TextStyle{
 					ForegroundColor: &docs.OptionalColor{
@@ -215,39 +265,53 @@ func setTextColorHandler(ctx context.Context, request mcp.CallToolRequest, input
 	}
 
 	batchUpdateRequest := &docs.BatchUpdateDocumentRequest{
-		Requests: requests,
+		Requests:     requests,
+		WriteControl: writeControl,
 	}
 
-	_, err = docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
+	response, err := docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
 	if err != nil {
-		return util.HandleGoogleAPIError("set text color", err), nil
+		return handleWriteError(ctx, "set text color", input.DocumentID, writeControl, err), nil
 	}
 
 	result := fmt.Sprintf("Text color applied successfully!\n\nDocument ID: %s\nRange: %d-%d\nColor: %s",
-		input.DocumentID, input.StartIndex, input.EndIndex, input.Color)
+		input.DocumentID, startIndex, endIndex, input.Color)
+
+	output := EditOutput{
+		DocumentID: input.DocumentID,
+		RevisionID: revisionAfter(response),
+		StartIndex: startIndex,
+		EndIndex:   endIndex,
+	}
 
-	return mcp.NewToolResultText(result), nil
+	return mcp.NewToolResultStructured(output, result), nil
 }
 
 func setBackgroundColorHandler(ctx context.Context, request mcp.CallToolRequest, input SetBackgroundColorInput) (*mcp.CallToolResult, error) {
 	docsService := services.GoogleDocsClient()
 
-	if input.StartIndex >= input.EndIndex {
-		return mcp.NewToolResultText("Error: Start index must be less than end index."), nil
+	writeControl, errResult := newWriteControl(input.RevisionID, input.TargetRevisionID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	startIndex, endIndex, errResult := resolveRange(ctx, input.DocumentID, input.StartIndex, input.EndIndex, input.Anchor, writeControl)
+	if errResult != nil {
+		return errResult, nil
 	}
 
 	// Parse hex color
 	color, err := parseHexColor(input.Color)
 	if err != nil {
-		return mcp.NewToolResultText(fmt.Sprintf("Error: Invalid color format. Use hex format like '#FFFF00'. %v", err)), nil
+		return mcp.NewToolResultError(fmt.Sprintf("Error: Invalid color format. Use hex format like '#FFFF00'. %v", err)), nil
 	}
 
 	requests := []*docs.Request{
 		{
 			UpdateTextStyle: &docs.UpdateTextStyleRequest{
 				Range: &docs.Range{
-					StartIndex: input.StartIndex,
-					EndIndex:   input.EndIndex,
+					StartIndex: startIndex,
+					EndIndex:   endIndex,
 				},
 				TextStyle: &docs.TextStyle{
 					BackgroundColor: &docs.OptionalColor{
@@ -260,25 +324,39 @@ func setBackgroundColorHandler(ctx context.Context, request mcp.CallToolRequest,
 	}
 
 	batchUpdateRequest := &docs.BatchUpdateDocumentRequest{
-		Requests: requests,
+		Requests:     requests,
+		WriteControl: writeControl,
 	}
 
-	_, err = docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
+	response, err := docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
 	if err != nil {
-		return util.HandleGoogleAPIError("set background color", err), nil
+		return handleWriteError(ctx, "set background color", input.DocumentID, writeControl, err), nil
 	}
 
 	result := fmt.Sprintf("Background color applied successfully!\n\nDocument ID: %s\nRange: %d-%d\nColor: %s",
-		input.DocumentID, input.StartIndex, input.EndIndex, input.Color)
+		input.DocumentID, startIndex, endIndex, input.Color)
 
-	return mcp.NewToolResultText(result), nil
+	output := EditOutput{
+		DocumentID: input.DocumentID,
+		RevisionID: revisionAfter(response),
+		StartIndex: startIndex,
+		EndIndex:   endIndex,
+	}
+
+	return mcp.NewToolResultStructured(output, result), nil
 }
 
 func setParagraphStyleHandler(ctx context.Context, request mcp.CallToolRequest, input SetParagraphStyleInput) (*mcp.CallToolResult, error) {
 	docsService := services.GoogleDocsClient()
 
-	if input.StartIndex >= input.EndIndex {
-		return mcp.NewToolResultText("Error: Start index must be less than end index."), nil
+	writeControl, errResult := newWriteControl(input.RevisionID, input.TargetRevisionID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	startIndex, endIndex, errResult := resolveRange(ctx, input.DocumentID, input.StartIndex, input.EndIndex, input.Anchor, writeControl)
+	if errResult != nil {
+		return errResult, nil
 	}
 
 	// Validate style type
@@ -295,7 +373,7 @@ func setParagraphStyleHandler(ctx context.Context, request mcp.CallToolRequest,
 	}
 
 	if !validStyles[input.StyleType] {
-		return mcp.NewToolResultText("Error: Invalid style type. Must be one of: NORMAL_TEXT, HEADING_1, HEADING_2, HEADING_3, HEADING_4, HEADING_5, HEADING_6, TITLE, SUBTITLE"), nil
+		return mcp.NewToolResultError("Error: Invalid style type. Must be one of: NORMAL_TEXT, HEADING_1, HEADING_2, HEADING_3, HEADING_4, HEADING_5, HEADING_6, TITLE, SUBTITLE"), nil
 	}
 
 	var requests []*docs.Request
@@ -315,7 +393,7 @@ func setParagraphStyleHandler(ctx context.Context, request mcp.CallToolRequest,
 		}
 
 		if !validAlignments[input.Alignment] {
-			return mcp.NewToolResultText("Error: Invalid alignment. Must be one of: START, CENTER, END, JUSTIFY"), nil
+			return mcp.NewToolResultError("Error: Invalid alignment. Must be one of: START, CENTER, END, JUSTIFY"), nil
 		}
 
 		paragraphStyle.Alignment = input.Alignment
@@ -324,8 +402,8 @@ func setParagraphStyleHandler(ctx context.Context, request mcp.CallToolRequest,
 	requests = append(requests, &docs.Request{
 		UpdateParagraphStyle: &docs.UpdateParagraphStyleRequest{
 			Range: &docs.Range{
-				StartIndex: input.StartIndex,
-				EndIndex:   input.EndIndex,
+				StartIndex: startIndex,
+				EndIndex:   endIndex,
 			},
 			ParagraphStyle: paragraphStyle,
 			Fields:         "*",
@@ -333,41 +411,55 @@ func setParagraphStyleHandler(ctx context.Context, request mcp.CallToolRequest,
 	})
 
 	batchUpdateRequest := &docs.BatchUpdateDocumentRequest{
-		Requests: requests,
+		Requests:     requests,
+		WriteControl: writeControl,
 	}
 
-	_, err := docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
+	response, err := docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
 	if err != nil {
-		return util.HandleGoogleAPIError("set paragraph style", err), nil
+		return handleWriteError(ctx, "set paragraph style", input.DocumentID, writeControl, err), nil
 	}
 
 	result := fmt.Sprintf("Paragraph style applied successfully!\n\nDocument ID: %s\nRange: %d-%d\nStyle: %s",
-		input.DocumentID, input.StartIndex, input.EndIndex, input.StyleType)
+		input.DocumentID, startIndex, endIndex, input.StyleType)
 
 	if input.Alignment != "" {
 		result += fmt.Sprintf("\nAlignment: %s", input.Alignment)
 	}
 
-	return mcp.NewToolResultText(result), nil
+	output := EditOutput{
+		DocumentID: input.DocumentID,
+		RevisionID: revisionAfter(response),
+		StartIndex: startIndex,
+		EndIndex:   endIndex,
+	}
+
+	return mcp.NewToolResultStructured(output, result), nil
 }
 
 func setLineSpacingHandler(ctx context.Context, request mcp.CallToolRequest, input SetLineSpacingInput) (*mcp.CallToolResult, error) {
 	docsService := services.GoogleDocsClient()
 
-	if input.StartIndex >= input.EndIndex {
-		return mcp.NewToolResultText("Error: Start index must be less than end index."), nil
+	writeControl, errResult := newWriteControl(input.RevisionID, input.TargetRevisionID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	startIndex, endIndex, errResult := resolveRange(ctx, input.DocumentID, input.StartIndex, input.EndIndex, input.Anchor, writeControl)
+	if errResult != nil {
+		return errResult, nil
 	}
 
 	if input.Spacing <= 0 {
-		return mcp.NewToolResultText("Error: Line spacing must be greater than 0."), nil
+		return mcp.NewToolResultError("Error: Line spacing must be greater than 0."), nil
 	}
 
 	requests := []*docs.Request{
 		{
 			UpdateParagraphStyle: &docs.UpdateParagraphStyleRequest{
 				Range: &docs.Range{
-					StartIndex: input.StartIndex,
-					EndIndex:   input.EndIndex,
+					StartIndex: startIndex,
+					EndIndex:   endIndex,
 				},
 				ParagraphStyle: &docs.ParagraphStyle{
 					LineSpacing: input.Spacing,
@@ -378,18 +470,26 @@ func setLineSpacingHandler(ctx context.Context, request mcp.CallToolRequest, inp
 	}
 
 	batchUpdateRequest := &docs.BatchUpdateDocumentRequest{
-		Requests: requests,
+		Requests:     requests,
+		WriteControl: writeControl,
 	}
 
-	_, err := docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
+	response, err := docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
 	if err != nil {
-		return util.HandleGoogleAPIError("set line spacing", err), nil
+		return handleWriteError(ctx, "set line spacing", input.DocumentID, writeControl, err), nil
 	}
 
 	result := fmt.Sprintf("Line spacing applied successfully!\n\nDocument ID: %s\nRange: %d-%d\nSpacing: %.1fx",
-		input.DocumentID, input.StartIndex, input.EndIndex, input.Spacing)
+		input.DocumentID, startIndex, endIndex, input.Spacing)
+
+	output := EditOutput{
+		DocumentID: input.DocumentID,
+		RevisionID: revisionAfter(response),
+		StartIndex: startIndex,
+		EndIndex:   endIndex,
+	}
 
-	return mcp.NewToolResultText(result), nil
+	return mcp.NewToolResultStructured(output, result), nil
 }
 
 // parseHexColor parses a hex color string and returns a Google Docs Color object
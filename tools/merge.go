@@ -0,0 +1,363 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hdbrzgr/docs-mcp/services"
+	"github.com/hdbrzgr/docs-mcp/util"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"google.golang.org/api/drive/v3"
+)
+
+// defaultMergeConcurrency is the number of documents generated at the same
+// time when concurrency is not given
+const defaultMergeConcurrency = 4
+
+// Input types for mail merge tools
+type MailMergeInput struct {
+	TemplateID     string `json:"template_id" validate:"required"`
+	DataFile       string `json:"data_file" validate:"required"`
+	TitlePattern   string `json:"title_pattern" validate:"required"`
+	ParentFolderID string `json:"parent_folder_id,omitempty"`
+	ShareColumn    string `json:"share_column,omitempty"`
+	ShareRole      string `json:"share_role,omitempty"`
+	Concurrency    int    `json:"concurrency,omitempty"`
+	ProgressFile   string `json:"progress_file,omitempty"`
+}
+
+// Output types for mail merge tools
+type MailMergeOutput struct {
+	TemplateID   string            `json:"template_id"`
+	DataFile     string            `json:"data_file"`
+	ProgressFile string            `json:"progress_file"`
+	Total        int               `json:"total" jsonschema_description:"Records in the data file"`
+	Created      int               `json:"created" jsonschema_description:"Documents created by this run"`
+	Shared       int               `json:"shared" jsonschema_description:"Documents created by an earlier run that this run only had to share"`
+	Skipped      int               `json:"skipped" jsonschema_description:"Records already completed by an earlier run"`
+	Failed       int               `json:"failed" jsonschema_description:"Records that failed; run the merge again to retry them"`
+	Pending      int               `json:"pending" jsonschema_description:"Records not started because the request was cancelled"`
+	Documents    []MailMergeRecord `json:"documents"`
+	Warning      string            `json:"warning,omitempty"`
+}
+
+type MailMergeRecord struct {
+	Record     int      `json:"record" jsonschema_description:"Position of the record in the data file, starting at 1"`
+	Status     string   `json:"status" jsonschema_description:"created, shared, skipped or failed"`
+	Title      string   `json:"title"`
+	DocumentID string   `json:"document_id,omitempty"`
+	URL        string   `json:"url,omitempty"`
+	SharedWith []string `json:"shared_with,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// mergeProgress is a line of the progress log. The last line logged for a
+// record tells how far it got: a document ID without an error means it is
+// done, a document ID with an error means only the sharing is left. The
+// fingerprint of the record's values ties the line to the record it was
+// logged for, should the data file change between runs.
+type mergeProgress struct {
+	Record      int      `json:"record"`
+	Fingerprint string   `json:"fingerprint"`
+	TemplateID  string   `json:"template_id"`
+	Title       string   `json:"title"`
+	DocumentID  string   `json:"document_id,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	SharedWith  []string `json:"shared_with,omitempty"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// mergeJob is a record still to be merged, with what an earlier run logged for it
+type mergeJob struct {
+	record      int
+	fingerprint string
+	values      map[string]any
+	previous    mergeProgress
+}
+
+func RegisterMergeTools(s *server.MCPServer) {
+	// Mail merge tool
+	mailMergeTool := mcp.NewTool("mail_merge",
+		mcp.WithDescription("Create one document per record of a local CSV or JSON data file from a template document, filling the template's placeholders with the record as in create_from_template. "+
+			"Progress is appended to a log file as records complete, so running the same merge again after a failure only retries the records that did not finish"),
+		mcp.WithString("template_id", mcp.Required(), mcp.Description("The unique identifier of the template document")),
+		mcp.WithString("data_file", mcp.Required(), mcp.Description("Path of the data file: a .csv file with a header row naming the columns, or a .json file holding an array of objects")),
+		mcp.WithString("title_pattern", mcp.Required(), mcp.Description("Title of each document, with {{column}} placeholders filled from the record, e.g. 'Offer Letter - {{name}}'")),
+		mcp.WithString("parent_folder_id", mcp.Description("ID of the Drive folder to create the documents in (default: the folder of the template)")),
+		mcp.WithString("share_column", mcp.Description("Column holding the email addresses to share each document with; several addresses are separated by commas or semicolons")),
+		mcp.WithString("share_role", mcp.Description("Role granted when sharing: 'reader', 'writer', or 'commenter' (default: 'reader')")),
+		mcp.WithNumber("concurrency", mcp.Description(fmt.Sprintf("Number of documents generated at the same time (default: %d, max: 10)", defaultMergeConcurrency))),
+		mcp.WithString("progress_file", mcp.Description("Path of the progress log (default: the data file path followed by '.progress.jsonl')")),
+		mcp.WithOutputSchema[MailMergeOutput](),
+	)
+	s.AddTool(mailMergeTool, mcp.NewTypedToolHandler(mailMergeHandler))
+}
+
+func mailMergeHandler(ctx context.Context, request mcp.CallToolRequest, input MailMergeInput) (*mcp.CallToolResult, error) {
+	driveService := services.GoogleDriveClient()
+
+	role, _, errResult := shareSettings(input.ShareRole, "user")
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	concurrency := input.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultMergeConcurrency
+	}
+	if concurrency > 10 {
+		concurrency = 10
+	}
+
+	progressFile := input.ProgressFile
+	if progressFile == "" {
+		progressFile = input.DataFile + ".progress.jsonl"
+	}
+
+	records, err := util.LoadMergeRecords(input.DataFile)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error: Failed to read data file: %v", err)), nil
+	}
+
+	previous, err := readMergeProgress(progressFile, input.TemplateID)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error: Failed to read progress file: %v", err)), nil
+	}
+
+	file, err := os.OpenFile(progressFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error: Failed to open progress file: %v", err)), nil
+	}
+	defer file.Close()
+	progressLog := &lockedWriter{writer: file}
+
+	output := MailMergeOutput{
+		TemplateID:   input.TemplateID,
+		DataFile:     input.DataFile,
+		ProgressFile: progressFile,
+		Total:        len(records),
+		Documents:    []MailMergeRecord{},
+	}
+
+	var jobs []mergeJob
+	for i, values := range records {
+		job := mergeJob{record: i + 1, fingerprint: recordFingerprint(values), values: values, previous: previous[i+1]}
+		if job.previous.Record != 0 && job.previous.Fingerprint != job.fingerprint {
+			return mcp.NewToolResultError(fmt.Sprintf("Error: Record %d of the data file differs from the record the progress file logged for it, so the data file was edited or reordered since the earlier run. Restore the data file, or choose another progress_file to start a new merge.", job.record)), nil
+		}
+		if job.previous.DocumentID != "" && job.previous.Error == "" {
+			output.Documents = append(output.Documents, mergeRecordOutput(job.previous, "skipped"))
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		logErr   error
+		jobQueue = make(chan mergeJob)
+	)
+	for range min(concurrency, len(jobs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobQueue {
+				entry, status := mergeRecord(ctx, driveService, input, role, job)
+
+				line, _ := json.Marshal(entry)
+				_, err := progressLog.Write(append(line, '\n'))
+
+				mu.Lock()
+				if err != nil && logErr == nil {
+					logErr = err
+				}
+				output.Documents = append(output.Documents, mergeRecordOutput(entry, status))
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, job := range jobs {
+		select {
+		case jobQueue <- job:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobQueue)
+	wg.Wait()
+
+	sort.Slice(output.Documents, func(i, j int) bool {
+		return output.Documents[i].Record < output.Documents[j].Record
+	})
+	for _, document := range output.Documents {
+		switch document.Status {
+		case "created":
+			output.Created++
+		case "shared":
+			output.Shared++
+		case "skipped":
+			output.Skipped++
+		case "failed":
+			output.Failed++
+		}
+	}
+	output.Pending = output.Total - len(output.Documents)
+	if logErr != nil {
+		output.Warning = fmt.Sprintf("Failed to write the progress file, so a later run may create some documents again: %v", logErr)
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Mail merge finished.\n\nTemplate ID: %s\nData File: %s\nProgress File: %s\n", output.TemplateID, output.DataFile, output.ProgressFile))
+	result.WriteString(fmt.Sprintf("Records: %d\nCreated: %d\nShared: %d\nSkipped: %d\nFailed: %d\n", output.Total, output.Created, output.Shared, output.Skipped, output.Failed))
+	if output.Pending > 0 {
+		result.WriteString(fmt.Sprintf("Not Started: %d\n", output.Pending))
+	}
+	if output.Warning != "" {
+		result.WriteString(fmt.Sprintf("Warning: %s\n", output.Warning))
+	}
+
+	if len(output.Documents) > 0 {
+		result.WriteString("\n")
+	}
+	for _, document := range output.Documents {
+		result.WriteString(fmt.Sprintf("%d. %s [%s]", document.Record, document.Title, document.Status))
+		if document.DocumentID != "" {
+			result.WriteString(fmt.Sprintf(" ID: %s", document.DocumentID))
+		}
+		if len(document.SharedWith) > 0 {
+			result.WriteString(fmt.Sprintf(", shared with %s", strings.Join(document.SharedWith, ", ")))
+		}
+		if document.Error != "" {
+			result.WriteString(fmt.Sprintf("\n   Error: %s", document.Error))
+		}
+		result.WriteString("\n")
+	}
+	if output.Failed > 0 || output.Pending > 0 {
+		result.WriteString("\nRun the same merge again to retry the records that did not finish.\n")
+	}
+
+	return mcp.NewToolResultStructured(output, result.String()), nil
+}
+
+// mergeRecord creates the document of a record, unless an earlier run already
+// did, and shares it with the addresses in the share column that it was not
+// shared with yet. It returns the progress to log and the record's status.
+func mergeRecord(ctx context.Context, driveService *drive.Service, input MailMergeInput, role string, job mergeJob) (mergeProgress, string) {
+	entry := job.previous
+	entry.Record = job.record
+	entry.Fingerprint = job.fingerprint
+	entry.TemplateID = input.TemplateID
+	entry.Error = ""
+
+	status := "shared"
+	if entry.DocumentID == "" {
+		entry.Title = util.FillTemplateText(input.TitlePattern, job.values)
+
+		created, err := createFromTemplate(ctx, input.TemplateID, entry.Title, input.ParentFolderID, job.values)
+		if err != nil {
+			entry.Error = err.Error()
+			return entry, "failed"
+		}
+		entry.DocumentID = created.DocumentID
+		entry.Title = created.Title
+		entry.URL = created.URL
+		status = "created"
+	}
+
+	if input.ShareColumn == "" {
+		return entry, status
+	}
+	for _, email := range mergeEmails(job.values[input.ShareColumn]) {
+		if slices.Contains(entry.SharedWith, email) {
+			continue
+		}
+		if _, err := shareDocument(ctx, driveService, entry.DocumentID, email, role, "user"); err != nil {
+			entry.Error = fmt.Sprintf("share with %s: %v", email, err)
+			return entry, "failed"
+		}
+		entry.SharedWith = append(entry.SharedWith, email)
+	}
+
+	return entry, status
+}
+
+// recordFingerprint hashes the values of a record. Map keys are marshalled in
+// sorted order, so equal records always have the same fingerprint.
+func recordFingerprint(values map[string]any) string {
+	data, _ := json.Marshal(values)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// mergeEmails splits the value of the share column into email addresses
+func mergeEmails(value any) []string {
+	text, ok := value.(string)
+	if !ok {
+		return nil
+	}
+
+	var emails []string
+	for _, email := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ';' }) {
+		if email = strings.TrimSpace(email); email != "" {
+			emails = append(emails, email)
+		}
+	}
+	return emails
+}
+
+// readMergeProgress reads the progress log of an earlier run, keeping the last
+// line logged for each record. A missing file means nothing was done yet, and
+// a line cut short by an interrupted run is ignored.
+func readMergeProgress(path, templateID string) (map[int]mergeProgress, error) {
+	progress := map[int]mergeProgress{}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return progress, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry mergeProgress
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil || entry.Record <= 0 {
+			continue
+		}
+		if entry.TemplateID != templateID {
+			return nil, fmt.Errorf("%s logs a merge of template %s; choose another progress_file for this template", path, entry.TemplateID)
+		}
+		progress[entry.Record] = entry
+	}
+	return progress, scanner.Err()
+}
+
+// mergeRecordOutput converts a logged record to its structured form
+func mergeRecordOutput(entry mergeProgress, status string) MailMergeRecord {
+	return MailMergeRecord{
+		Record:     entry.Record,
+		Status:     status,
+		Title:      entry.Title,
+		DocumentID: entry.DocumentID,
+		URL:        entry.URL,
+		SharedWith: entry.SharedWith,
+		Error:      entry.Error,
+	}
+}
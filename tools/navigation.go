@@ -0,0 +1,349 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hdbrzgr/docs-mcp/services"
+	"github.com/hdbrzgr/docs-mcp/util"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"google.golang.org/api/docs/v1"
+)
+
+// Input types for navigation tools
+type GetOutlineInput struct {
+	DocumentID string `json:"document_id" validate:"required"`
+	MaxLevel   int    `json:"max_level,omitempty"` // Deepest heading level to include (1-6)
+}
+
+type ReadSectionInput struct {
+	DocumentID     string `json:"document_id" validate:"required"`
+	Heading        string `json:"heading" validate:"required"` // Heading text or heading ID
+	Format         string `json:"format,omitempty"`            // markdown, text
+	IncludeHeading bool   `json:"include_heading,omitempty"`
+}
+
+type ReplaceSectionInput struct {
+	DocumentID       string `json:"document_id" validate:"required"`
+	Heading          string `json:"heading" validate:"required"` // Heading text or heading ID
+	Content          string `json:"content"`
+	Format           string `json:"format,omitempty"` // markdown, text
+	RevisionID       string `json:"revision_id,omitempty"`
+	TargetRevisionID string `json:"target_revision_id,omitempty"`
+}
+
+// Output types for navigation tools
+type GetOutlineOutput struct {
+	DocumentID string              `json:"document_id"`
+	Title      string              `json:"title"`
+	RevisionID string              `json:"revision_id"`
+	Headings   []util.OutlineEntry `json:"headings" jsonschema_description:"Headings in document order; the tree is given by each heading's parent"`
+	Count      int                 `json:"count"`
+}
+
+type ReadSectionOutput struct {
+	DocumentID string            `json:"document_id"`
+	RevisionID string            `json:"revision_id"`
+	Section    util.OutlineEntry `json:"section"`
+	Format     string            `json:"format"`
+	Content    string            `json:"content"`
+}
+
+type ReplaceSectionOutput struct {
+	EditOutput
+	Heading    string `json:"heading"`
+	Removed    int64  `json:"removed" jsonschema_description:"Number of indices of previous section content that were deleted"`
+	Paragraphs int    `json:"paragraphs"`
+	Tables     int    `json:"tables"`
+	Images     int    `json:"images"`
+}
+
+func RegisterNavigationTools(s *server.MCPServer) {
+	// Get outline tool
+	getOutlineTool := mcp.NewTool("get_outline",
+		mcp.WithDescription("Get the heading tree of a Google Docs document: for every heading its level, text, heading ID, parent heading, the index range of the heading and of the whole section it owns, and how many tables, images and lists the section contains. Use it to find where sections are before targeting edits."),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
+		mcp.WithNumber("max_level", mcp.Description("Deepest heading level to include, from 1 to 6 (default: all levels)")),
+		mcp.WithOutputSchema[GetOutlineOutput](),
+	)
+	s.AddTool(getOutlineTool, mcp.NewTypedToolHandler(getOutlineHandler))
+
+	// Read section tool
+	readSectionTool := mcp.NewTool("read_section",
+		mcp.WithDescription("Read the content of one section of a Google Docs document, identified by its heading text or heading ID. The section runs from the heading to the next heading of the same or higher level and includes its subsections."),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
+		mcp.WithString("heading", mcp.Required(), mcp.Description("Text or heading ID of the heading that starts the section")),
+		mcp.WithString("format", mcp.Description("Content format: 'markdown' or 'text' (default: 'markdown')")),
+		mcp.WithBoolean("include_heading", mcp.Description("Whether to include the heading itself in the content (default: false)")),
+		mcp.WithOutputSchema[ReadSectionOutput](),
+	)
+	s.AddTool(readSectionTool, mcp.NewTypedToolHandler(readSectionHandler))
+
+	// Replace section tool
+	replaceSectionTool := mcp.NewTool("replace_section",
+		mcp.WithDescription("Replace the content of one section of a Google Docs document in a single atomic update. Everything between the heading and the next heading of the same or higher level is replaced, including subsections; the heading itself and its style are kept."),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
+		mcp.WithString("heading", mcp.Required(), mcp.Description("Text or heading ID of the heading that starts the section")),
+		mcp.WithString("content", mcp.Required(), mcp.Description("New content of the section, without the heading. An empty string clears the section.")),
+		mcp.WithString("format", mcp.Description("Content format: 'markdown' to create native headings, lists, tables and styles, or 'text' for plain paragraphs (default: 'markdown')")),
+		withWriteControl(),
+		mcp.WithOutputSchema[ReplaceSectionOutput](),
+	)
+	s.AddTool(replaceSectionTool, mcp.NewTypedToolHandler(replaceSectionHandler))
+}
+
+func getOutlineHandler(ctx context.Context, request mcp.CallToolRequest, input GetOutlineInput) (*mcp.CallToolResult, error) {
+	docsService := services.GoogleDocsClient()
+
+	if input.MaxLevel < 0 || input.MaxLevel > 6 {
+		return mcp.NewToolResultError("Error: max_level must be between 1 and 6."), nil
+	}
+
+	doc, err := docsService.Documents.Get(input.DocumentID).Context(ctx).Do()
+	if err != nil {
+		return util.HandleGoogleAPIError("get document outline", err), nil
+	}
+
+	output := GetOutlineOutput{
+		DocumentID: doc.DocumentId,
+		Title:      doc.Title,
+		RevisionID: doc.RevisionId,
+		Headings:   filterOutline(util.BuildOutline(doc), input.MaxLevel),
+	}
+	output.Count = len(output.Headings)
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Document Outline: %s\n\nDocument ID: %s\nRevision ID: %s\nHeadings: %d\n",
+		doc.Title, doc.DocumentId, doc.RevisionId, output.Count))
+
+	if output.Count == 0 {
+		result.WriteString("\nThe document has no headings.\n")
+		return mcp.NewToolResultStructured(output, result.String()), nil
+	}
+
+	result.WriteString("\n")
+	depths := make([]int, len(output.Headings))
+	for i, heading := range output.Headings {
+		if heading.Parent >= 0 {
+			depths[i] = depths[heading.Parent] + 1
+		}
+		indent := strings.Repeat("  ", depths[i])
+
+		result.WriteString(fmt.Sprintf("%s- H%d %s\n", indent, heading.Level, heading.Text))
+		result.WriteString(fmt.Sprintf("%s  Heading: %d-%d, Section: %d-%d", indent,
+			heading.StartIndex, heading.HeadingEndIndex, heading.StartIndex, heading.EndIndex))
+		if contents := sectionContents(heading); contents != "" {
+			result.WriteString(fmt.Sprintf(" (%s)", contents))
+		}
+		result.WriteString("\n")
+		if heading.HeadingID != "" {
+			result.WriteString(fmt.Sprintf("%s  Heading ID: %s\n", indent, heading.HeadingID))
+		}
+	}
+
+	return mcp.NewToolResultStructured(output, result.String()), nil
+}
+
+// filterOutline keeps the headings up to maxLevel, renumbering their parents.
+// Parents always have a lower level, so they are kept along with their children.
+func filterOutline(outline []util.OutlineEntry, maxLevel int) []util.OutlineEntry {
+	filtered := []util.OutlineEntry{}
+	positions := make([]int, len(outline))
+	for i, entry := range outline {
+		if maxLevel > 0 && entry.Level > maxLevel {
+			continue
+		}
+		if entry.Parent >= 0 {
+			entry.Parent = positions[entry.Parent]
+		}
+		positions[i] = len(filtered)
+		filtered = append(filtered, entry)
+	}
+	return filtered
+}
+
+// sectionContents describes the tables, images and lists in a section
+func sectionContents(entry util.OutlineEntry) string {
+	var parts []string
+	for _, count := range []struct {
+		n    int
+		name string
+	}{
+		{entry.Tables, "table"},
+		{entry.Images, "image"},
+		{entry.Lists, "list"},
+	} {
+		switch {
+		case count.n == 1:
+			parts = append(parts, "1 "+count.name)
+		case count.n > 1:
+			parts = append(parts, fmt.Sprintf("%d %ss", count.n, count.name))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func readSectionHandler(ctx context.Context, request mcp.CallToolRequest, input ReadSectionInput) (*mcp.CallToolResult, error) {
+	docsService := services.GoogleDocsClient()
+
+	format, errResult := sectionFormat(input.Format)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	doc, err := docsService.Documents.Get(input.DocumentID).Context(ctx).Do()
+	if err != nil {
+		return util.HandleGoogleAPIError("get document for reading section", err), nil
+	}
+
+	section, err := util.FindSection(doc, input.Heading)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error: %v", err)), nil
+	}
+
+	start := section.HeadingEndIndex
+	if input.IncludeHeading {
+		start = section.StartIndex
+	}
+
+	var content string
+	if format == "markdown" {
+		content = util.ConvertElementsToMarkdown(doc, sectionElements(doc, start, section.EndIndex))
+	} else {
+		content = util.NewTextMap(doc).Slice(start, section.EndIndex)
+	}
+
+	result := fmt.Sprintf("Section: %s\n\nDocument ID: %s\nRevision ID: %s\nHeading Level: %d\nSection Range: %d-%d\nFormat: %s\n\n--- Content ---\n%s\n--- End of Section ---",
+		section.Text, doc.DocumentId, doc.RevisionId, section.Level, section.StartIndex, section.EndIndex, format, content)
+
+	output := ReadSectionOutput{
+		DocumentID: doc.DocumentId,
+		RevisionID: doc.RevisionId,
+		Section:    section,
+		Format:     format,
+		Content:    content,
+	}
+
+	return mcp.NewToolResultStructured(output, result), nil
+}
+
+func replaceSectionHandler(ctx context.Context, request mcp.CallToolRequest, input ReplaceSectionInput) (*mcp.CallToolResult, error) {
+	docsService := services.GoogleDocsClient()
+
+	writeControl, errResult := newWriteControl(input.RevisionID, input.TargetRevisionID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	format, errResult := sectionFormat(input.Format)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	doc, err := docsService.Documents.Get(input.DocumentID).Context(ctx).Do()
+	if err != nil {
+		return util.HandleGoogleAPIError("get document for section replacement", err), nil
+	}
+
+	if errResult := pinWriteControl(writeControl, doc); errResult != nil {
+		return errResult, nil
+	}
+
+	section, err := util.FindSection(doc, input.Heading)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error: %v", err)), nil
+	}
+
+	// The final newline of the body cannot be deleted, so a section at the end
+	// of the document keeps an empty last paragraph, which the new content fills
+	bodyEnd := util.BodyEndIndex(doc)
+	atBodyEnd := section.EndIndex >= bodyEnd
+	deleteEnd := section.EndIndex
+	if atBodyEnd {
+		deleteEnd = bodyEnd - 1
+	}
+
+	var requests []*docs.Request
+	insertIndex := section.HeadingEndIndex
+	removed := int64(0)
+
+	if deleteEnd > insertIndex {
+		requests = append(requests, deleteRangeRequest(insertIndex, deleteEnd))
+		removed = deleteEnd - insertIndex
+	}
+
+	var batch *util.MarkdownBatch
+	if format == "markdown" {
+		batch, err = util.CompileMarkdown(input.Content, insertIndex, atBodyEnd)
+	} else {
+		batch, err = util.CompilePlainText(input.Content, insertIndex, atBodyEnd)
+	}
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error: Failed to parse content: %v", err)), nil
+	}
+
+	if len(batch.Requests) > 0 && insertIndex >= bodyEnd {
+		// The heading is the last paragraph: start a new paragraph after it
+		requests = append(requests, insertTextRequest(bodyEnd-1, "\n"))
+	}
+	requests = append(requests, batch.Requests...)
+
+	if atBodyEnd && removed > 0 && len(batch.Requests) == 0 {
+		// Without new content the empty last paragraph keeps the style of the
+		// content it ended; make it a plain paragraph again
+		requests = append(requests, util.PlainParagraphRequests(insertIndex)...)
+	}
+
+	if len(requests) == 0 {
+		result := fmt.Sprintf("Section is already empty.\n\nDocument ID: %s\nSection: %s", input.DocumentID, section.Text)
+		output := ReplaceSectionOutput{
+			EditOutput: EditOutput{DocumentID: input.DocumentID, StartIndex: insertIndex, EndIndex: insertIndex},
+			Heading:    section.Text,
+		}
+		return mcp.NewToolResultStructured(output, result), nil
+	}
+
+	batchUpdateRequest := &docs.BatchUpdateDocumentRequest{
+		Requests:     requests,
+		WriteControl: writeControl,
+	}
+
+	response, err := docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
+	if err != nil {
+		return handleWriteError(ctx, "replace section", input.DocumentID, writeControl, err), nil
+	}
+
+	result := fmt.Sprintf("Section replaced successfully!\n\nDocument ID: %s\nSection: %s\nRemoved: %d indices\nNew Content Range: %d-%d\nParagraphs: %d\nTables: %d\nImages: %d",
+		input.DocumentID, section.Text, removed, batch.StartIndex, batch.EndIndex, batch.Paragraphs, batch.Tables, batch.Images)
+
+	output := ReplaceSectionOutput{
+		EditOutput: EditOutput{
+			DocumentID: input.DocumentID,
+			RevisionID: revisionAfter(response),
+			StartIndex: batch.StartIndex,
+			EndIndex:   batch.EndIndex,
+			Length:     batch.EndIndex - batch.StartIndex,
+		},
+		Heading:    section.Text,
+		Removed:    removed,
+		Paragraphs: batch.Paragraphs,
+		Tables:     batch.Tables,
+		Images:     batch.Images,
+	}
+
+	return mcp.NewToolResultStructured(output, result), nil
+}
+
+// sectionFormat validates the content format of the section tools
+func sectionFormat(format string) (string, *mcp.CallToolResult) {
+	switch format {
+	case "":
+		return "markdown", nil
+	case "markdown", "text":
+		return format, nil
+	default:
+		return "", mcp.NewToolResultError("Error: Invalid format. Must be 'markdown' or 'text'.")
+	}
+}
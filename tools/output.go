@@ -0,0 +1,65 @@
+package tools
+
+import (
+	"fmt"
+
+	"google.golang.org/api/docs/v1"
+	"google.golang.org/api/drive/v3"
+)
+
+// EditOutput is the structured result of tools that modify document content
+type EditOutput struct {
+	DocumentID string `json:"document_id"`
+	RevisionID string `json:"revision_id,omitempty" jsonschema_description:"Revision of the document after the edit. Pass it as revision_id to chain further edits"`
+	StartIndex int64  `json:"start_index,omitempty" jsonschema_description:"Start of the range written by the edit, or of the deleted range for deletions"`
+	EndIndex   int64  `json:"end_index,omitempty" jsonschema_description:"End of the range written by the edit, or of the deleted range for deletions"`
+	Length     int64  `json:"length,omitempty" jsonschema_description:"Number of indices inserted or deleted"`
+}
+
+// DriveFileOutput is the structured form of a Drive file
+type DriveFileOutput struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	MimeType       string   `json:"mime_type,omitempty"`
+	CreatedTime    string   `json:"created_time,omitempty"`
+	ModifiedTime   string   `json:"modified_time,omitempty"`
+	ViewedByMeTime string   `json:"viewed_by_me_time,omitempty"`
+	Owners         []string `json:"owners,omitempty"`
+	Size           int64    `json:"size,omitempty"`
+	URL            string   `json:"url,omitempty"`
+}
+
+// documentURL returns the edit URL of a document
+func documentURL(documentID string) string {
+	return fmt.Sprintf("https://docs.google.com/document/d/%s/edit", documentID)
+}
+
+// revisionAfter returns the revision of a document after a batch update
+func revisionAfter(response *docs.BatchUpdateDocumentResponse) string {
+	if response == nil || response.WriteControl == nil {
+		return ""
+	}
+	return response.WriteControl.RequiredRevisionId
+}
+
+// driveFileOutput converts a Drive file to its structured form
+func driveFileOutput(file *drive.File) DriveFileOutput {
+	output := DriveFileOutput{
+		ID:             file.Id,
+		Name:           file.Name,
+		MimeType:       file.MimeType,
+		CreatedTime:    file.CreatedTime,
+		ModifiedTime:   file.ModifiedTime,
+		ViewedByMeTime: file.ViewedByMeTime,
+		Size:           file.Size,
+		URL:            file.WebViewLink,
+	}
+	for _, owner := range file.Owners {
+		if owner.EmailAddress != "" {
+			output.Owners = append(output.Owners, owner.EmailAddress)
+		} else {
+			output.Owners = append(output.Owners, owner.DisplayName)
+		}
+	}
+	return output
+}
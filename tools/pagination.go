@@ -0,0 +1,177 @@
+package tools
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hdbrzgr/docs-mcp/util"
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/api/docs/v1"
+)
+
+// defaultPageChars is the character budget of a page when max_chars is not given
+const defaultPageChars = 50000
+
+// defaultPageParagraphs is the number of paragraphs on a page when paging by paragraphs
+const defaultPageParagraphs = 50
+
+// PaginationInput holds the parameters shared by tools that read a document
+// page by page
+type PaginationInput struct {
+	Cursor     string `json:"cursor,omitempty"`
+	MaxChars   int64  `json:"max_chars,omitempty"`
+	PageBy     string `json:"page_by,omitempty"` // chars, section, paragraphs
+	Paragraphs int    `json:"paragraphs,omitempty"`
+}
+
+// PageOutput describes the part of a document returned by a paginated read
+type PageOutput struct {
+	StartIndex      int64  `json:"start_index"`
+	EndIndex        int64  `json:"end_index"`
+	RangeStartIndex int64  `json:"range_start_index" jsonschema_description:"Start of the whole range being read page by page"`
+	RangeEndIndex   int64  `json:"range_end_index" jsonschema_description:"End of the whole range being read page by page"`
+	Offset          int64  `json:"offset" jsonschema_description:"Characters of the range before this page"`
+	Characters      int64  `json:"characters" jsonschema_description:"Characters on this page"`
+	TotalCharacters int64  `json:"total_characters" jsonschema_description:"Characters in the whole range"`
+	NextCursor      string `json:"next_cursor,omitempty" jsonschema_description:"Pass as cursor to read the next page; absent on the last page"`
+	Warning         string `json:"warning,omitempty"`
+}
+
+// pageCursor is the position of the next page, encoded into next_cursor. It
+// carries the paging options so later pages are cut the same way.
+type pageCursor struct {
+	DocumentID string `json:"d"`
+	RevisionID string `json:"r"`
+	Index      int64  `json:"i"`
+	RangeStart int64  `json:"s"`
+	RangeEnd   int64  `json:"e"`
+	PageBy     string `json:"p,omitempty"`
+	MaxChars   int64  `json:"m,omitempty"`
+	Paragraphs int    `json:"n,omitempty"`
+}
+
+// withPagination adds the optional paging parameters shared by reading tools
+func withPagination() mcp.ToolOption {
+	return func(t *mcp.Tool) {
+		mcp.WithString("cursor",
+			mcp.Description("The next_cursor returned by the previous page. When given, the other paging and range parameters are taken from the cursor"),
+		)(t)
+		mcp.WithNumber("max_chars",
+			mcp.Description(fmt.Sprintf("Character budget of a page (default: %d)", defaultPageChars)),
+		)(t)
+		mcp.WithString("page_by",
+			mcp.Description("How pages are cut: 'chars' fills each page up to max_chars, 'section' also starts a new page at every heading, 'paragraphs' puts a fixed number of paragraphs on each page within max_chars (default: 'chars')"),
+		)(t)
+		mcp.WithNumber("paragraphs",
+			mcp.Description(fmt.Sprintf("Paragraphs per page when page_by is 'paragraphs'; a table counts as one (default: %d)", defaultPageParagraphs)),
+		)(t)
+	}
+}
+
+// readPage works out the page to return from the paging parameters. Without a
+// cursor the first page of the range from rangeStart to rangeEnd is read.
+// WholeElements pages never split a paragraph. A non-nil result is an error to
+// return.
+func readPage(doc *docs.Document, textMap *util.TextMap, rangeStart, rangeEnd int64, input PaginationInput, wholeElements bool) (PageOutput, *mcp.CallToolResult) {
+	cursor := pageCursor{
+		DocumentID: doc.DocumentId,
+		RevisionID: doc.RevisionId,
+		Index:      rangeStart,
+		RangeStart: rangeStart,
+		RangeEnd:   rangeEnd,
+		PageBy:     input.PageBy,
+		MaxChars:   input.MaxChars,
+		Paragraphs: input.Paragraphs,
+	}
+
+	if input.Cursor != "" {
+		decoded, err := decodePageCursor(input.Cursor)
+		if err != nil || decoded.DocumentID != doc.DocumentId {
+			return PageOutput{}, mcp.NewToolResultError("Error: Invalid cursor. Pass the next_cursor returned by the previous page of the same document.")
+		}
+		cursor = decoded
+	}
+
+	switch cursor.PageBy {
+	case "", "chars", "section", "paragraphs":
+	default:
+		return PageOutput{}, mcp.NewToolResultError("Error: Invalid page_by. Must be 'chars', 'section', or 'paragraphs'.")
+	}
+	if cursor.MaxChars <= 0 {
+		cursor.MaxChars = defaultPageChars
+	}
+	if cursor.PageBy == "paragraphs" && cursor.Paragraphs <= 0 {
+		cursor.Paragraphs = defaultPageParagraphs
+	}
+
+	options := util.PageOptions{
+		MaxChars:      cursor.MaxChars,
+		BySection:     cursor.PageBy == "section",
+		WholeElements: wholeElements,
+	}
+	if cursor.PageBy == "paragraphs" {
+		options.Paragraphs = cursor.Paragraphs
+	}
+
+	page := PageOutput{
+		StartIndex:      cursor.Index,
+		RangeStartIndex: cursor.RangeStart,
+		RangeEndIndex:   cursor.RangeEnd,
+	}
+	if page.StartIndex < page.RangeEndIndex {
+		page.EndIndex = util.NextPage(doc, textMap, page.StartIndex, page.RangeEndIndex, options)
+	} else {
+		page.EndIndex = page.RangeEndIndex
+	}
+
+	page.Offset = util.UTF16Length(textMap.Slice(page.RangeStartIndex, page.StartIndex))
+	page.Characters = util.UTF16Length(textMap.Slice(page.StartIndex, page.EndIndex))
+	page.TotalCharacters = util.UTF16Length(textMap.Slice(page.RangeStartIndex, page.RangeEndIndex))
+
+	if cursor.RevisionID != doc.RevisionId {
+		page.Warning = "The document changed since the cursor was issued, so this page may repeat or skip some content."
+	}
+
+	if page.EndIndex < page.RangeEndIndex {
+		cursor.RevisionID = doc.RevisionId
+		cursor.Index = page.EndIndex
+		page.NextCursor = encodePageCursor(cursor)
+	}
+
+	return page, nil
+}
+
+// pageHeader describes the position of a page within the range being read
+func pageHeader(page PageOutput) string {
+	header := fmt.Sprintf("Page: indices %d-%d of %d-%d, characters %d-%d of %d\n",
+		page.StartIndex, page.EndIndex, page.RangeStartIndex, page.RangeEndIndex,
+		page.Offset, page.Offset+page.Characters, page.TotalCharacters)
+
+	if page.Warning != "" {
+		header += fmt.Sprintf("Warning: %s\n", page.Warning)
+	}
+
+	if page.NextCursor != "" {
+		header += fmt.Sprintf("Next Cursor: %s\n", page.NextCursor)
+	} else {
+		header += "Last page\n"
+	}
+
+	return header
+}
+
+func encodePageCursor(cursor pageCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodePageCursor(value string) (pageCursor, error) {
+	var cursor pageCursor
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return cursor, err
+	}
+	err = json.Unmarshal(data, &cursor)
+	return cursor, err
+}
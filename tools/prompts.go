@@ -0,0 +1,391 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/hdbrzgr/docs-mcp/services"
+	"github.com/hdbrzgr/docs-mcp/util"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"google.golang.org/api/docs/v1"
+)
+
+// promptsDirEnv names the environment variable of the directory team-defined
+// prompt templates are loaded from
+const promptsDirEnv = "DOCS_PROMPTS_DIR"
+
+// promptTemplateExtensions are the file extensions loaded as prompt templates
+var promptTemplateExtensions = map[string]bool{".md": true, ".txt": true, ".tmpl": true}
+
+// promptTemplate is a team-defined prompt loaded from a file
+type promptTemplate struct {
+	Name        string
+	Description string
+	Arguments   []mcp.PromptArgument
+	Template    *template.Template
+}
+
+func RegisterPrompts(s *server.MCPServer) {
+	// Summarize document prompt
+	summarizePrompt := mcp.NewPrompt("summarize_document",
+		mcp.WithPromptDescription("Summarize a Google Docs document"),
+		withDocumentArgument(),
+		mcp.WithArgument("audience", mcp.ArgumentDescription("Who the summary is for, e.g. 'executives' or 'new team members'")),
+		mcp.WithArgument("length", mcp.ArgumentDescription("Desired length, e.g. '3 bullet points' or 'one paragraph'")),
+	)
+	s.AddPrompt(summarizePrompt, summarizePromptHandler)
+
+	// Review and comment prompt
+	reviewPrompt := mcp.NewPrompt("review_document",
+		mcp.WithPromptDescription("Review a Google Docs document and leave comments on the passages that need work"),
+		withDocumentArgument(),
+		mcp.WithArgument("focus", mcp.ArgumentDescription("What to focus the review on, e.g. 'clarity', 'technical accuracy' or 'tone'")),
+	)
+	s.AddPrompt(reviewPrompt, reviewPromptHandler)
+
+	// Meeting notes prompt
+	meetingNotesPrompt := mcp.NewPrompt("meeting_notes",
+		mcp.WithPromptDescription("Draft meeting notes in a new document based on a template document"),
+		mcp.WithArgument("document_id", mcp.RequiredArgument(), mcp.ArgumentDescription("The ID of the template document")),
+		mcp.WithArgument("title", mcp.RequiredArgument(), mcp.ArgumentDescription("The title of the new meeting notes document")),
+		mcp.WithArgument("notes", mcp.ArgumentDescription("Raw notes or a transcript of the meeting")),
+	)
+	s.AddPrompt(meetingNotesPrompt, meetingNotesPromptHandler)
+
+	// Tighten section prompt
+	tightenPrompt := mcp.NewPrompt("tighten_section",
+		mcp.WithPromptDescription("Tighten the prose of one section of a Google Docs document without changing its meaning"),
+		withDocumentArgument(),
+		mcp.WithArgument("section", mcp.RequiredArgument(), mcp.ArgumentDescription("The heading text of the section to tighten")),
+	)
+	s.AddPrompt(tightenPrompt, tightenSectionPromptHandler)
+
+	// Team-defined prompts
+	if dir := os.Getenv(promptsDirEnv); dir != "" {
+		templates, err := loadPromptTemplates(dir)
+		if err != nil {
+			log.Printf("Failed to load prompt templates from %s: %v", dir, err)
+		}
+		for _, t := range templates {
+			s.AddPrompt(t.Prompt(), t.Handler())
+		}
+	}
+}
+
+// withDocumentArgument adds the required document_id argument shared by all
+// document prompts
+func withDocumentArgument() mcp.PromptOption {
+	return mcp.WithArgument("document_id", mcp.RequiredArgument(), mcp.ArgumentDescription("The ID of the Google Docs document"))
+}
+
+func summarizePromptHandler(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	doc, messages, err := documentPromptMessages(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	args := request.Params.Arguments
+
+	var instructions strings.Builder
+	instructions.WriteString(fmt.Sprintf("Summarize the document \"%s\" attached above.", doc.Title))
+	if audience := args["audience"]; audience != "" {
+		instructions.WriteString(fmt.Sprintf(" Write the summary for %s.", audience))
+	}
+	if length := args["length"]; length != "" {
+		instructions.WriteString(fmt.Sprintf(" Keep it to %s.", length))
+	}
+	instructions.WriteString("\n\nLead with the purpose of the document and its main conclusions or decisions, then list open questions and action items with their owners if the document names them. ")
+	instructions.WriteString("Base the summary only on the document; if a section is unclear, say so instead of guessing. ")
+	instructions.WriteString("Do not modify the document.")
+
+	messages = append(messages, mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(instructions.String())))
+
+	return mcp.NewGetPromptResult("Summarize "+doc.Title, messages), nil
+}
+
+func reviewPromptHandler(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	doc, messages, err := documentPromptMessages(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	focus := request.Params.Arguments["focus"]
+	if focus == "" {
+		focus = "clarity, correctness, structure and consistency"
+	}
+
+	instructions := fmt.Sprintf(`Review the document "%s" attached above, focusing on %s.
+
+1. Call list_comments with document_id "%s" first, so you do not repeat points that are already under discussion.
+2. For each passage that needs work, call create_comment with an anchor such as {"match": "<exact text from the document>"} (add "occurrence" if the text appears more than once) and a comment that explains the problem and proposes a concrete fix.
+3. Keep comments specific and actionable, and comment on the narrowest passage that shows the problem.
+4. Do not edit the document text itself; use create_suggestion if you want to propose exact replacement wording.
+
+Finish with a short overall assessment and the number of comments you left.`, doc.Title, focus, doc.DocumentId)
+
+	messages = append(messages, mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(instructions)))
+
+	return mcp.NewGetPromptResult("Review "+doc.Title, messages), nil
+}
+
+func meetingNotesPromptHandler(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	title := request.Params.Arguments["title"]
+	if title == "" {
+		return nil, fmt.Errorf("title is required")
+	}
+
+	doc, messages, err := documentPromptMessages(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	instructions := fmt.Sprintf(`Draft meeting notes titled "%s" using the template document "%s" attached above.
+
+1. Call copy_document with document_id "%s" and new_title "%s" to create the notes document. Never edit the template itself.
+2. Call get_document on the copy to get its Revision ID, and read_document_markdown to see its sections.
+3. Fill in each section of the template from the meeting notes with one batch_edit call: use replace_text operations with an anchor such as {"match": "<placeholder text>"}, and pass the Revision ID as revision_id so your edits never overwrite someone else's.
+4. Keep the template's headings and order. Leave a section's placeholder in place and mention it in your reply if the notes do not cover it.
+5. Record decisions and action items with their owners and due dates exactly as stated; do not invent any.
+
+Reply with the ID of the new document and the sections you could not fill.`, title, doc.Title, doc.DocumentId, title)
+
+	if notes := request.Params.Arguments["notes"]; notes != "" {
+		instructions += "\n\nMeeting notes:\n\n" + notes
+	} else {
+		instructions += "\n\nAsk me for the meeting notes or transcript before creating the document."
+	}
+
+	messages = append(messages, mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(instructions)))
+
+	return mcp.NewGetPromptResult("Meeting notes from "+doc.Title, messages), nil
+}
+
+func tightenSectionPromptHandler(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	documentID := request.Params.Arguments["document_id"]
+	section := request.Params.Arguments["section"]
+	if documentID == "" {
+		return nil, fmt.Errorf("document_id is required")
+	}
+	if section == "" {
+		return nil, fmt.Errorf("section is required")
+	}
+
+	docsService := services.GoogleDocsClient()
+
+	doc, err := docsService.Documents.Get(documentID).Context(ctx).Do()
+	if err != nil {
+		return nil, util.WrapError("get document for prompt", err)
+	}
+
+	start, end, err := util.ResolveAnchor(doc, &util.Anchor{Heading: section})
+	if err != nil {
+		return nil, err
+	}
+
+	outline, err := json.MarshalIndent(util.BuildOutline(doc), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	messages := []mcp.PromptMessage{
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewEmbeddedResource(mcp.TextResourceContents{
+			URI:      fmt.Sprintf("gdoc://%s/outline", doc.DocumentId),
+			MIMEType: "application/json",
+			Text:     string(outline),
+		})),
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewEmbeddedResource(mcp.TextResourceContents{
+			URI:      fmt.Sprintf("gdoc://%s", doc.DocumentId),
+			MIMEType: "text/markdown",
+			Text:     util.ConvertElementsToMarkdown(doc, sectionElements(doc, start, end)),
+		})),
+	}
+
+	instructions := fmt.Sprintf(`Tighten the prose of the section "%s" of the document "%s" (Document ID: %s, Revision ID: %s). The document outline and the current text of the section are attached above; the section spans indices %d-%d.
+
+1. Cut filler words, redundancy and hedging, prefer active voice and shorter sentences, and keep every fact, number, name and link.
+2. Do not change the heading, the meaning, or the section's structure of paragraphs, lists and tables.
+3. Apply all edits in one batch_edit call, with a replace_text operation per changed passage using an anchor such as {"match": "<exact original sentence>"}. Pass revision_id "%s" so the edit fails instead of overwriting changes made since this prompt was prepared.
+4. Leave passages that are already tight unchanged.
+
+Finish with a short list of what you changed.`, section, doc.Title, doc.DocumentId, doc.RevisionId, start, end, doc.RevisionId)
+
+	messages = append(messages, mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(instructions)))
+
+	return mcp.NewGetPromptResult(fmt.Sprintf("Tighten '%s' in %s", section, doc.Title), messages), nil
+}
+
+// documentPromptMessages reads the document named by the document_id argument
+// and returns it with a message embedding its Markdown content
+func documentPromptMessages(ctx context.Context, request mcp.GetPromptRequest) (*docs.Document, []mcp.PromptMessage, error) {
+	documentID := request.Params.Arguments["document_id"]
+	if documentID == "" {
+		return nil, nil, fmt.Errorf("document_id is required")
+	}
+
+	docsService := services.GoogleDocsClient()
+
+	doc, err := docsService.Documents.Get(documentID).Context(ctx).Do()
+	if err != nil {
+		return nil, nil, util.WrapError("get document for prompt", err)
+	}
+
+	messages := []mcp.PromptMessage{
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewEmbeddedResource(mcp.TextResourceContents{
+			URI:      fmt.Sprintf("gdoc://%s", doc.DocumentId),
+			MIMEType: "text/markdown",
+			Text:     fmt.Sprintf("# %s\n\nDocument ID: %s\nRevision ID: %s\n\n%s", doc.Title, doc.DocumentId, doc.RevisionId, util.ConvertToMarkdown(doc)),
+		})),
+	}
+
+	return doc, messages, nil
+}
+
+// sectionElements returns the body elements within an index range
+func sectionElements(doc *docs.Document, start, end int64) []*docs.StructuralElement {
+	var elements []*docs.StructuralElement
+	for _, element := range doc.Body.Content {
+		if element.StartIndex >= start && element.EndIndex <= end {
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
+
+// loadPromptTemplates loads the prompt templates in a directory. Each file
+// defines one prompt named after the file. An optional header between "---"
+// lines sets the description and the extra arguments, comma separated, with
+// optional arguments marked by a trailing "?":
+//
+//	---
+//	description: Draft a weekly status update
+//	arguments: audience, deadline?
+//	---
+//	Write a status update for {{.audience}} based on the document above.
+//
+// The body is a Go text/template that can use the arguments as well as
+// {{.document_id}} and {{.document_title}}. The document is always embedded
+// before the rendered text.
+func loadPromptTemplates(dir string) ([]*promptTemplate, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var templates []*promptTemplate
+	for _, entry := range entries {
+		extension := filepath.Ext(entry.Name())
+		if entry.IsDir() || !promptTemplateExtensions[extension] {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		t, err := parsePromptTemplate(strings.TrimSuffix(entry.Name(), extension), path)
+		if err != nil {
+			log.Printf("Skipping prompt template %s: %v", path, err)
+			continue
+		}
+		templates = append(templates, t)
+	}
+
+	return templates, nil
+}
+
+// parsePromptTemplate parses a prompt template file
+func parsePromptTemplate(name, path string) (*promptTemplate, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &promptTemplate{
+		Name:        strings.ReplaceAll(strings.TrimSpace(name), " ", "_"),
+		Description: "Team prompt " + name,
+	}
+
+	body := strings.ReplaceAll(string(content), "\r\n", "\n")
+	if rest, ok := strings.CutPrefix(body, "---\n"); ok {
+		header, remaining, found := strings.Cut(rest, "\n---\n")
+		if !found {
+			return nil, fmt.Errorf("header is not closed with ---")
+		}
+		body = remaining
+
+		scanner := bufio.NewScanner(strings.NewReader(header))
+		for scanner.Scan() {
+			key, value, _ := strings.Cut(scanner.Text(), ":")
+			value = strings.TrimSpace(value)
+
+			switch strings.TrimSpace(key) {
+			case "description":
+				t.Description = value
+			case "arguments":
+				for _, argument := range strings.Split(value, ",") {
+					argument = strings.TrimSpace(argument)
+					if argument == "" {
+						continue
+					}
+					argumentName, optional := strings.CutSuffix(argument, "?")
+					t.Arguments = append(t.Arguments, mcp.PromptArgument{Name: argumentName, Required: !optional})
+				}
+			}
+		}
+	}
+
+	t.Template, err = template.New(t.Name).Option("missingkey=zero").Parse(strings.TrimSpace(body))
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Prompt returns the prompt definition of a team template
+func (t *promptTemplate) Prompt() mcp.Prompt {
+	prompt := mcp.NewPrompt(t.Name,
+		mcp.WithPromptDescription(t.Description),
+		withDocumentArgument(),
+	)
+	prompt.Arguments = append(prompt.Arguments, t.Arguments...)
+	return prompt
+}
+
+// Handler returns the prompt handler of a team template, which embeds the
+// document and renders the template with the request arguments
+func (t *promptTemplate) Handler() server.PromptHandlerFunc {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		for _, argument := range t.Arguments {
+			if argument.Required && request.Params.Arguments[argument.Name] == "" {
+				return nil, fmt.Errorf("%s is required", argument.Name)
+			}
+		}
+
+		doc, messages, err := documentPromptMessages(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		data := make(map[string]string, len(request.Params.Arguments)+2)
+		for name, value := range request.Params.Arguments {
+			data[name] = value
+		}
+		data["document_id"] = doc.DocumentId
+		data["document_title"] = doc.Title
+
+		var text strings.Builder
+		if err := t.Template.Execute(&text, data); err != nil {
+			return nil, fmt.Errorf("failed to render prompt %s: %v", t.Name, err)
+		}
+
+		messages = append(messages, mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text.String())))
+
+		return mcp.NewGetPromptResult(t.Description, messages), nil
+	}
+}
@@ -0,0 +1,221 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hdbrzgr/docs-mcp/services"
+	"github.com/hdbrzgr/docs-mcp/util"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"google.golang.org/api/drive/v3"
+)
+
+// recentDocumentsCount is the number of recently modified documents listed as
+// resources
+const recentDocumentsCount = 20
+
+// documentOutline is the content of the gdoc://{document_id}/outline resource
+type documentOutline struct {
+	DocumentID string              `json:"document_id"`
+	Title      string              `json:"title"`
+	RevisionID string              `json:"revision_id"`
+	Headings   []util.OutlineEntry `json:"headings"`
+}
+
+// RegisterDocumentResources exposes documents as gdoc:// resources. The hooks
+// must be the ones the server was created with; they are used to add recently
+// modified documents to the resource list.
+func RegisterDocumentResources(s *server.MCPServer, hooks *server.Hooks) {
+	// Document body resource
+	documentTemplate := mcp.NewResourceTemplate("gdoc://{document_id}", "Google Doc",
+		mcp.WithTemplateDescription("The body of a Google Docs document as Markdown"),
+		mcp.WithTemplateMIMEType("text/markdown"),
+	)
+	s.AddResourceTemplate(documentTemplate, documentResourceHandler)
+
+	// Document outline resource
+	outlineTemplate := mcp.NewResourceTemplate("gdoc://{document_id}/outline", "Google Doc outline",
+		mcp.WithTemplateDescription("The headings of a Google Docs document with the index range of each section, as JSON"),
+		mcp.WithTemplateMIMEType("application/json"),
+	)
+	s.AddResourceTemplate(outlineTemplate, outlineResourceHandler)
+
+	// Document comments resource
+	commentsTemplate := mcp.NewResourceTemplate("gdoc://{document_id}/comments", "Google Doc comments",
+		mcp.WithTemplateDescription("The comments and replies on a Google Docs document, as JSON"),
+		mcp.WithTemplateMIMEType("application/json"),
+	)
+	s.AddResourceTemplate(commentsTemplate, commentsResourceHandler)
+
+	// Document revision resource
+	revisionTemplate := mcp.NewResourceTemplate("gdoc://{document_id}/revisions/{revision_id}", "Google Doc revision",
+		mcp.WithTemplateDescription("The content of a past revision of a Google Docs document, as Markdown when available and plain text otherwise"),
+		mcp.WithTemplateMIMEType("text/markdown"),
+	)
+	s.AddResourceTemplate(revisionTemplate, revisionResourceHandler)
+
+	// Recently modified documents are listed alongside the static resources
+	hooks.AddAfterListResources(listRecentDocuments)
+}
+
+func documentResourceHandler(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	documentID := resourceArgument(request, "document_id")
+	docsService := services.GoogleDocsClient()
+
+	doc, err := docsService.Documents.Get(documentID).Context(ctx).Do()
+	if err != nil {
+		return nil, util.WrapError("get document", err)
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "text/markdown",
+			Text:     util.ConvertToMarkdown(doc),
+		},
+	}, nil
+}
+
+func outlineResourceHandler(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	documentID := resourceArgument(request, "document_id")
+	docsService := services.GoogleDocsClient()
+
+	doc, err := docsService.Documents.Get(documentID).Context(ctx).Do()
+	if err != nil {
+		return nil, util.WrapError("get document outline", err)
+	}
+
+	outline := documentOutline{
+		DocumentID: doc.DocumentId,
+		Title:      doc.Title,
+		RevisionID: doc.RevisionId,
+		Headings:   util.BuildOutline(doc),
+	}
+	if outline.Headings == nil {
+		outline.Headings = []util.OutlineEntry{}
+	}
+
+	return jsonResourceContents(request.Params.URI, outline)
+}
+
+func commentsResourceHandler(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	documentID := resourceArgument(request, "document_id")
+	driveService := services.GoogleDriveClient()
+
+	comments := []*drive.Comment{}
+	err := driveService.Comments.List(documentID).
+		Fields("nextPageToken,comments(id,content,author(displayName,emailAddress),createdTime,modifiedTime,resolved,quotedFileContent,anchor,replies(id,content,author(displayName,emailAddress),createdTime,action))").
+		PageSize(100).
+		Pages(ctx, func(page *drive.CommentList) error {
+			comments = append(comments, page.Comments...)
+			return nil
+		})
+	if err != nil {
+		return nil, util.WrapError("list comments", err)
+	}
+
+	return jsonResourceContents(request.Params.URI, map[string]any{
+		"document_id": documentID,
+		"comments":    comments,
+	})
+}
+
+func revisionResourceHandler(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	documentID := resourceArgument(request, "document_id")
+	revisionID := resourceArgument(request, "revision_id")
+	driveService := services.GoogleDriveClient()
+
+	revision, err := driveService.Revisions.Get(documentID, revisionID).
+		Fields("id,exportLinks").
+		Context(ctx).
+		Do()
+	if err != nil {
+		return nil, util.WrapError("get revision", err)
+	}
+
+	// Prefer Markdown, which keeps headings and lists, over plain text
+	mimeType := exportFormats["md"].MimeType
+	if revision.ExportLinks[mimeType] == "" {
+		mimeType = exportFormats["txt"].MimeType
+	}
+
+	content, err := downloadRevision(ctx, revision, mimeType)
+	if err != nil {
+		return nil, util.WrapError("download revision", err)
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: mimeType,
+			Text:     normalizeExportedText(content),
+		},
+	}, nil
+}
+
+// listRecentDocuments adds the most recently modified documents to the last
+// page of the resource list, so clients can attach them without knowing their IDs
+func listRecentDocuments(ctx context.Context, id any, message *mcp.ListResourcesRequest, result *mcp.ListResourcesResult) {
+	if result.NextCursor != "" {
+		return
+	}
+
+	driveService := services.GoogleDriveClient()
+
+	filesList, err := driveService.Files.List().
+		Q(fmt.Sprintf("mimeType='%s' and trashed=false", googleDocsMimeType)).
+		OrderBy("modifiedTime desc").
+		PageSize(recentDocumentsCount).
+		Fields("files(id,name,modifiedTime)").
+		Context(ctx).
+		Do()
+	if err != nil {
+		log.Printf("Failed to list recent documents as resources: %v", err)
+		return
+	}
+
+	for _, file := range filesList.Files {
+		description := "Google Docs document"
+		if modifiedTime, err := time.Parse(time.RFC3339, file.ModifiedTime); err == nil {
+			description += fmt.Sprintf(", last modified %s", modifiedTime.Format("2006-01-02 15:04:05"))
+		}
+
+		result.Resources = append(result.Resources, mcp.NewResource("gdoc://"+file.Id, file.Name,
+			mcp.WithResourceDescription(description),
+			mcp.WithMIMEType("text/markdown"),
+		))
+	}
+}
+
+// resourceArgument returns a variable matched from a resource URI template
+func resourceArgument(request mcp.ReadResourceRequest, name string) string {
+	switch value := request.Params.Arguments[name].(type) {
+	case string:
+		return value
+	case []string:
+		return strings.Join(value, ",")
+	default:
+		return ""
+	}
+}
+
+// jsonResourceContents returns a value as the JSON content of a resource
+func jsonResourceContents(uri string, value any) ([]mcp.ResourceContents, error) {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      uri,
+			MIMEType: "application/json",
+			Text:     string(data),
+		},
+	}, nil
+}
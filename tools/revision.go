@@ -1,6 +1,7 @@
 package tools
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"strings"
@@ -11,8 +12,15 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
 )
 
+// maxPreviewChanges limits how many changed paragraphs a restore preview lists
+const maxPreviewChanges = 20
+
+// maxDiffLines limits the length of the diff returned by compare_revisions
+const maxDiffLines = 500
+
 // Input types for revision tools
 type ListRevisionsInput struct {
 	DocumentID string `json:"document_id" validate:"required"`
@@ -25,20 +33,67 @@ type GetRevisionInput struct {
 }
 
 type CompareRevisionsInput struct {
-	DocumentID    string `json:"document_id" validate:"required"`
-	RevisionID1   string `json:"revision_id1" validate:"required"`
-	RevisionID2   string `json:"revision_id2" validate:"required"`
+	DocumentID   string `json:"document_id" validate:"required"`
+	RevisionID1  string `json:"revision_id1" validate:"required"`
+	RevisionID2  string `json:"revision_id2" validate:"required"`
+	ContextLines *int   `json:"context_lines,omitempty"` // Unchanged paragraphs shown around each change
 }
 
 type RestoreRevisionInput struct {
-	DocumentID string `json:"document_id" validate:"required"`
-	RevisionID string `json:"revision_id" validate:"required"`
+	DocumentID         string `json:"document_id" validate:"required"`
+	RevisionID         string `json:"revision_id" validate:"required"`
+	Mode               string `json:"mode,omitempty"`    // in_place, copy
+	Preview            bool   `json:"preview,omitempty"` // Only show what would change
+	DocumentRevisionID string `json:"document_revision_id,omitempty"`
 }
 
 type ExportRevisionInput struct {
 	DocumentID string `json:"document_id" validate:"required"`
 	RevisionID string `json:"revision_id" validate:"required"`
-	Format     string `json:"format,omitempty"` // pdf, docx, odt, rtf, txt, html
+	Format     string `json:"format,omitempty"` // pdf, docx, odt, rtf, epub, txt, html, md
+}
+
+// Output types for revision tools
+type RevisionOutput struct {
+	ID              string `json:"id"`
+	ModifiedTime    string `json:"modified_time,omitempty"`
+	ModifiedBy      string `json:"modified_by,omitempty" jsonschema_description:"Display name of the user who made the revision"`
+	ModifiedByEmail string `json:"modified_by_email,omitempty"`
+	Size            int64  `json:"size,omitempty" jsonschema_description:"Size of the revision in bytes"`
+}
+
+type ListRevisionsOutput struct {
+	DocumentID string           `json:"document_id"`
+	Revisions  []RevisionOutput `json:"revisions"`
+	Count      int              `json:"count"`
+}
+
+type GetRevisionOutput struct {
+	DocumentID string `json:"document_id"`
+	RevisionOutput
+	OriginalFilename string            `json:"original_filename,omitempty"`
+	ExportLinks      map[string]string `json:"export_links,omitempty" jsonschema_description:"Download links by MIME type"`
+}
+
+type CompareRevisionsOutput struct {
+	DocumentID string         `json:"document_id"`
+	Revision1  RevisionOutput `json:"revision1"`
+	Revision2  RevisionOutput `json:"revision2"`
+	SizeChange int64          `json:"size_change,omitempty" jsonschema_description:"Size of the second revision minus the size of the first, in bytes"`
+	Summary    []string       `json:"summary" jsonschema_description:"Changes per section"`
+	Diff       string         `json:"diff,omitempty" jsonschema_description:"Paragraph-level unified diff with word-level changes marked inside edited paragraphs"`
+	Truncated  bool           `json:"truncated,omitempty" jsonschema_description:"Whether the diff was cut short"`
+}
+
+type RestoreRevisionOutput struct {
+	DocumentID         string `json:"document_id"`
+	RevisionID         string `json:"revision_id"`
+	Mode               string `json:"mode"`
+	Restored           bool   `json:"restored" jsonschema_description:"Whether anything was restored; false for previews"`
+	Changes            string `json:"changes" jsonschema_description:"Paragraphs that change when the revision is restored"`
+	RestoredDocumentID string `json:"restored_document_id,omitempty" jsonschema_description:"New document holding the revision in copy mode"`
+	SnapshotDocumentID string `json:"snapshot_document_id,omitempty" jsonschema_description:"Copy of the document taken before restoring in place"`
+	URL                string `json:"url,omitempty"`
 }
 
 func RegisterRevisionTools(s *server.MCPServer) {
@@ -47,6 +102,7 @@ func RegisterRevisionTools(s *server.MCPServer) {
 		mcp.WithDescription("List all revisions (version history) of a Google Docs document"),
 		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
 		mcp.WithNumber("max_count", mcp.Description("Maximum number of revisions to return (default: 10, max: 100)")),
+		mcp.WithOutputSchema[ListRevisionsOutput](),
 	)
 	s.AddTool(listRevisionsTool, mcp.NewTypedToolHandler(listRevisionsHandler))
 
@@ -55,32 +111,40 @@ func RegisterRevisionTools(s *server.MCPServer) {
 		mcp.WithDescription("Get detailed information about a specific revision of a Google Docs document"),
 		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
 		mcp.WithString("revision_id", mcp.Required(), mcp.Description("The ID of the revision to retrieve")),
+		mcp.WithOutputSchema[GetRevisionOutput](),
 	)
 	s.AddTool(getRevisionTool, mcp.NewTypedToolHandler(getRevisionHandler))
 
 	// Compare revisions tool
 	compareRevisionsTool := mcp.NewTool("compare_revisions",
-		mcp.WithDescription("Compare two revisions of a Google Docs document to see what changed"),
+		mcp.WithDescription("Compare the content of two revisions of a Google Docs document: a summary of changes per section, and a paragraph-level unified diff with word-level changes marked inside edited paragraphs"),
 		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
-		mcp.WithString("revision_id1", mcp.Required(), mcp.Description("The ID of the first revision to compare")),
-		mcp.WithString("revision_id2", mcp.Required(), mcp.Description("The ID of the second revision to compare")),
+		mcp.WithString("revision_id1", mcp.Required(), mcp.Description("The ID of the first (older) revision to compare")),
+		mcp.WithString("revision_id2", mcp.Required(), mcp.Description("The ID of the second (newer) revision to compare")),
+		mcp.WithNumber("context_lines", mcp.Description("Number of unchanged paragraphs to show around each change (default: 2)")),
+		mcp.WithOutputSchema[CompareRevisionsOutput](),
 	)
 	s.AddTool(compareRevisionsTool, mcp.NewTypedToolHandler(compareRevisionsHandler))
 
-	// Restore revision tool (Note: This creates a copy, as Google Docs doesn't allow direct restoration)
+	// Restore revision tool
 	restoreRevisionTool := mcp.NewTool("restore_revision",
-		mcp.WithDescription("Restore a document to a previous revision by creating a copy of that revision"),
+		mcp.WithDescription("Restore the content of a previous revision of a Google Docs document, either by rolling the document back in place (after saving a snapshot copy of its current state) or by creating a new document with the revision's content"),
 		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
 		mcp.WithString("revision_id", mcp.Required(), mcp.Description("The ID of the revision to restore")),
+		mcp.WithString("mode", mcp.Description("Restore mode: 'in_place' overwrites the document with the revision after snapshotting its current state, 'copy' creates a new document with the revision's content (default: 'in_place')")),
+		mcp.WithBoolean("preview", mcp.Description("Only show which paragraphs the restore would change, without modifying anything (default: false)")),
+		withRestoreWriteControl(),
+		mcp.WithOutputSchema[RestoreRevisionOutput](),
 	)
 	s.AddTool(restoreRevisionTool, mcp.NewTypedToolHandler(restoreRevisionHandler))
 
 	// Export revision tool
 	exportRevisionTool := mcp.NewTool("export_revision",
-		mcp.WithDescription("Export a specific revision of a Google Docs document in various formats"),
+		mcp.WithDescription("Download a specific revision of a Google Docs document in various formats. Text formats (txt, html, md) are returned inline; binary formats are returned as an embedded resource, or saved to the server's export directory when DOCS_EXPORT_DIR is set"),
 		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
 		mcp.WithString("revision_id", mcp.Required(), mcp.Description("The ID of the revision to export")),
-		mcp.WithString("format", mcp.Description("Export format: 'pdf', 'docx', 'odt', 'rtf', 'txt', or 'html' (default: 'pdf')")),
+		mcp.WithString("format", mcp.Description("Export format: 'pdf', 'docx', 'odt', 'rtf', 'epub', 'txt', 'html', or 'md' (default: 'pdf')")),
+		mcp.WithOutputSchema[ExportOutput](),
 	)
 	s.AddTool(exportRevisionTool, mcp.NewTypedToolHandler(exportRevisionHandler))
 }
@@ -107,8 +171,17 @@ func listRevisionsHandler(ctx context.Context, request mcp.CallToolRequest, inpu
 		return util.HandleGoogleAPIError("list revisions", err), nil
 	}
 
+	output := ListRevisionsOutput{
+		DocumentID: input.DocumentID,
+		Revisions:  []RevisionOutput{},
+		Count:      len(revisionsList.Revisions),
+	}
+	for _, revision := range revisionsList.Revisions {
+		output.Revisions = append(output.Revisions, revisionOutput(revision))
+	}
+
 	if len(revisionsList.Revisions) == 0 {
-		return mcp.NewToolResultText("No revisions found for this document."), nil
+		return mcp.NewToolResultStructured(output, "No revisions found for this document."), nil
 	}
 
 	var result strings.Builder
@@ -138,7 +211,7 @@ func listRevisionsHandler(ctx context.Context, request mcp.CallToolRequest, inpu
 		result.WriteString("\n")
 	}
 
-	return mcp.NewToolResultText(result.String()), nil
+	return mcp.NewToolResultStructured(output, result.String()), nil
 }
 
 func getRevisionHandler(ctx context.Context, request mcp.CallToolRequest, input GetRevisionInput) (*mcp.CallToolResult, error) {
@@ -187,7 +260,14 @@ func getRevisionHandler(ctx context.Context, request mcp.CallToolRequest, input
 		}
 	}
 
-	return mcp.NewToolResultText(result.String()), nil
+	output := GetRevisionOutput{
+		DocumentID:       input.DocumentID,
+		RevisionOutput:   revisionOutput(revision),
+		OriginalFilename: revision.OriginalFilename,
+		ExportLinks:      revision.ExportLinks,
+	}
+
+	return mcp.NewToolResultStructured(output, result.String()), nil
 }
 
 func compareRevisionsHandler(ctx context.Context, request mcp.CallToolRequest, input CompareRevisionsInput) (*mcp.CallToolResult, error) {
@@ -195,7 +275,7 @@ func compareRevisionsHandler(ctx context.Context, request mcp.CallToolRequest, i
 
 	// Get both revisions
 	revision1, err := driveService.Revisions.Get(input.DocumentID, input.RevisionID1).
-		Fields("id,modifiedTime,lastModifyingUser,size").
+		Fields("id,modifiedTime,lastModifyingUser,size,exportLinks").
 		Context(ctx).
 		Do()
 
@@ -204,7 +284,7 @@ func compareRevisionsHandler(ctx context.Context, request mcp.CallToolRequest, i
 	}
 
 	revision2, err := driveService.Revisions.Get(input.DocumentID, input.RevisionID2).
-		Fields("id,modifiedTime,lastModifyingUser,size").
+		Fields("id,modifiedTime,lastModifyingUser,size,exportLinks").
 		Context(ctx).
 		Do()
 
@@ -260,20 +340,88 @@ func compareRevisionsHandler(ctx context.Context, request mcp.CallToolRequest, i
 		result.WriteString("\n")
 	}
 	
-	result.WriteString("\nNote: For detailed content comparison, you can export both revisions and compare them externally.")
+	// Markdown keeps headings and tables, so changes can be attributed to sections
+	mimeType := "text/plain"
+	if revision1.ExportLinks["text/markdown"] != "" && revision2.ExportLinks["text/markdown"] != "" {
+		mimeType = "text/markdown"
+	}
 
-	return mcp.NewToolResultText(result.String()), nil
+	content1, err := downloadRevision(ctx, revision1, mimeType)
+	if err != nil {
+		return util.HandleGoogleAPIError("download first revision content", err), nil
+	}
+
+	content2, err := downloadRevision(ctx, revision2, mimeType)
+	if err != nil {
+		return util.HandleGoogleAPIError("download second revision content", err), nil
+	}
+
+	contextLines := 2
+	if input.ContextLines != nil && *input.ContextLines >= 0 {
+		contextLines = *input.ContextLines
+	}
+
+	ops := util.PairModified(util.Diff(
+		nonEmptyParagraphs(normalizeExportedText(content1)),
+		nonEmptyParagraphs(normalizeExportedText(content2)),
+	))
+
+	output := CompareRevisionsOutput{
+		DocumentID: input.DocumentID,
+		Revision1:  revisionOutput(revision1),
+		Revision2:  revisionOutput(revision2),
+		Summary:    []string{},
+	}
+	if revision1.Size > 0 && revision2.Size > 0 {
+		output.SizeChange = revision2.Size - revision1.Size
+	}
+
+	result.WriteString("\n--- Summary ---\n")
+	summary := util.SummarizeDiff(ops)
+	if len(summary) == 0 {
+		result.WriteString("No content changes between these revisions.\n")
+		return mcp.NewToolResultStructured(output, result.String()), nil
+	}
+	output.Summary = summary
+	for _, line := range summary {
+		result.WriteString(fmt.Sprintf("- %s\n", line))
+	}
+
+	diff := util.UnifiedDiff(ops, contextLines, "revision "+revision1.Id, "revision "+revision2.Id)
+	lines := strings.SplitAfter(diff, "\n")
+	if len(lines) > maxDiffLines {
+		diff = strings.Join(lines[:maxDiffLines], "") + fmt.Sprintf("... diff truncated, %d more lines\n", len(lines)-maxDiffLines)
+		output.Truncated = true
+	}
+	output.Diff = diff
+
+	result.WriteString("\n--- Diff ---\n")
+	result.WriteString("Lines: '  ' unchanged, '- ' removed, '+ ' added, '~ ' edited with [-removed-] and {+added+} words\n\n")
+	result.WriteString(diff)
+
+	return mcp.NewToolResultStructured(output, result.String()), nil
 }
 
 func restoreRevisionHandler(ctx context.Context, request mcp.CallToolRequest, input RestoreRevisionInput) (*mcp.CallToolResult, error) {
 	driveService := services.GoogleDriveClient()
 
-	// Note: Google Drive API doesn't support directly restoring a revision
-	// Instead, we create a copy of the document at that revision
-	
-	// Get the original file name
+	mode := input.Mode
+	if mode == "" {
+		mode = "in_place"
+	}
+	if mode != "in_place" && mode != "copy" {
+		return mcp.NewToolResultError("Error: Invalid mode. Must be 'in_place' or 'copy'."), nil
+	}
+
+	// revision_id names the revision to restore, so the revision the caller
+	// read is given as document_revision_id
+	writeControl, errResult := newWriteControl(input.DocumentRevisionID, "")
+	if errResult != nil {
+		return errResult, nil
+	}
+
 	file, err := driveService.Files.Get(input.DocumentID).
-		Fields("name").
+		Fields("id,name,mimeType,parents").
 		Context(ctx).
 		Do()
 
@@ -281,50 +429,145 @@ func restoreRevisionHandler(ctx context.Context, request mcp.CallToolRequest, in
 		return util.HandleGoogleAPIError("get document info for restoration", err), nil
 	}
 
-	// Create a copy with the revision content
-	copyName := fmt.Sprintf("%s (Restored from revision %s)", file.Name, input.RevisionID)
-	
-	copiedFile, err := driveService.Files.Copy(input.DocumentID, &drive.File{
-		Name: copyName,
+	if file.MimeType != googleDocsMimeType {
+		return mcp.NewToolResultError(fmt.Sprintf("Error: File is not a Google Docs document (MIME type: %s).", file.MimeType)), nil
+	}
+
+	revision, err := driveService.Revisions.Get(input.DocumentID, input.RevisionID).
+		Fields("id,modifiedTime,exportLinks").
+		Context(ctx).
+		Do()
+
+	if err != nil {
+		return util.HandleGoogleAPIError("get revision for restoration", err), nil
+	}
+
+	// Compare the plain text of both versions for the preview
+	revisionText, err := downloadRevision(ctx, revision, "text/plain")
+	if err != nil {
+		return util.HandleGoogleAPIError("download revision text", err), nil
+	}
+
+	currentText, err := exportDocument(ctx, input.DocumentID, "text/plain")
+	if err != nil {
+		return util.HandleGoogleAPIError("export current document text", err), nil
+	}
+
+	preview := restorePreview(normalizeExportedText(currentText), normalizeExportedText(revisionText))
+
+	output := RestoreRevisionOutput{
+		DocumentID: input.DocumentID,
+		RevisionID: input.RevisionID,
+		Mode:       mode,
+		Changes:    preview,
+	}
+
+	if input.Preview {
+		result := fmt.Sprintf("Restore preview (no changes made):\n\nDocument ID: %s\nRevision ID: %s\nMode: %s\n\n%s",
+			input.DocumentID, input.RevisionID, mode, preview)
+		return mcp.NewToolResultStructured(output, result), nil
+	}
+
+	// Word format keeps headings, lists, tables, images and styles when Drive
+	// converts the content back into a Google Doc
+	content, err := downloadRevision(ctx, revision, docxMimeType)
+	if err != nil {
+		return util.HandleGoogleAPIError("download revision content", err), nil
+	}
+
+	if mode == "copy" {
+		restoredFile, err := driveService.Files.Create(&drive.File{
+			Name:     fmt.Sprintf("%s (Restored from revision %s)", file.Name, input.RevisionID),
+			MimeType: googleDocsMimeType,
+			Parents:  file.Parents,
+		}).
+			Media(bytes.NewReader(content), googleapi.ContentType(docxMimeType)).
+			Fields("id,name").
+			Context(ctx).
+			Do()
+
+		if err != nil {
+			return util.HandleGoogleAPIError("create document from revision", err), nil
+		}
+
+		result := fmt.Sprintf("Revision restored to a new document successfully!\n\nOriginal Document ID: %s\nRevision ID: %s\nRestored Document ID: %s\nRestored Document Name: %s\nURL: https://docs.google.com/document/d/%s/edit\n\n%s",
+			input.DocumentID, input.RevisionID, restoredFile.Id, restoredFile.Name, restoredFile.Id, preview)
+
+		output.Restored = true
+		output.RestoredDocumentID = restoredFile.Id
+		output.URL = documentURL(restoredFile.Id)
+
+		return mcp.NewToolResultStructured(output, result), nil
+	}
+
+	// Overwriting replaces the whole document, so make sure it is still the
+	// revision the caller read before touching it
+	if writeControl != nil {
+		docsService := services.GoogleDocsClient()
+		doc, err := docsService.Documents.Get(input.DocumentID).Fields("revisionId").Context(ctx).Do()
+		if err != nil {
+			return util.HandleGoogleAPIError("get document revision for restoration", err), nil
+		}
+		if doc.RevisionId != writeControl.RequiredRevisionId {
+			return util.DocumentChangedError(input.DocumentID, writeControl.RequiredRevisionId, doc.RevisionId), nil
+		}
+	}
+
+	// Snapshot the current state before overwriting it
+	snapshot, err := driveService.Files.Copy(input.DocumentID, &drive.File{
+		Name:    fmt.Sprintf("%s (Snapshot before restoring revision %s)", file.Name, input.RevisionID),
+		Parents: file.Parents,
 	}).
+		Fields("id,name").
 		Context(ctx).
 		Do()
 
 	if err != nil {
-		return util.HandleGoogleAPIError("create copy for revision restoration", err), nil
+		return util.HandleGoogleAPIError("snapshot document before restoration", err), nil
 	}
 
-	result := fmt.Sprintf("Revision restored successfully!\n\nNote: Google Docs doesn't support direct revision restoration, so a copy was created.\n\nOriginal Document ID: %s\nRevision ID: %s\nRestored Copy ID: %s\nRestored Copy Name: %s\nURL: https://docs.google.com/document/d/%s/edit\n\nTo complete the restoration, you can:\n1. Copy content from the restored document\n2. Replace content in the original document\n3. Or rename the documents as needed",
-		input.DocumentID, input.RevisionID, copiedFile.Id, copiedFile.Name, copiedFile.Id)
+	_, err = driveService.Files.Update(input.DocumentID, &drive.File{}).
+		Media(bytes.NewReader(content), googleapi.ContentType(docxMimeType)).
+		Context(ctx).
+		Do()
 
-	return mcp.NewToolResultText(result), nil
+	if err != nil {
+		return util.HandleGoogleAPIError("overwrite document with revision", err), nil
+	}
+
+	result := fmt.Sprintf("Revision restored successfully!\n\nDocument ID: %s\nRestored Revision ID: %s\nSnapshot Document ID: %s\nSnapshot Name: %s\nURL: https://docs.google.com/document/d/%s/edit\n\nThe previous content is kept in the snapshot document.\n\n%s",
+		input.DocumentID, input.RevisionID, snapshot.Id, snapshot.Name, input.DocumentID, preview)
+
+	output.Restored = true
+	output.SnapshotDocumentID = snapshot.Id
+	output.URL = documentURL(input.DocumentID)
+
+	return mcp.NewToolResultStructured(output, result), nil
 }
 
 func exportRevisionHandler(ctx context.Context, request mcp.CallToolRequest, input ExportRevisionInput) (*mcp.CallToolResult, error) {
 	driveService := services.GoogleDriveClient()
 
 	// Set default format if not provided
-	format := input.Format
-	if format == "" {
-		format = "pdf"
+	formatName := input.Format
+	if formatName == "" {
+		formatName = "pdf"
 	}
 
-	// Validate format
-	validFormats := map[string]string{
-		"pdf":  "application/pdf",
-		"docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
-		"odt":  "application/vnd.oasis.opendocument.text",
-		"rtf":  "application/rtf",
-		"txt":  "text/plain",
-		"html": "text/html",
+	format, ok := exportFormats[formatName]
+	if !ok {
+		return mcp.NewToolResultError(fmt.Sprintf("Error: Invalid format. Must be one of: %s", exportFormatNames())), nil
 	}
 
-	mimeType, ok := validFormats[format]
-	if !ok {
-		return mcp.NewToolResultText("Error: Invalid format. Must be one of: pdf, docx, odt, rtf, txt, html"), nil
+	file, err := driveService.Files.Get(input.DocumentID).
+		Fields("name").
+		Context(ctx).
+		Do()
+
+	if err != nil {
+		return util.HandleGoogleAPIError("get document info for export", err), nil
 	}
 
-	// Get the revision to check if it exists
 	revision, err := driveService.Revisions.Get(input.DocumentID, input.RevisionID).
 		Fields("id,exportLinks").
 		Context(ctx).
@@ -334,18 +577,109 @@ func exportRevisionHandler(ctx context.Context, request mcp.CallToolRequest, inp
 		return util.HandleGoogleAPIError("get revision for export", err), nil
 	}
 
-	// Check if the export link exists for this format
-	var exportLink string
-	if revision.ExportLinks != nil {
-		exportLink = revision.ExportLinks[mimeType]
+	if revision.ExportLinks[format.MimeType] == "" {
+		return mcp.NewToolResultError(fmt.Sprintf("Error: Export format '%s' is not available for this revision.", formatName)), nil
+	}
+
+	content, err := downloadRevision(ctx, revision, format.MimeType)
+	if err != nil {
+		return util.HandleGoogleAPIError("download revision export", err), nil
+	}
+
+	header := fmt.Sprintf("Revision exported successfully!\n\nDocument ID: %s\nRevision ID: %s\nFormat: %s\nMIME Type: %s\n",
+		input.DocumentID, input.RevisionID, formatName, format.MimeType)
+	uri := fmt.Sprintf("gdoc://%s/revisions/%s", input.DocumentID, input.RevisionID)
+	fileName := exportFileName(fmt.Sprintf("%s (revision %s)", file.Name, input.RevisionID))
+
+	output := ExportOutput{
+		DocumentID: input.DocumentID,
+		RevisionID: input.RevisionID,
+		Format:     formatName,
+		MimeType:   format.MimeType,
+	}
+
+	return exportResult(header, uri, fileName, format, content, output), nil
+}
+
+// revisionOutput converts a Drive revision to its structured form
+func revisionOutput(revision *drive.Revision) RevisionOutput {
+	output := RevisionOutput{
+		ID:           revision.Id,
+		ModifiedTime: revision.ModifiedTime,
+		Size:         revision.Size,
+	}
+	if revision.LastModifyingUser != nil {
+		output.ModifiedBy = revision.LastModifyingUser.DisplayName
+		output.ModifiedByEmail = revision.LastModifyingUser.EmailAddress
+	}
+	return output
+}
+
+// nonEmptyParagraphs splits exported text into paragraphs, skipping blank lines
+// so spacing changes do not show up as content changes
+func nonEmptyParagraphs(text string) []string {
+	var paragraphs []string
+	for _, paragraph := range util.SplitParagraphs(text) {
+		if strings.TrimSpace(paragraph) != "" {
+			paragraphs = append(paragraphs, strings.TrimRight(paragraph, " "))
+		}
 	}
+	return paragraphs
+}
 
-	if exportLink == "" {
-		return mcp.NewToolResultText(fmt.Sprintf("Error: Export format '%s' is not available for this revision.", format)), nil
+// restorePreview summarizes which paragraphs change when the current text is
+// replaced by the revision text
+func restorePreview(currentText, revisionText string) string {
+	current := nonEmptyParagraphs(currentText)
+	restored := nonEmptyParagraphs(revisionText)
+	ops := util.PairModified(util.Diff(current, restored))
+
+	var sb strings.Builder
+	sb.WriteString("--- Changes ---\n")
+	sb.WriteString(fmt.Sprintf("Current Paragraphs: %d\n", len(current)))
+	sb.WriteString(fmt.Sprintf("Revision Paragraphs: %d\n", len(restored)))
+
+	summary := util.SummarizeDiff(ops)
+	if len(summary) == 0 {
+		sb.WriteString("\nThe revision has the same text as the current document.\n")
+		return sb.String()
+	}
+	for _, line := range summary {
+		sb.WriteString(fmt.Sprintf("- %s\n", line))
 	}
 
-	result := fmt.Sprintf("Revision export information:\n\nDocument ID: %s\nRevision ID: %s\nFormat: %s\nMIME Type: %s\nExport Link: %s\n\nNote: Use the export link to download the revision in the specified format. The link may require authentication.",
-		input.DocumentID, input.RevisionID, format, mimeType, exportLink)
+	sb.WriteString("\n")
+	changed, shown := 0, 0
+	for _, op := range ops {
+		if op.Kind == util.DiffEqual {
+			continue
+		}
+		changed++
+		if shown == maxPreviewChanges {
+			continue
+		}
+		switch op.Kind {
+		case util.DiffDelete:
+			sb.WriteString(fmt.Sprintf("- %s\n", truncateText(op.Text, 120)))
+		case util.DiffInsert:
+			sb.WriteString(fmt.Sprintf("+ %s\n", truncateText(op.Text, 120)))
+		case util.DiffModify:
+			sb.WriteString(fmt.Sprintf("~ %s\n", truncateText(util.WordDiff(op.Previous, op.Text), 160)))
+		}
+		shown++
+	}
+	if changed > shown {
+		sb.WriteString(fmt.Sprintf("... and %d more changed paragraphs\n", changed-shown))
+	}
 
-	return mcp.NewToolResultText(result), nil
+	return sb.String()
+}
+
+// truncateText shortens text to at most limit runes, marking the cut with an ellipsis
+func truncateText(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit]) + "..."
 }
@@ -0,0 +1,561 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hdbrzgr/docs-mcp/services"
+	"github.com/hdbrzgr/docs-mcp/util"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"google.golang.org/api/docs/v1"
+	"google.golang.org/api/drive/v3"
+)
+
+// defaultSearchContext is the number of characters shown on each side of a match
+const defaultSearchContext = 40
+
+// snippetFetchers is the number of documents read at once for search snippets
+const snippetFetchers = 5
+
+// Input types for search tools
+type SearchDocumentInput struct {
+	DocumentID   string `json:"document_id" validate:"required"`
+	Query        string `json:"query" validate:"required"`
+	Regex        bool   `json:"regex,omitempty"`
+	MatchCase    bool   `json:"match_case,omitempty"`
+	WholeWord    bool   `json:"whole_word,omitempty"`
+	Scope        string `json:"scope,omitempty"` // all, body, headers, footers, footnotes
+	MaxResults   int    `json:"max_results,omitempty"`
+	ContextChars int    `json:"context_chars,omitempty"`
+}
+
+type SearchDocumentsInput struct {
+	Text           string `json:"text,omitempty"`
+	Title          string `json:"title,omitempty"`
+	Owner          string `json:"owner,omitempty"`
+	ModifiedAfter  string `json:"modified_after,omitempty"`
+	ModifiedBefore string `json:"modified_before,omitempty"`
+	FolderID       string `json:"folder_id,omitempty"`
+	Starred        bool   `json:"starred,omitempty"`
+	SharedWithMe   bool   `json:"shared_with_me,omitempty"`
+	MaxResults     int    `json:"max_results,omitempty"`
+}
+
+// Output types for search tools
+type SearchDocumentOutput struct {
+	DocumentID string        `json:"document_id"`
+	RevisionID string        `json:"revision_id"`
+	Query      string        `json:"query"`
+	Matches    []SearchMatch `json:"matches"`
+	Count      int           `json:"count" jsonschema_description:"Total number of matches, including any beyond max_results"`
+	Truncated  bool          `json:"truncated,omitempty" jsonschema_description:"Whether matches were left out because of max_results"`
+}
+
+type SearchMatch struct {
+	Segment    string `json:"segment" jsonschema_description:"Where the match is: body, header, footer or footnote"`
+	SegmentID  string `json:"segment_id,omitempty" jsonschema_description:"ID of the header, footer or footnote; its indices are relative to that segment"`
+	StartIndex int64  `json:"start_index"`
+	EndIndex   int64  `json:"end_index"`
+	Text       string `json:"text" jsonschema_description:"The matched text"`
+	Heading    string `json:"heading,omitempty" jsonschema_description:"Text of the closest heading before a body match"`
+	HeadingID  string `json:"heading_id,omitempty"`
+	InTable    bool   `json:"in_table,omitempty"`
+	Context    string `json:"context" jsonschema_description:"The match with the text around it, newlines shown as spaces"`
+}
+
+type SearchDocumentsOutput struct {
+	Query   string                 `json:"query" jsonschema_description:"The Drive query that was run"`
+	Results []SearchDocumentResult `json:"results"`
+	Count   int                    `json:"count"`
+}
+
+type SearchDocumentResult struct {
+	DriveFileOutput
+	Rank    int    `json:"rank"`
+	Matches int    `json:"matches" jsonschema_description:"Occurrences of the search text, or of its words, in the document body"`
+	Snippet string `json:"snippet,omitempty" jsonschema_description:"Text around the first match, or the start of the document when no text was searched for"`
+}
+
+// searchSegment is a part of a document searched separately, with its own indices
+type searchSegment struct {
+	kind     string
+	id       string
+	elements []*docs.StructuralElement
+}
+
+func RegisterSearchTools(s *server.MCPServer) {
+	// Search document tool
+	searchDocumentTool := mcp.NewTool("search_document",
+		mcp.WithDescription("Find every occurrence of a phrase or regular expression in a Google Docs document, including tables, headers, footers and footnotes. Returns each match with its index range, the heading it falls under and the surrounding text"),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
+		mcp.WithString("query", mcp.Required(), mcp.Description("Text to find, or a regular expression when regex is true")),
+		mcp.WithBoolean("regex", mcp.Description("Treat the query as a regular expression (RE2 syntax) (default: false)")),
+		mcp.WithBoolean("match_case", mcp.Description("Whether to match case (default: false)")),
+		mcp.WithBoolean("whole_word", mcp.Description("Only match whole words (default: false)")),
+		mcp.WithString("scope", mcp.Description("Where to search: 'all', 'body', 'headers', 'footers', or 'footnotes' (default: 'all'); tables are part of the body")),
+		mcp.WithNumber("max_results", mcp.Description("Maximum number of matches to return (default: 100, max: 1000)")),
+		mcp.WithNumber("context_chars", mcp.Description(fmt.Sprintf("Characters of surrounding text to show on each side of a match (default: %d)", defaultSearchContext))),
+		mcp.WithOutputSchema[SearchDocumentOutput](),
+	)
+	s.AddTool(searchDocumentTool, mcp.NewTypedToolHandler(searchDocumentHandler))
+
+	// Search documents tool
+	searchDocumentsTool := mcp.NewTool("search_documents",
+		mcp.WithDescription("Search Google Docs documents in Drive by content, title, owner, modification date, folder, star or sharing. Returns the matching documents ranked by relevance, each with a snippet of its text around the first match"),
+		mcp.WithString("text", mcp.Description("Words or phrase to find in the document content or title")),
+		mcp.WithString("title", mcp.Description("Text the document title must contain")),
+		mcp.WithString("owner", mcp.Description("Email address of the owner, or 'me'")),
+		mcp.WithString("modified_after", mcp.Description("Only documents modified after this date or time (e.g., '2024-01-31' or '2024-01-31T09:00:00Z')")),
+		mcp.WithString("modified_before", mcp.Description("Only documents modified before this date or time (e.g., '2024-01-31' or '2024-01-31T09:00:00Z')")),
+		mcp.WithString("folder_id", mcp.Description("Only documents directly inside this Drive folder")),
+		mcp.WithBoolean("starred", mcp.Description("Only starred documents (default: false)")),
+		mcp.WithBoolean("shared_with_me", mcp.Description("Only documents shared with the authenticated user (default: false)")),
+		mcp.WithNumber("max_results", mcp.Description("Maximum number of documents to return (default: 10, max: 50)")),
+		mcp.WithOutputSchema[SearchDocumentsOutput](),
+	)
+	s.AddTool(searchDocumentsTool, mcp.NewTypedToolHandler(searchDocumentsHandler))
+}
+
+func searchDocumentHandler(ctx context.Context, request mcp.CallToolRequest, input SearchDocumentInput) (*mcp.CallToolResult, error) {
+	docsService := services.GoogleDocsClient()
+
+	scope := input.Scope
+	if scope == "" {
+		scope = "all"
+	}
+	validScopes := map[string]bool{
+		"all":       true,
+		"body":      true,
+		"headers":   true,
+		"footers":   true,
+		"footnotes": true,
+	}
+	if !validScopes[scope] {
+		return mcp.NewToolResultError("Error: Invalid scope. Must be 'all', 'body', 'headers', 'footers', or 'footnotes'."), nil
+	}
+
+	maxResults := input.MaxResults
+	if maxResults <= 0 {
+		maxResults = 100
+	}
+	if maxResults > 1000 {
+		maxResults = 1000
+	}
+
+	contextChars := input.ContextChars
+	if contextChars <= 0 {
+		contextChars = defaultSearchContext
+	}
+
+	re, err := searchPattern(input.Query, input.Regex, input.MatchCase)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error: Invalid regular expression: %v", err)), nil
+	}
+
+	doc, err := docsService.Documents.Get(input.DocumentID).Context(ctx).Do()
+	if err != nil {
+		return util.HandleGoogleAPIError("get document for search", err), nil
+	}
+
+	outline := util.BuildOutline(doc)
+
+	output := SearchDocumentOutput{
+		DocumentID: doc.DocumentId,
+		RevisionID: doc.RevisionId,
+		Query:      input.Query,
+		Matches:    []SearchMatch{},
+	}
+
+	for _, segment := range searchSegments(doc, scope) {
+		textMap := util.NewTextMapFromElements(segment.elements)
+		for _, match := range textMap.FindAllRegexp(re) {
+			if input.WholeWord && !isWholeWord(textMap.Text, match.TextStart, match.TextEnd) {
+				continue
+			}
+
+			output.Count++
+			if len(output.Matches) == maxResults {
+				output.Truncated = true
+				continue
+			}
+
+			result := SearchMatch{
+				Segment:    segment.kind,
+				SegmentID:  segment.id,
+				StartIndex: match.StartIndex,
+				EndIndex:   match.EndIndex,
+				Text:       textMap.Text[match.TextStart:match.TextEnd],
+				Context:    matchContext(textMap.Text, match.TextStart, match.TextEnd, contextChars),
+			}
+			if segment.kind == "body" {
+				for _, heading := range outline {
+					if heading.StartIndex > match.StartIndex {
+						break
+					}
+					result.Heading = heading.Text
+					result.HeadingID = heading.HeadingID
+				}
+				result.InTable = inTable(segment.elements, match.StartIndex)
+			}
+			output.Matches = append(output.Matches, result)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Search results for '%s'\n\nDocument ID: %s\nRevision ID: %s\nMatches: %d\n",
+		input.Query, doc.DocumentId, doc.RevisionId, output.Count))
+
+	if output.Count == 0 {
+		sb.WriteString("\nNo matches found.\n")
+		return mcp.NewToolResultStructured(output, sb.String()), nil
+	}
+	if output.Truncated {
+		sb.WriteString(fmt.Sprintf("Showing the first %d matches\n", len(output.Matches)))
+	}
+
+	sb.WriteString("\n")
+	for i, match := range output.Matches {
+		location := "Body"
+		switch {
+		case match.Segment != "body":
+			location = fmt.Sprintf("%s%s %s", strings.ToUpper(match.Segment[:1]), match.Segment[1:], match.SegmentID)
+		case match.InTable:
+			location = "Table"
+		}
+		if match.Heading != "" {
+			location += fmt.Sprintf(" under \"%s\"", match.Heading)
+		}
+
+		sb.WriteString(fmt.Sprintf("%d. Range: %d-%d (%s)\n", i+1, match.StartIndex, match.EndIndex, location))
+		sb.WriteString(fmt.Sprintf("   %s\n", match.Context))
+	}
+
+	return mcp.NewToolResultStructured(output, sb.String()), nil
+}
+
+// searchPattern compiles a search query, quoting it unless it is a regular
+// expression
+func searchPattern(query string, isRegex, matchCase bool) (*regexp.Regexp, error) {
+	pattern := query
+	if !isRegex {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if !matchCase {
+		pattern = "(?i)" + pattern
+	}
+	return regexp.Compile(pattern)
+}
+
+func searchDocumentsHandler(ctx context.Context, request mcp.CallToolRequest, input SearchDocumentsInput) (*mcp.CallToolResult, error) {
+	driveService := services.GoogleDriveClient()
+
+	query, err := buildDocumentQuery(input)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error: %v", err)), nil
+	}
+
+	maxResults := input.MaxResults
+	if maxResults <= 0 {
+		maxResults = 10
+	}
+	if maxResults > 50 {
+		maxResults = 50
+	}
+
+	// Drive orders full text searches by relevance and does not accept an order
+	// for them, so the most recently modified come first only for other searches
+	call := driveService.Files.List().
+		Q(query).
+		PageSize(int64(maxResults)).
+		Fields("nextPageToken,files(id,name,mimeType,createdTime,modifiedTime,owners,webViewLink)")
+	if input.Text == "" {
+		call = call.OrderBy("modifiedTime desc")
+	}
+
+	var files []*drive.File
+	pageToken := ""
+	for len(files) < maxResults {
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		filesList, err := call.Context(ctx).Do()
+		if err != nil {
+			return util.HandleGoogleAPIError("search documents", err), nil
+		}
+		files = append(files, filesList.Files...)
+		pageToken = filesList.NextPageToken
+		if pageToken == "" {
+			break
+		}
+	}
+	if len(files) > maxResults {
+		files = files[:maxResults]
+	}
+
+	output := SearchDocumentsOutput{
+		Query:   query,
+		Results: make([]SearchDocumentResult, len(files)),
+		Count:   len(files),
+	}
+
+	// Read the documents a few at a time for their snippets
+	docsService := services.GoogleDocsClient()
+	var wg sync.WaitGroup
+	fetchers := make(chan struct{}, snippetFetchers)
+	for i, file := range files {
+		output.Results[i].DriveFileOutput = driveFileOutput(file)
+
+		wg.Add(1)
+		go func(result *SearchDocumentResult) {
+			defer wg.Done()
+			fetchers <- struct{}{}
+			defer func() { <-fetchers }()
+
+			doc, err := docsService.Documents.Get(result.ID).Context(ctx).Do()
+			if err != nil {
+				return
+			}
+			result.Matches, result.Snippet = documentSnippet(util.ExtractPlainText(doc), input.Text)
+		}(&output.Results[i])
+	}
+	wg.Wait()
+
+	// Rank by the number of matches, then by title matches, keeping Drive's
+	// order otherwise
+	if input.Text != "" {
+		sort.SliceStable(output.Results, func(i, j int) bool {
+			a, b := output.Results[i], output.Results[j]
+			if a.Matches != b.Matches {
+				return a.Matches > b.Matches
+			}
+			return titleMatches(a.Name, input.Text) && !titleMatches(b.Name, input.Text)
+		})
+	}
+	for i := range output.Results {
+		output.Results[i].Rank = i + 1
+	}
+
+	if output.Count == 0 {
+		return mcp.NewToolResultStructured(output, fmt.Sprintf("No documents found.\n\nQuery: %s", query)), nil
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Found %d Google Docs documents:\n\nQuery: %s\n\n", output.Count, query))
+	for _, doc := range output.Results {
+		result.WriteString(fmt.Sprintf("%d. %s\n", doc.Rank, doc.Name))
+		result.WriteString(fmt.Sprintf("   ID: %s\n", doc.ID))
+		if doc.ModifiedTime != "" {
+			result.WriteString(fmt.Sprintf("   Modified: %s\n", doc.ModifiedTime))
+		}
+		if input.Text != "" {
+			result.WriteString(fmt.Sprintf("   Matches: %d\n", doc.Matches))
+		}
+		if doc.Snippet != "" {
+			result.WriteString(fmt.Sprintf("   %s\n", doc.Snippet))
+		}
+		result.WriteString("\n")
+	}
+
+	return mcp.NewToolResultStructured(output, result.String()), nil
+}
+
+// buildDocumentQuery translates the search parameters into a Drive query for
+// Google Docs documents, quoting every value
+func buildDocumentQuery(input SearchDocumentsInput) (string, error) {
+	conditions := []string{
+		fmt.Sprintf("mimeType='%s'", googleDocsMimeType),
+		"trashed=false",
+	}
+
+	if input.Text != "" {
+		conditions = append(conditions, fmt.Sprintf("fullText contains %s", driveQueryString(input.Text)))
+	}
+	if input.Title != "" {
+		conditions = append(conditions, fmt.Sprintf("name contains %s", driveQueryString(input.Title)))
+	}
+	if input.Owner != "" {
+		conditions = append(conditions, fmt.Sprintf("%s in owners", driveQueryString(input.Owner)))
+	}
+	if input.ModifiedAfter != "" {
+		modified, err := driveQueryTime(input.ModifiedAfter)
+		if err != nil {
+			return "", fmt.Errorf("invalid modified_after: %v", err)
+		}
+		conditions = append(conditions, fmt.Sprintf("modifiedTime > %s", modified))
+	}
+	if input.ModifiedBefore != "" {
+		modified, err := driveQueryTime(input.ModifiedBefore)
+		if err != nil {
+			return "", fmt.Errorf("invalid modified_before: %v", err)
+		}
+		conditions = append(conditions, fmt.Sprintf("modifiedTime < %s", modified))
+	}
+	if input.FolderID != "" {
+		conditions = append(conditions, fmt.Sprintf("%s in parents", driveQueryString(input.FolderID)))
+	}
+	if input.Starred {
+		conditions = append(conditions, "starred=true")
+	}
+	if input.SharedWithMe {
+		conditions = append(conditions, "sharedWithMe=true")
+	}
+
+	return strings.Join(conditions, " and "), nil
+}
+
+// driveQueryString quotes a value for a Drive query, escaping backslashes and
+// single quotes
+func driveQueryString(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, "'", `\'`)
+	return "'" + value + "'"
+}
+
+// driveQueryTime quotes a date or RFC 3339 time for a Drive query
+func driveQueryTime(value string) (string, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t, err = time.Parse("2006-01-02", value)
+		if err != nil {
+			return "", fmt.Errorf("'%s' is not a date (YYYY-MM-DD) or RFC 3339 time", value)
+		}
+	}
+	return driveQueryString(t.UTC().Format(time.RFC3339)), nil
+}
+
+// documentSnippet counts the occurrences of the search text in a document and
+// returns the text around the first one. When the phrase itself does not occur
+// its words are counted instead. Without search text the start of the document
+// is returned.
+func documentSnippet(text, search string) (int, string) {
+	const snippetChars = 80
+
+	if strings.TrimSpace(search) == "" {
+		return 0, matchContext(text, 0, 0, snippetChars*2)
+	}
+
+	matches := 0
+	first := []int(nil)
+	for _, terms := range [][]string{{search}, strings.Fields(search)} {
+		for _, term := range terms {
+			re, err := searchPattern(strings.Trim(term, `"`), false, false)
+			if err != nil {
+				continue
+			}
+			locations := re.FindAllStringIndex(text, -1)
+			matches += len(locations)
+			if len(locations) > 0 && (first == nil || locations[0][0] < first[0]) {
+				first = locations[0]
+			}
+		}
+		if matches > 0 {
+			break
+		}
+	}
+
+	if first == nil {
+		return 0, ""
+	}
+	return matches, matchContext(text, first[0], first[1], snippetChars)
+}
+
+// titleMatches reports whether a title contains the search text, ignoring case
+func titleMatches(title, search string) bool {
+	return strings.Contains(strings.ToLower(title), strings.ToLower(search))
+}
+
+// searchSegments returns the parts of the document to search within the scope,
+// with headers, footers and footnotes ordered by ID
+func searchSegments(doc *docs.Document, scope string) []searchSegment {
+	var segments []searchSegment
+
+	if (scope == "all" || scope == "body") && doc.Body != nil {
+		segments = append(segments, searchSegment{kind: "body", elements: doc.Body.Content})
+	}
+
+	if scope == "all" || scope == "headers" {
+		for _, id := range util.SortedSegmentIDs(doc.Headers) {
+			segments = append(segments, searchSegment{kind: "header", id: id, elements: doc.Headers[id].Content})
+		}
+	}
+
+	if scope == "all" || scope == "footers" {
+		for _, id := range util.SortedSegmentIDs(doc.Footers) {
+			segments = append(segments, searchSegment{kind: "footer", id: id, elements: doc.Footers[id].Content})
+		}
+	}
+
+	if scope == "all" || scope == "footnotes" {
+		for _, id := range util.SortedSegmentIDs(doc.Footnotes) {
+			segments = append(segments, searchSegment{kind: "footnote", id: id, elements: doc.Footnotes[id].Content})
+		}
+	}
+
+	return segments
+}
+
+// isWholeWord reports whether the text between start and end is not directly
+// preceded or followed by a letter, digit or underscore
+func isWholeWord(text string, start, end int) bool {
+	if start > 0 {
+		if r, _ := utf8.DecodeLastRuneInString(text[:start]); isWordRune(r) {
+			return false
+		}
+	}
+	if end < len(text) {
+		if r, _ := utf8.DecodeRuneInString(text[end:]); isWordRune(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// matchContext returns the match with up to chars characters on each side,
+// on one line
+func matchContext(text string, start, end, chars int) string {
+	// Walk outward from the match rune by rune rather than converting the
+	// whole text, which is searched for many matches
+	from := start
+	for i := 0; i < chars && from > 0; i++ {
+		_, size := utf8.DecodeLastRuneInString(text[:from])
+		from -= size
+	}
+	to := end
+	for i := 0; i < chars && to < len(text); i++ {
+		_, size := utf8.DecodeRuneInString(text[to:])
+		to += size
+	}
+
+	prefix, suffix := "", ""
+	if from > 0 {
+		prefix = "..."
+	}
+	if to < len(text) {
+		suffix = "..."
+	}
+
+	snippet := prefix + text[from:to] + suffix
+	return strings.Join(strings.Fields(snippet), " ")
+}
+
+// inTable reports whether an index of the body falls inside a table
+func inTable(elements []*docs.StructuralElement, index int64) bool {
+	for _, element := range elements {
+		if element.Table != nil && element.StartIndex <= index && index < element.EndIndex {
+			return true
+		}
+	}
+	return false
+}
@@ -3,6 +3,7 @@ package tools
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/hdbrzgr/docs-mcp/services"
 	"github.com/hdbrzgr/docs-mcp/util"
@@ -13,27 +14,37 @@ import (
 
 // Input types for structure tools
 type InsertTableInput struct {
-	DocumentID string `json:"document_id" validate:"required"`
-	Index      int64  `json:"index" validate:"required"`
-	Rows       int64  `json:"rows" validate:"required"`
-	Columns    int64  `json:"columns" validate:"required"`
+	DocumentID       string  `json:"document_id" validate:"required"`
+	Index            int64   `json:"index" validate:"required"`
+	Rows             int64   `json:"rows,omitempty"`    // Defaults to the number of data rows
+	Columns          int64   `json:"columns,omitempty"` // Defaults to the widest data row
+	Data             [][]any `json:"data,omitempty"`
+	HeaderRow        bool    `json:"header_row,omitempty"`
+	RevisionID       string  `json:"revision_id,omitempty"`
+	TargetRevisionID string  `json:"target_revision_id,omitempty"`
 }
 
 type InsertListInput struct {
-	DocumentID string   `json:"document_id" validate:"required"`
-	Index      int64    `json:"index" validate:"required"`
-	Items      []string `json:"items" validate:"required"`
-	Ordered    bool     `json:"ordered,omitempty"` // true for numbered list, false for bullet list
+	DocumentID       string   `json:"document_id" validate:"required"`
+	Index            int64    `json:"index" validate:"required"`
+	Items            []string `json:"items" validate:"required"`
+	Ordered          bool     `json:"ordered,omitempty"` // true for numbered list, false for bullet list
+	RevisionID       string   `json:"revision_id,omitempty"`
+	TargetRevisionID string   `json:"target_revision_id,omitempty"`
 }
 
 type InsertPageBreakInput struct {
-	DocumentID string `json:"document_id" validate:"required"`
-	Index      int64  `json:"index" validate:"required"`
+	DocumentID       string `json:"document_id" validate:"required"`
+	Index            int64  `json:"index" validate:"required"`
+	RevisionID       string `json:"revision_id,omitempty"`
+	TargetRevisionID string `json:"target_revision_id,omitempty"`
 }
 
 type InsertHorizontalRuleInput struct {
-	DocumentID string `json:"document_id" validate:"required"`
-	Index      int64  `json:"index" validate:"required"`
+	DocumentID       string `json:"document_id" validate:"required"`
+	Index            int64  `json:"index" validate:"required"`
+	RevisionID       string `json:"revision_id,omitempty"`
+	TargetRevisionID string `json:"target_revision_id,omitempty"`
 }
 
 type CreateTableOfContentsInput struct {
@@ -42,29 +53,39 @@ type CreateTableOfContentsInput struct {
 }
 
 type UpdateTableCellInput struct {
-	DocumentID  string `json:"document_id" validate:"required"`
-	TableIndex  int64  `json:"table_index" validate:"required"`
-	RowIndex    int64  `json:"row_index" validate:"required"`
-	ColumnIndex int64  `json:"column_index" validate:"required"`
-	Text        string `json:"text" validate:"required"`
+	DocumentID       string `json:"document_id" validate:"required"`
+	TableIndex       int64  `json:"table_index" validate:"required"`
+	RowIndex         int64  `json:"row_index" validate:"required"`
+	ColumnIndex      int64  `json:"column_index" validate:"required"`
+	Text             string `json:"text" validate:"required"`
+	RevisionID       string `json:"revision_id,omitempty"`
+	TargetRevisionID string `json:"target_revision_id,omitempty"`
 }
 
 type InsertImageInput struct {
-	DocumentID string `json:"document_id" validate:"required"`
-	Index      int64  `json:"index" validate:"required"`
-	ImageURL   string `json:"image_url" validate:"required"`
-	Width      int64  `json:"width,omitempty"`  // Width in points
-	Height     int64  `json:"height,omitempty"` // Height in points
+	DocumentID       string `json:"document_id" validate:"required"`
+	Index            int64  `json:"index" validate:"required"`
+	ImageURL         string `json:"image_url" validate:"required"`
+	Width            int64  `json:"width,omitempty"`  // Width in points
+	Height           int64  `json:"height,omitempty"` // Height in points
+	RevisionID       string `json:"revision_id,omitempty"`
+	TargetRevisionID string `json:"target_revision_id,omitempty"`
 }
 
 func RegisterStructureTools(s *server.MCPServer) {
 	// Insert table tool
 	insertTableTool := mcp.NewTool("insert_table",
-		mcp.WithDescription("Insert a table with specified rows and columns at a specific position in a Google Docs document"),
+		mcp.WithDescription("Insert a table at a specific position in a Google Docs document, optionally filled with data in the same update"),
 		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
 		mcp.WithNumber("index", mcp.Required(), mcp.Description("Position to insert the table")),
-		mcp.WithNumber("rows", mcp.Required(), mcp.Description("Number of rows in the table")),
-		mcp.WithNumber("columns", mcp.Required(), mcp.Description("Number of columns in the table")),
+		mcp.WithNumber("rows", mcp.Description(fmt.Sprintf("Number of rows in the table (default: the number of data rows, max: %d)", util.MaxTableRows))),
+		mcp.WithNumber("columns", mcp.Description(fmt.Sprintf("Number of columns in the table (default: the widest data row, max: %d)", util.MaxTableColumns))),
+		mcp.WithArray("data", mcp.Description("Cell contents as an array of rows, each an array of cell values; missing cells are left empty"),
+			mcp.Items(map[string]any{"type": "array"}),
+		),
+		mcp.WithBoolean("header_row", mcp.Description("Make the first row a bold header row that repeats on every page (default: false)")),
+		withWriteControl(),
+		mcp.WithOutputSchema[EditOutput](),
 	)
 	s.AddTool(insertTableTool, mcp.NewTypedToolHandler(insertTableHandler))
 
@@ -75,6 +96,8 @@ func RegisterStructureTools(s *server.MCPServer) {
 		mcp.WithNumber("index", mcp.Required(), mcp.Description("Position to insert the list")),
 		mcp.WithArray("items", mcp.Required(), mcp.Description("Array of text items for the list")),
 		mcp.WithBoolean("ordered", mcp.Description("Whether to create a numbered list (true) or bullet list (false, default)")),
+		withWriteControl(),
+		mcp.WithOutputSchema[EditOutput](),
 	)
 	s.AddTool(insertListTool, mcp.NewTypedToolHandler(insertListHandler))
 
@@ -83,6 +106,8 @@ func RegisterStructureTools(s *server.MCPServer) {
 		mcp.WithDescription("Insert a page break at a specific position in a Google Docs document"),
 		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
 		mcp.WithNumber("index", mcp.Required(), mcp.Description("Position to insert the page break")),
+		withWriteControl(),
+		mcp.WithOutputSchema[EditOutput](),
 	)
 	s.AddTool(insertPageBreakTool, mcp.NewTypedToolHandler(insertPageBreakHandler))
 
@@ -91,6 +116,8 @@ func RegisterStructureTools(s *server.MCPServer) {
 		mcp.WithDescription("Insert a horizontal rule (line) at a specific position in a Google Docs document"),
 		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
 		mcp.WithNumber("index", mcp.Required(), mcp.Description("Position to insert the horizontal rule")),
+		withWriteControl(),
+		mcp.WithOutputSchema[EditOutput](),
 	)
 	s.AddTool(insertHorizontalRuleTool, mcp.NewTypedToolHandler(insertHorizontalRuleHandler))
 
@@ -110,6 +137,8 @@ func RegisterStructureTools(s *server.MCPServer) {
 		mcp.WithNumber("row_index", mcp.Required(), mcp.Description("Row index in the table (0-based)")),
 		mcp.WithNumber("column_index", mcp.Required(), mcp.Description("Column index in the table (0-based)")),
 		mcp.WithString("text", mcp.Required(), mcp.Description("Text content to insert in the cell")),
+		withWriteControl(),
+		mcp.WithOutputSchema[EditOutput](),
 	)
 	s.AddTool(updateTableCellTool, mcp.NewTypedToolHandler(updateTableCellHandler))
 
@@ -121,6 +150,8 @@ func RegisterStructureTools(s *server.MCPServer) {
 		mcp.WithString("image_url", mcp.Required(), mcp.Description("URL of the image to insert")),
 		mcp.WithNumber("width", mcp.Description("Image width in points (optional)")),
 		mcp.WithNumber("height", mcp.Description("Image height in points (optional)")),
+		withWriteControl(),
+		mcp.WithOutputSchema[EditOutput](),
 	)
 	s.AddTool(insertImageTool, mcp.NewTypedToolHandler(insertImageHandler))
 }
@@ -128,94 +159,98 @@ func RegisterStructureTools(s *server.MCPServer) {
 func insertTableHandler(ctx context.Context, request mcp.CallToolRequest, input InsertTableInput) (*mcp.CallToolResult, error) {
 	docsService := services.GoogleDocsClient()
 
-	if input.Rows <= 0 || input.Columns <= 0 {
-		return mcp.NewToolResultText("Error: Rows and columns must be greater than 0."), nil
+	writeControl, errResult := newWriteControl(input.RevisionID, input.TargetRevisionID)
+	if errResult != nil {
+		return errResult, nil
 	}
 
-	if input.Rows > 20 || input.Columns > 20 {
-		return mcp.NewToolResultText("Error: Maximum 20 rows and 20 columns allowed."), nil
+	data := tableData(input.Data)
+	dataColumns := int64(0)
+	for _, row := range data {
+		dataColumns = max(dataColumns, int64(len(row)))
 	}
 
-	requests := []*docs.Request{
-		{
-			InsertTable: &docs.InsertTableRequest{
-				Location: &docs.Location{
-					Index: input.Index,
-				},
-				Rows:    input.Rows,
-				Columns: input.Columns,
-			},
-		},
+	rows := input.Rows
+	if rows == 0 {
+		rows = int64(len(data))
+	}
+	columns := input.Columns
+	if columns == 0 {
+		columns = dataColumns
+	}
+
+	if rows <= 0 || columns <= 0 {
+		return mcp.NewToolResultError("Error: Rows and columns must be greater than 0, or data must be given."), nil
 	}
 
+	if rows > util.MaxTableRows || columns > util.MaxTableColumns {
+		return mcp.NewToolResultError(fmt.Sprintf("Error: Maximum %d rows and %d columns allowed.", util.MaxTableRows, util.MaxTableColumns)), nil
+	}
+
+	if int64(len(data)) > rows || dataColumns > columns {
+		return mcp.NewToolResultError(fmt.Sprintf("Error: The data has %d rows and %d columns, more than the %dx%d table.", len(data), dataColumns, rows, columns)), nil
+	}
+
+	requests, length := util.TableDataRequests(input.Index, data, rows, columns, input.HeaderRow)
+
 	batchUpdateRequest := &docs.BatchUpdateDocumentRequest{
-		Requests: requests,
+		Requests:     requests,
+		WriteControl: writeControl,
 	}
 
-	_, err := docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
+	response, err := docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
 	if err != nil {
-		return util.HandleGoogleAPIError("insert table", err), nil
+		return handleWriteError(ctx, "insert table", input.DocumentID, writeControl, err), nil
 	}
 
 	result := fmt.Sprintf("Table inserted successfully!\n\nDocument ID: %s\nPosition: %d\nSize: %dx%d (rows x columns)",
-		input.DocumentID, input.Index, input.Rows, input.Columns)
+		input.DocumentID, input.Index, rows, columns)
+	if len(data) > 0 {
+		result += fmt.Sprintf("\nData Rows: %d", len(data))
+	}
+	if input.HeaderRow {
+		result += "\nHeader Row: yes"
+	}
+
+	output := EditOutput{
+		DocumentID: input.DocumentID,
+		RevisionID: revisionAfter(response),
+		StartIndex: input.Index,
+		EndIndex:   input.Index + length,
+		Length:     length,
+	}
 
-	return mcp.NewToolResultText(result), nil
+	return mcp.NewToolResultStructured(output, result), nil
 }
 
 func insertListHandler(ctx context.Context, request mcp.CallToolRequest, input InsertListInput) (*mcp.CallToolResult, error) {
 	docsService := services.GoogleDocsClient()
 
-	if len(input.Items) == 0 {
-		return mcp.NewToolResultText("Error: List must contain at least one item."), nil
+	writeControl, errResult := newWriteControl(input.RevisionID, input.TargetRevisionID)
+	if errResult != nil {
+		return errResult, nil
 	}
 
-	var requests []*docs.Request
-	currentIndex := input.Index
-
-	// Insert each list item
-	for i, item := range input.Items {
-		// Insert the text
-		requests = append(requests, &docs.Request{
-			InsertText: &docs.InsertTextRequest{
-				Location: &docs.Location{
-					Index: currentIndex,
-				},
-				Text: item + "\n",
-			},
-		})
-
-		// Apply list formatting to the paragraph
-		listType := "BULLET_DISC_CIRCLE_SQUARE"
-		if input.Ordered {
-			listType = "DECIMAL_ALPHA_ROMAN"
-		}
-
-		requests = append(requests, &docs.Request{
-			CreateParagraphBullets: &docs.CreateParagraphBulletsRequest{
-				Range: &docs.Range{
-					StartIndex: currentIndex,
-					EndIndex:   currentIndex + int64(len(item)) + 1,
-				},
-				BulletPreset: listType,
-			},
-		})
-
-		currentIndex += int64(len(item)) + 1 // +1 for the newline
+	if len(input.Items) == 0 {
+		return mcp.NewToolResultError("Error: List must contain at least one item."), nil
+	}
 
-		// Add some spacing between requests to avoid conflicts
-		if i < len(input.Items)-1 {
-			currentIndex += 1
-		}
+	// Insert the items as one paragraph each and make them a single list
+	text := strings.Join(input.Items, "\n") + "\n"
+	endIndex := input.Index + util.UTF16Length(text)
+	requests := []*docs.Request{
+		insertTextRequest(input.Index, text),
+		bulletsRequest(input.Index, endIndex, input.Ordered),
 	}
 
 	batchUpdateRequest := &docs.BatchUpdateDocumentRequest{
-		Requests: requests,
+		Requests:     requests,
+		WriteControl: writeControl,
 	}
 
-	_, err := docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
+	response, err := docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
 	if err != nil {
-		return util.HandleGoogleAPIError("insert list", err), nil
+		return handleWriteError(ctx, "insert list", input.DocumentID, writeControl, err), nil
 	}
 
 	listType := "bullet"
@@ -226,12 +261,25 @@ func insertListHandler(ctx context.Context, request mcp.CallToolRequest, input I
 	result := fmt.Sprintf("List inserted successfully!\n\nDocument ID: %s\nPosition: %d\nType: %s list\nItems: %d",
 		input.DocumentID, input.Index, listType, len(input.Items))
 
-	return mcp.NewToolResultText(result), nil
+	output := EditOutput{
+		DocumentID: input.DocumentID,
+		RevisionID: revisionAfter(response),
+		StartIndex: input.Index,
+		EndIndex:   endIndex,
+		Length:     endIndex - input.Index,
+	}
+
+	return mcp.NewToolResultStructured(output, result), nil
 }
 
 func insertPageBreakHandler(ctx context.Context, request mcp.CallToolRequest, input InsertPageBreakInput) (*mcp.CallToolResult, error) {
 	docsService := services.GoogleDocsClient()
 
+	writeControl, errResult := newWriteControl(input.RevisionID, input.TargetRevisionID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
 	requests := []*docs.Request{
 		{
 			InsertPageBreak: &docs.InsertPageBreakRequest{
@@ -243,23 +291,38 @@ func insertPageBreakHandler(ctx context.Context, request mcp.CallToolRequest, in
 	}
 
 	batchUpdateRequest := &docs.BatchUpdateDocumentRequest{
-		Requests: requests,
+		Requests:     requests,
+		WriteControl: writeControl,
 	}
 
-	_, err := docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
+	response, err := docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
 	if err != nil {
-		return util.HandleGoogleAPIError("insert page break", err), nil
+		return handleWriteError(ctx, "insert page break", input.DocumentID, writeControl, err), nil
 	}
 
 	result := fmt.Sprintf("Page break inserted successfully!\n\nDocument ID: %s\nPosition: %d",
 		input.DocumentID, input.Index)
 
-	return mcp.NewToolResultText(result), nil
+	// A page break is inserted together with a newline
+	output := EditOutput{
+		DocumentID: input.DocumentID,
+		RevisionID: revisionAfter(response),
+		StartIndex: input.Index,
+		EndIndex:   input.Index + 2,
+		Length:     2,
+	}
+
+	return mcp.NewToolResultStructured(output, result), nil
 }
 
 func insertHorizontalRuleHandler(ctx context.Context, request mcp.CallToolRequest, input InsertHorizontalRuleInput) (*mcp.CallToolResult, error) {
 	docsService := services.GoogleDocsClient()
 
+	writeControl, errResult := newWriteControl(input.RevisionID, input.TargetRevisionID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
 	// Insert a horizontal rule by inserting text and formatting it
 	requests := []*docs.Request{
 		{
@@ -285,18 +348,27 @@ func insertHorizontalRuleHandler(ctx context.Context, request mcp.CallToolReques
 	}
 
 	batchUpdateRequest := &docs.BatchUpdateDocumentRequest{
-		Requests: requests,
+		Requests:     requests,
+		WriteControl: writeControl,
 	}
 
-	_, err := docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
+	response, err := docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
 	if err != nil {
-		return util.HandleGoogleAPIError("insert horizontal rule", err), nil
+		return handleWriteError(ctx, "insert horizontal rule", input.DocumentID, writeControl, err), nil
 	}
 
 	result := fmt.Sprintf("Horizontal rule inserted successfully!\n\nDocument ID: %s\nPosition: %d",
 		input.DocumentID, input.Index)
 
-	return mcp.NewToolResultText(result), nil
+	output := EditOutput{
+		DocumentID: input.DocumentID,
+		RevisionID: revisionAfter(response),
+		StartIndex: input.Index,
+		EndIndex:   input.Index + 4,
+		Length:     4,
+	}
+
+	return mcp.NewToolResultStructured(output, result), nil
 }
 
 func createTableOfContentsHandler(ctx context.Context, request mcp.CallToolRequest, input CreateTableOfContentsInput) (*mcp.CallToolResult, error) {
@@ -310,87 +382,83 @@ func createTableOfContentsHandler(ctx context.Context, request mcp.CallToolReque
 	result := fmt.Sprintf("❌ Table of Contents Creation Not Supported\n\nThe Google Docs API does not currently support programmatically inserting a table of contents.\n\nTo add a table of contents to your document:\n1. Open the document in Google Docs: https://docs.google.com/document/d/%s/edit\n2. Click at position %d (or where you want the table of contents)\n3. Go to Insert → Table of contents\n4. Choose your preferred style\n\nThe table of contents will automatically update based on headings in your document.",
 		input.DocumentID, input.Index)
 
-	return mcp.NewToolResultText(result), nil
+	return mcp.NewToolResultError(result), nil
 }
 
 func updateTableCellHandler(ctx context.Context, request mcp.CallToolRequest, input UpdateTableCellInput) (*mcp.CallToolResult, error) {
 	docsService := services.GoogleDocsClient()
 
+	writeControl, errResult := newWriteControl(input.RevisionID, input.TargetRevisionID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
 	// Get the document to find the table
 	doc, err := docsService.Documents.Get(input.DocumentID).Context(ctx).Do()
 	if err != nil {
 		return util.HandleGoogleAPIError("get document for table update", err), nil
 	}
 
-	// Find the table and cell
-	var targetTable *docs.Table
-	tableCount := int64(0)
-
-	if doc.Body != nil {
-		for _, element := range doc.Body.Content {
-			if element.Table != nil {
-				if tableCount == input.TableIndex {
-					targetTable = element.Table
-					break
-				}
-				tableCount++
-			}
-		}
+	if errResult := pinWriteControl(writeControl, doc); errResult != nil {
+		return errResult, nil
 	}
 
-	if targetTable == nil {
-		return mcp.NewToolResultText(fmt.Sprintf("Error: Table with index %d not found in document.", input.TableIndex)), nil
+	// Find the table and cell
+	tableElement := findTableElement(doc, input.TableIndex)
+	if tableElement == nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error: Table with index %d not found in document.", input.TableIndex)), nil
 	}
+	targetTable := tableElement.Table
 
 	if input.RowIndex >= int64(len(targetTable.TableRows)) {
-		return mcp.NewToolResultText(fmt.Sprintf("Error: Row index %d is out of range. Table has %d rows.", input.RowIndex, len(targetTable.TableRows))), nil
+		return mcp.NewToolResultError(fmt.Sprintf("Error: Row index %d is out of range. Table has %d rows.", input.RowIndex, len(targetTable.TableRows))), nil
 	}
 
 	row := targetTable.TableRows[input.RowIndex]
 	if input.ColumnIndex >= int64(len(row.TableCells)) {
-		return mcp.NewToolResultText(fmt.Sprintf("Error: Column index %d is out of range. Row has %d columns.", input.ColumnIndex, len(row.TableCells))), nil
+		return mcp.NewToolResultError(fmt.Sprintf("Error: Column index %d is out of range. Row has %d columns.", input.ColumnIndex, len(row.TableCells))), nil
 	}
 
 	cell := row.TableCells[input.ColumnIndex]
+	cellStart, _ := util.TableCellContentRange(cell)
 
 	// Clear existing content and insert new text
-	requests := []*docs.Request{
-		{
-			DeleteContentRange: &docs.DeleteContentRangeRequest{
-				Range: &docs.Range{
-					StartIndex: cell.StartIndex,
-					EndIndex:   cell.EndIndex - 1, // -1 to preserve the cell structure
-				},
-			},
-		},
-		{
-			InsertText: &docs.InsertTextRequest{
-				Location: &docs.Location{
-					Index: cell.StartIndex,
-				},
-				Text: input.Text,
-			},
-		},
-	}
-
-	batchUpdateRequest := &docs.BatchUpdateDocumentRequest{
-		Requests: requests,
-	}
+	revisionID := doc.RevisionId
+	if requests := util.TableCellTextRequests(cell, input.Text); len(requests) > 0 {
+		batchUpdateRequest := &docs.BatchUpdateDocumentRequest{
+			Requests:     requests,
+			WriteControl: writeControl,
+		}
 
-	_, err = docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
-	if err != nil {
-		return util.HandleGoogleAPIError("update table cell", err), nil
+		response, err := docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
+		if err != nil {
+			return handleWriteError(ctx, "update table cell", input.DocumentID, writeControl, err), nil
+		}
+		revisionID = revisionAfter(response)
 	}
 
 	result := fmt.Sprintf("Table cell updated successfully!\n\nDocument ID: %s\nTable: %d\nCell: Row %d, Column %d\nContent: %s",
 		input.DocumentID, input.TableIndex, input.RowIndex, input.ColumnIndex, input.Text)
 
-	return mcp.NewToolResultText(result), nil
+	output := EditOutput{
+		DocumentID: input.DocumentID,
+		RevisionID: revisionID,
+		StartIndex: cellStart,
+		EndIndex:   cellStart + util.UTF16Length(input.Text),
+		Length:     util.UTF16Length(input.Text),
+	}
+
+	return mcp.NewToolResultStructured(output, result), nil
 }
 
 func insertImageHandler(ctx context.Context, request mcp.CallToolRequest, input InsertImageInput) (*mcp.CallToolResult, error) {
 	docsService := services.GoogleDocsClient()
 
+	writeControl, errResult := newWriteControl(input.RevisionID, input.TargetRevisionID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
 	// Create inline object properties for the image
 	inlineObjectProperties := &docs.InlineObjectProperties{
 		EmbeddedObject: &docs.EmbeddedObject{
@@ -430,12 +498,13 @@ func insertImageHandler(ctx context.Context, request mcp.CallToolRequest, input
 	}
 
 	batchUpdateRequest := &docs.BatchUpdateDocumentRequest{
-		Requests: requests,
+		Requests:     requests,
+		WriteControl: writeControl,
 	}
 
-	_, err := docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
+	response, err := docsService.Documents.BatchUpdate(input.DocumentID, batchUpdateRequest).Context(ctx).Do()
 	if err != nil {
-		return util.HandleGoogleAPIError("insert image", err), nil
+		return handleWriteError(ctx, "insert image", input.DocumentID, writeControl, err), nil
 	}
 
 	result := fmt.Sprintf("Image inserted successfully!\n\nDocument ID: %s\nPosition: %d\nImage URL: %s",
@@ -445,5 +514,32 @@ func insertImageHandler(ctx context.Context, request mcp.CallToolRequest, input
 		result += fmt.Sprintf("\nDimensions: %dx%d points", input.Width, input.Height)
 	}
 
-	return mcp.NewToolResultText(result), nil
+	output := EditOutput{
+		DocumentID: input.DocumentID,
+		RevisionID: revisionAfter(response),
+		StartIndex: input.Index,
+		EndIndex:   input.Index + 1,
+		Length:     1,
+	}
+
+	return mcp.NewToolResultStructured(output, result), nil
+}
+
+// findTableElement returns the structural element of the table at the 0-based
+// position among the tables of the document body, or nil
+func findTableElement(doc *docs.Document, tableIndex int64) *docs.StructuralElement {
+	if doc.Body == nil {
+		return nil
+	}
+
+	tableCount := int64(0)
+	for _, element := range doc.Body.Content {
+		if element.Table != nil {
+			if tableCount == tableIndex {
+				return element
+			}
+			tableCount++
+		}
+	}
+	return nil
 }
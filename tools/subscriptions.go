@@ -0,0 +1,144 @@
+package tools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Subscription methods, which the MCP server advertises but does not route
+const (
+	methodResourcesSubscribe   = "resources/subscribe"
+	methodResourcesUnsubscribe = "resources/unsubscribe"
+)
+
+// sessionIDHeader is the header carrying the session ID of streamable HTTP clients
+const sessionIDHeader = "Mcp-Session-Id"
+
+// HandleSubscriptionRequest answers a resources/subscribe or
+// resources/unsubscribe request. It returns false for any other message, which
+// should be passed on to the MCP server.
+func (w *ResourceWatcher) HandleSubscriptionRequest(ctx context.Context, sessionID string, message []byte) (mcp.JSONRPCMessage, bool) {
+	var request struct {
+		ID     mcp.RequestId `json:"id"`
+		Method string        `json:"method"`
+		Params struct {
+			URI string `json:"uri"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(message, &request); err != nil || request.ID.IsNil() {
+		return nil, false
+	}
+
+	var err error
+	switch request.Method {
+	case methodResourcesSubscribe:
+		err = w.Subscribe(ctx, sessionID, request.Params.URI)
+	case methodResourcesUnsubscribe:
+		err = w.Unsubscribe(sessionID, request.Params.URI)
+	default:
+		return nil, false
+	}
+
+	if err != nil {
+		return mcp.NewJSONRPCError(request.ID, mcp.INVALID_PARAMS, err.Error(), nil), true
+	}
+	return mcp.NewJSONRPCResponse(request.ID, mcp.Result{}), true
+}
+
+// ServeStdio serves the MCP server over standard input and output like
+// server.ServeStdio, answering subscription requests and watching subscribed
+// documents until the input is closed or the process is interrupted
+func (w *ResourceWatcher) ServeStdio(s *server.MCPServer) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer cancel()
+
+	go w.Run(ctx)
+
+	// Responses to subscription requests share stdout with the server
+	stdout := &lockedWriter{writer: os.Stdout}
+
+	input, pipe := io.Pipe()
+	go func() {
+		reader := bufio.NewReader(os.Stdin)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(line) > 0 {
+				if response, ok := w.HandleSubscriptionRequest(ctx, "", line); ok {
+					writeJSONLine(stdout, response)
+				} else if _, writeErr := pipe.Write(line); writeErr != nil {
+					return
+				}
+			}
+			if err != nil {
+				pipe.CloseWithError(err)
+				return
+			}
+		}
+	}()
+
+	return server.NewStdioServer(s).Listen(ctx, input, stdout)
+}
+
+// HTTPHandler wraps a streamable HTTP server, answering subscription requests
+// and dropping the subscriptions of terminated sessions
+func (w *ResourceWatcher) HTTPHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		sessionID := r.Header.Get(sessionIDHeader)
+
+		switch r.Method {
+		case http.MethodDelete:
+			w.UnsubscribeSession(sessionID)
+		case http.MethodPost:
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(rw, "Failed to read request body", http.StatusBadRequest)
+				return
+			}
+
+			if response, ok := w.HandleSubscriptionRequest(r.Context(), sessionID, body); ok {
+				if sessionID != "" {
+					rw.Header().Set(sessionIDHeader, sessionID)
+				}
+				rw.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(rw).Encode(response)
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// lockedWriter serializes writes from several goroutines
+type lockedWriter struct {
+	mu     sync.Mutex
+	writer io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.writer.Write(p)
+}
+
+// writeJSONLine writes a JSON-RPC message as a single line
+func writeJSONLine(writer io.Writer, message mcp.JSONRPCMessage) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+	writer.Write(append(data, '\n'))
+}
@@ -0,0 +1,1129 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hdbrzgr/docs-mcp/services"
+	"github.com/hdbrzgr/docs-mcp/util"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"google.golang.org/api/docs/v1"
+)
+
+// Input types for table tools
+type ReadTableInput struct {
+	DocumentID string   `json:"document_id" validate:"required"`
+	TableIndex int64    `json:"table_index,omitempty"` // Among the tables that match heading and header
+	Heading    string   `json:"heading,omitempty"`
+	Header     []string `json:"header,omitempty"`
+	Format     string   `json:"format,omitempty"` // json, csv, markdown
+	HeaderRow  *bool    `json:"header_row,omitempty"`
+	FillMerged bool     `json:"fill_merged,omitempty"`
+}
+
+type InsertTableRowInput struct {
+	DocumentID       string `json:"document_id" validate:"required"`
+	TableIndex       int64  `json:"table_index"`
+	RowIndex         int64  `json:"row_index"`
+	Count            int64  `json:"count,omitempty"`
+	Above            bool   `json:"above,omitempty"` // Insert above the row instead of below
+	RevisionID       string `json:"revision_id,omitempty"`
+	TargetRevisionID string `json:"target_revision_id,omitempty"`
+}
+
+type DeleteTableRowInput struct {
+	DocumentID       string `json:"document_id" validate:"required"`
+	TableIndex       int64  `json:"table_index"`
+	RowIndex         int64  `json:"row_index"`
+	Count            int64  `json:"count,omitempty"`
+	RevisionID       string `json:"revision_id,omitempty"`
+	TargetRevisionID string `json:"target_revision_id,omitempty"`
+}
+
+type InsertTableColumnInput struct {
+	DocumentID       string `json:"document_id" validate:"required"`
+	TableIndex       int64  `json:"table_index"`
+	ColumnIndex      int64  `json:"column_index"`
+	Count            int64  `json:"count,omitempty"`
+	Left             bool   `json:"left,omitempty"` // Insert left of the column instead of right
+	RevisionID       string `json:"revision_id,omitempty"`
+	TargetRevisionID string `json:"target_revision_id,omitempty"`
+}
+
+type DeleteTableColumnInput struct {
+	DocumentID       string `json:"document_id" validate:"required"`
+	TableIndex       int64  `json:"table_index"`
+	ColumnIndex      int64  `json:"column_index"`
+	Count            int64  `json:"count,omitempty"`
+	RevisionID       string `json:"revision_id,omitempty"`
+	TargetRevisionID string `json:"target_revision_id,omitempty"`
+}
+
+type MergeTableCellsInput struct {
+	DocumentID       string `json:"document_id" validate:"required"`
+	TableIndex       int64  `json:"table_index"`
+	RowIndex         int64  `json:"row_index"`
+	ColumnIndex      int64  `json:"column_index"`
+	RowSpan          int64  `json:"row_span,omitempty"`
+	ColumnSpan       int64  `json:"column_span,omitempty"`
+	RevisionID       string `json:"revision_id,omitempty"`
+	TargetRevisionID string `json:"target_revision_id,omitempty"`
+}
+
+type UnmergeTableCellsInput struct {
+	DocumentID       string `json:"document_id" validate:"required"`
+	TableIndex       int64  `json:"table_index"`
+	RowIndex         *int64 `json:"row_index,omitempty"`    // All rows when not given
+	ColumnIndex      *int64 `json:"column_index,omitempty"` // All columns when not given
+	RowSpan          int64  `json:"row_span,omitempty"`
+	ColumnSpan       int64  `json:"column_span,omitempty"`
+	RevisionID       string `json:"revision_id,omitempty"`
+	TargetRevisionID string `json:"target_revision_id,omitempty"`
+}
+
+type SetTableColumnWidthInput struct {
+	DocumentID       string  `json:"document_id" validate:"required"`
+	TableIndex       int64   `json:"table_index"`
+	Columns          []int64 `json:"columns,omitempty"` // All columns when empty
+	Width            float64 `json:"width,omitempty"`   // Width in points; 0 distributes the columns evenly
+	RevisionID       string  `json:"revision_id,omitempty"`
+	TargetRevisionID string  `json:"target_revision_id,omitempty"`
+}
+
+type StyleTableCellsInput struct {
+	DocumentID       string   `json:"document_id" validate:"required"`
+	TableIndex       int64    `json:"table_index"`
+	RowIndex         *int64   `json:"row_index,omitempty"`    // All rows when not given
+	ColumnIndex      *int64   `json:"column_index,omitempty"` // All columns when not given
+	RowSpan          int64    `json:"row_span,omitempty"`
+	ColumnSpan       int64    `json:"column_span,omitempty"`
+	BackgroundColor  string   `json:"background_color,omitempty"`
+	BorderColor      string   `json:"border_color,omitempty"`
+	BorderWidth      *float64 `json:"border_width,omitempty"`
+	BorderStyle      string   `json:"border_style,omitempty"` // SOLID, DOT, DASH
+	Borders          string   `json:"borders,omitempty"`      // all, or a comma-separated list of top, bottom, left, right
+	ContentAlignment string   `json:"content_alignment,omitempty"`
+	RevisionID       string   `json:"revision_id,omitempty"`
+	TargetRevisionID string   `json:"target_revision_id,omitempty"`
+}
+
+type UpdateTableCellsInput struct {
+	DocumentID       string            `json:"document_id" validate:"required"`
+	TableIndex       int64             `json:"table_index"`
+	Cells            []TableCellUpdate `json:"cells,omitempty"`
+	Data             [][]any           `json:"data,omitempty"`
+	RowIndex         int64             `json:"row_index,omitempty"`    // First row written by data
+	ColumnIndex      int64             `json:"column_index,omitempty"` // First column written by data
+	RevisionID       string            `json:"revision_id,omitempty"`
+	TargetRevisionID string            `json:"target_revision_id,omitempty"`
+}
+
+type TableCellUpdate struct {
+	Row    int64  `json:"row"`
+	Column int64  `json:"column"`
+	Text   string `json:"text"`
+}
+
+// Output types for table tools
+type ReadTableOutput struct {
+	DocumentID string         `json:"document_id"`
+	RevisionID string         `json:"revision_id"`
+	TableIndex int64          `json:"table_index" jsonschema_description:"Position of the table among the tables of the document body, as taken by the table editing tools"`
+	Format     string         `json:"format"`
+	Tables     []TableContent `json:"tables" jsonschema_description:"The table read, followed by the tables nested in its cells at any depth"`
+	Content    string         `json:"content,omitempty" jsonschema_description:"The tables rendered as CSV or Markdown"`
+}
+
+type TableContent struct {
+	Parent     int                 `json:"parent" jsonschema_description:"Position in the tables list of the table holding this one, or -1 for the table read"`
+	Row        int                 `json:"row" jsonschema_description:"Row of the parent cell holding this table"`
+	Column     int                 `json:"column" jsonschema_description:"Column of the parent cell holding this table"`
+	StartIndex int64               `json:"start_index"`
+	EndIndex   int64               `json:"end_index"`
+	Rows       int                 `json:"rows"`
+	Columns    int                 `json:"columns"`
+	Cells      [][]string          `json:"cells" jsonschema_description:"Text of every cell, row by row; paragraphs of a cell are separated by newlines"`
+	Header     []string            `json:"header,omitempty" jsonschema_description:"Keys of the records, from the first row"`
+	Records    []map[string]string `json:"records,omitempty" jsonschema_description:"Rows after the header row as objects keyed by header"`
+	Merges     []util.TableMerge   `json:"merges" jsonschema_description:"Merged cells with the rows and columns they span"`
+}
+
+type TableOutput struct {
+	DocumentID string `json:"document_id"`
+	RevisionID string `json:"revision_id,omitempty" jsonschema_description:"Revision of the document after the edit. Pass it as revision_id to chain further edits"`
+	TableIndex int64  `json:"table_index"`
+	StartIndex int64  `json:"start_index" jsonschema_description:"Start of the table in the document"`
+	Rows       int64  `json:"rows" jsonschema_description:"Rows of the table after the edit"`
+	Columns    int64  `json:"columns" jsonschema_description:"Columns of the table after the edit"`
+	Cells      int    `json:"cells,omitempty" jsonschema_description:"Cells written by the edit"`
+}
+
+func RegisterTableTools(s *server.MCPServer) {
+	// Read table tool
+	readTableTool := mcp.NewTool("read_table",
+		mcp.WithDescription("Read a table from a Google Docs document as data: JSON rows and records, CSV or Markdown, with its merged cells and nested tables. "+
+			"The table is chosen by position, by the heading of the section it is in, or by the names in its header row"),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
+		mcp.WithNumber("table_index", mcp.Description("Position of the table (0-based) among the tables of the body, or among those matching heading and header when given (default: 0)")),
+		mcp.WithString("heading", mcp.Description("Only consider tables in the section under this heading (text or heading ID)")),
+		mcp.WithArray("header", mcp.Description("Only consider tables whose first row contains all of these cell texts (case-insensitive)"), mcp.WithStringItems()),
+		mcp.WithString("format", mcp.Description("Content format: 'json', 'csv', or 'markdown' (default: 'json')")),
+		mcp.WithBoolean("header_row", mcp.Description("Treat the first row as a header and return the other rows as records keyed by it (default: true)")),
+		mcp.WithBoolean("fill_merged", mcp.Description("Repeat the text of a merged cell in every cell it covers instead of leaving them empty (default: false)")),
+		mcp.WithOutputSchema[ReadTableOutput](),
+	)
+	s.AddTool(readTableTool, mcp.NewTypedToolHandler(readTableHandler))
+
+	// Insert table row tool
+	insertTableRowTool := mcp.NewTool("insert_table_row",
+		mcp.WithDescription("Insert empty rows into a table in a Google Docs document, below or above an existing row"),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
+		mcp.WithNumber("table_index", mcp.Required(), mcp.Description("Index of the table in the document (0-based)")),
+		mcp.WithNumber("row_index", mcp.Required(), mcp.Description("Row to insert next to (0-based)")),
+		mcp.WithNumber("count", mcp.Description("Number of rows to insert (default: 1)")),
+		mcp.WithBoolean("above", mcp.Description("Insert above the row instead of below (default: false)")),
+		withWriteControl(),
+		mcp.WithOutputSchema[TableOutput](),
+	)
+	s.AddTool(insertTableRowTool, mcp.NewTypedToolHandler(insertTableRowHandler))
+
+	// Delete table row tool
+	deleteTableRowTool := mcp.NewTool("delete_table_row",
+		mcp.WithDescription("Delete rows from a table in a Google Docs document"),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
+		mcp.WithNumber("table_index", mcp.Required(), mcp.Description("Index of the table in the document (0-based)")),
+		mcp.WithNumber("row_index", mcp.Required(), mcp.Description("First row to delete (0-based)")),
+		mcp.WithNumber("count", mcp.Description("Number of rows to delete (default: 1)")),
+		withWriteControl(),
+		mcp.WithOutputSchema[TableOutput](),
+	)
+	s.AddTool(deleteTableRowTool, mcp.NewTypedToolHandler(deleteTableRowHandler))
+
+	// Insert table column tool
+	insertTableColumnTool := mcp.NewTool("insert_table_column",
+		mcp.WithDescription("Insert empty columns into a table in a Google Docs document, right or left of an existing column"),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
+		mcp.WithNumber("table_index", mcp.Required(), mcp.Description("Index of the table in the document (0-based)")),
+		mcp.WithNumber("column_index", mcp.Required(), mcp.Description("Column to insert next to (0-based)")),
+		mcp.WithNumber("count", mcp.Description("Number of columns to insert (default: 1)")),
+		mcp.WithBoolean("left", mcp.Description("Insert left of the column instead of right (default: false)")),
+		withWriteControl(),
+		mcp.WithOutputSchema[TableOutput](),
+	)
+	s.AddTool(insertTableColumnTool, mcp.NewTypedToolHandler(insertTableColumnHandler))
+
+	// Delete table column tool
+	deleteTableColumnTool := mcp.NewTool("delete_table_column",
+		mcp.WithDescription("Delete columns from a table in a Google Docs document"),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
+		mcp.WithNumber("table_index", mcp.Required(), mcp.Description("Index of the table in the document (0-based)")),
+		mcp.WithNumber("column_index", mcp.Required(), mcp.Description("First column to delete (0-based)")),
+		mcp.WithNumber("count", mcp.Description("Number of columns to delete (default: 1)")),
+		withWriteControl(),
+		mcp.WithOutputSchema[TableOutput](),
+	)
+	s.AddTool(deleteTableColumnTool, mcp.NewTypedToolHandler(deleteTableColumnHandler))
+
+	// Merge table cells tool
+	mergeTableCellsTool := mcp.NewTool("merge_table_cells",
+		mcp.WithDescription("Merge a rectangle of table cells into one cell. The text of the merged cells is kept in the resulting cell"),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
+		mcp.WithNumber("table_index", mcp.Required(), mcp.Description("Index of the table in the document (0-based)")),
+		mcp.WithNumber("row_index", mcp.Required(), mcp.Description("Top row of the cells to merge (0-based)")),
+		mcp.WithNumber("column_index", mcp.Required(), mcp.Description("Leftmost column of the cells to merge (0-based)")),
+		mcp.WithNumber("row_span", mcp.Description("Number of rows to merge (default: 1)")),
+		mcp.WithNumber("column_span", mcp.Description("Number of columns to merge (default: 1)")),
+		withWriteControl(),
+		mcp.WithOutputSchema[TableOutput](),
+	)
+	s.AddTool(mergeTableCellsTool, mcp.NewTypedToolHandler(mergeTableCellsHandler))
+
+	// Unmerge table cells tool
+	unmergeTableCellsTool := mcp.NewTool("unmerge_table_cells",
+		mcp.WithDescription("Split the merged cells in a rectangle of a table, or in the whole table, back into separate cells"),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
+		mcp.WithNumber("table_index", mcp.Required(), mcp.Description("Index of the table in the document (0-based)")),
+		mcp.WithNumber("row_index", mcp.Description("Top row of the rectangle (0-based, default: every row)")),
+		mcp.WithNumber("column_index", mcp.Description("Leftmost column of the rectangle (0-based, default: every column)")),
+		mcp.WithNumber("row_span", mcp.Description("Number of rows from row_index (default: 1)")),
+		mcp.WithNumber("column_span", mcp.Description("Number of columns from column_index (default: 1)")),
+		withWriteControl(),
+		mcp.WithOutputSchema[TableOutput](),
+	)
+	s.AddTool(unmergeTableCellsTool, mcp.NewTypedToolHandler(unmergeTableCellsHandler))
+
+	// Set table column width tool
+	setTableColumnWidthTool := mcp.NewTool("set_table_column_width",
+		mcp.WithDescription("Set the width of table columns in a Google Docs document, or distribute them evenly"),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
+		mcp.WithNumber("table_index", mcp.Required(), mcp.Description("Index of the table in the document (0-based)")),
+		mcp.WithArray("columns", mcp.Description("Columns to resize (0-based, default: every column)"), mcp.WithNumberItems()),
+		mcp.WithNumber("width", mcp.Description("Width in points, at least 5; leave out to distribute the columns evenly")),
+		withWriteControl(),
+		mcp.WithOutputSchema[TableOutput](),
+	)
+	s.AddTool(setTableColumnWidthTool, mcp.NewTypedToolHandler(setTableColumnWidthHandler))
+
+	// Style table cells tool
+	styleTableCellsTool := mcp.NewTool("style_table_cells",
+		mcp.WithDescription("Set the background color, borders and vertical alignment of a rectangle of table cells, a row, a column or the whole table"),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
+		mcp.WithNumber("table_index", mcp.Required(), mcp.Description("Index of the table in the document (0-based)")),
+		mcp.WithNumber("row_index", mcp.Description("Top row of the cells (0-based, default: every row)")),
+		mcp.WithNumber("column_index", mcp.Description("Leftmost column of the cells (0-based, default: every column)")),
+		mcp.WithNumber("row_span", mcp.Description("Number of rows from row_index (default: 1)")),
+		mcp.WithNumber("column_span", mcp.Description("Number of columns from column_index (default: 1)")),
+		mcp.WithString("background_color", mcp.Description("Cell background color in hex format (e.g., '#D9EAD3')")),
+		mcp.WithString("border_color", mcp.Description("Border color in hex format (default: '#000000' when another border option is given)")),
+		mcp.WithNumber("border_width", mcp.Description("Border width in points; 0 hides the borders (default: 1 when another border option is given)")),
+		mcp.WithString("border_style", mcp.Description("Border dash style: 'SOLID', 'DOT', or 'DASH' (default: 'SOLID')")),
+		mcp.WithString("borders", mcp.Description("Borders to change: 'all' or a comma-separated list of 'top', 'bottom', 'left', 'right' (default: 'all')")),
+		mcp.WithString("content_alignment", mcp.Description("Vertical alignment of the cell content: 'TOP', 'MIDDLE', or 'BOTTOM'")),
+		withWriteControl(),
+		mcp.WithOutputSchema[TableOutput](),
+	)
+	s.AddTool(styleTableCellsTool, mcp.NewTypedToolHandler(styleTableCellsHandler))
+
+	// Update table cells tool
+	updateTableCellsTool := mcp.NewTool("update_table_cells",
+		mcp.WithDescription("Replace the content of many table cells in a single update, given as a list of cells or as a block of rows"),
+		mcp.WithString("document_id", mcp.Required(), mcp.Description("The unique identifier of the document")),
+		mcp.WithNumber("table_index", mcp.Required(), mcp.Description("Index of the table in the document (0-based)")),
+		mcp.WithArray("cells", mcp.Description("Cells to write, each {\"row\": 0, \"column\": 1, \"text\": \"...\"} with 0-based positions"),
+			mcp.Items(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"row":    map[string]any{"type": "number", "description": "Row index (0-based)"},
+					"column": map[string]any{"type": "number", "description": "Column index (0-based)"},
+					"text":   map[string]any{"type": "string", "description": "New content of the cell; empty clears it"},
+				},
+				"required": []string{"row", "column", "text"},
+			}),
+		),
+		mcp.WithArray("data", mcp.Description("Block of cell values as an array of rows, written from row_index and column_index"),
+			mcp.Items(map[string]any{"type": "array"}),
+		),
+		mcp.WithNumber("row_index", mcp.Description("First row written by data (0-based, default: 0)")),
+		mcp.WithNumber("column_index", mcp.Description("First column written by data (0-based, default: 0)")),
+		withWriteControl(),
+		mcp.WithOutputSchema[TableOutput](),
+	)
+	s.AddTool(updateTableCellsTool, mcp.NewTypedToolHandler(updateTableCellsHandler))
+}
+
+func readTableHandler(ctx context.Context, request mcp.CallToolRequest, input ReadTableInput) (*mcp.CallToolResult, error) {
+	docsService := services.GoogleDocsClient()
+
+	format := input.Format
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "csv" && format != "markdown" {
+		return mcp.NewToolResultError("Error: Invalid format. Must be 'json', 'csv', or 'markdown'."), nil
+	}
+	headerRow := input.HeaderRow == nil || *input.HeaderRow
+
+	doc, err := docsService.Documents.Get(input.DocumentID).Context(ctx).Do()
+	if err != nil {
+		return util.HandleGoogleAPIError("get document for reading table", err), nil
+	}
+
+	tableIndex, table, errResult := selectTable(doc, input)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	output := ReadTableOutput{
+		DocumentID: doc.DocumentId,
+		RevisionID: doc.RevisionId,
+		TableIndex: tableIndex,
+		Format:     format,
+	}
+
+	output.Tables, output.Content, err = readTableContents(table, format, headerRow, input.FillMerged)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error: Failed to write %s: %v", format, err)), nil
+	}
+
+	selected := output.Tables[0]
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Table %d of document %s\n\nRevision ID: %s\nRange: %d-%d\nSize: %dx%d (rows x columns)\nMerged Cells: %d\nNested Tables: %d\nFormat: %s\n",
+		output.TableIndex, output.DocumentID, output.RevisionID, selected.StartIndex, selected.EndIndex, selected.Rows, selected.Columns, len(selected.Merges), len(output.Tables)-1, format))
+
+	content := output.Content
+	if format == "json" {
+		data, _ := json.MarshalIndent(output.Tables, "", "  ")
+		content = string(data)
+	}
+	result.WriteString("\n--- Content ---\n" + strings.TrimRight(content, "\n") + "\n--- End of Table ---")
+
+	return mcp.NewToolResultStructured(output, result.String()), nil
+}
+
+func insertTableRowHandler(ctx context.Context, request mcp.CallToolRequest, input InsertTableRowInput) (*mcp.CallToolResult, error) {
+	writeControl, errResult := newWriteControl(input.RevisionID, input.TargetRevisionID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	count := max(input.Count, 1)
+
+	doc, table, errResult := getTable(ctx, input.DocumentID, input.TableIndex, writeControl)
+	if errResult != nil {
+		return errResult, nil
+	}
+	if input.RowIndex < 0 || input.RowIndex >= table.Table.Rows {
+		return mcp.NewToolResultError(fmt.Sprintf("Error: Row index %d is out of range. Table has %d rows.", input.RowIndex, table.Table.Rows)), nil
+	}
+	if table.Table.Rows+count > util.MaxTableRows {
+		return mcp.NewToolResultError(fmt.Sprintf("Error: Maximum %d rows allowed.", util.MaxTableRows)), nil
+	}
+
+	var requests []*docs.Request
+	for range count {
+		requests = append(requests, &docs.Request{
+			InsertTableRow: &docs.InsertTableRowRequest{
+				TableCellLocation: tableCellLocation(table, input.RowIndex, 0),
+				InsertBelow:       !input.Above,
+			},
+		})
+	}
+
+	output := tableOutput(doc, input.TableIndex, table)
+	output.Rows += count
+
+	position := "below"
+	if input.Above {
+		position = "above"
+	}
+	result := fmt.Sprintf("Table rows inserted successfully!\n\nDocument ID: %s\nTable: %d\nInserted: %d row(s) %s row %d",
+		input.DocumentID, input.TableIndex, count, position, input.RowIndex)
+
+	return updateTable(ctx, "insert table row", writeControl, requests, output, result), nil
+}
+
+func deleteTableRowHandler(ctx context.Context, request mcp.CallToolRequest, input DeleteTableRowInput) (*mcp.CallToolResult, error) {
+	writeControl, errResult := newWriteControl(input.RevisionID, input.TargetRevisionID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	count := max(input.Count, 1)
+
+	doc, table, errResult := getTable(ctx, input.DocumentID, input.TableIndex, writeControl)
+	if errResult != nil {
+		return errResult, nil
+	}
+	if input.RowIndex < 0 || input.RowIndex+count > table.Table.Rows {
+		return mcp.NewToolResultError(fmt.Sprintf("Error: Rows %d to %d are out of range. Table has %d rows.", input.RowIndex, input.RowIndex+count-1, table.Table.Rows)), nil
+	}
+	if count == table.Table.Rows {
+		return mcp.NewToolResultError("Error: Cannot delete every row of a table. Delete the table's range with delete_text instead."), nil
+	}
+
+	// Deleting a row moves the next one up, so the same row is deleted count times
+	var requests []*docs.Request
+	for range count {
+		requests = append(requests, &docs.Request{
+			DeleteTableRow: &docs.DeleteTableRowRequest{
+				TableCellLocation: tableCellLocation(table, input.RowIndex, 0),
+			},
+		})
+	}
+
+	output := tableOutput(doc, input.TableIndex, table)
+	output.Rows -= count
+
+	result := fmt.Sprintf("Table rows deleted successfully!\n\nDocument ID: %s\nTable: %d\nDeleted: %d row(s) from row %d",
+		input.DocumentID, input.TableIndex, count, input.RowIndex)
+
+	return updateTable(ctx, "delete table row", writeControl, requests, output, result), nil
+}
+
+func insertTableColumnHandler(ctx context.Context, request mcp.CallToolRequest, input InsertTableColumnInput) (*mcp.CallToolResult, error) {
+	writeControl, errResult := newWriteControl(input.RevisionID, input.TargetRevisionID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	count := max(input.Count, 1)
+
+	doc, table, errResult := getTable(ctx, input.DocumentID, input.TableIndex, writeControl)
+	if errResult != nil {
+		return errResult, nil
+	}
+	if input.ColumnIndex < 0 || input.ColumnIndex >= table.Table.Columns {
+		return mcp.NewToolResultError(fmt.Sprintf("Error: Column index %d is out of range. Table has %d columns.", input.ColumnIndex, table.Table.Columns)), nil
+	}
+	if table.Table.Columns+count > util.MaxTableColumns {
+		return mcp.NewToolResultError(fmt.Sprintf("Error: Maximum %d columns allowed.", util.MaxTableColumns)), nil
+	}
+
+	var requests []*docs.Request
+	for range count {
+		requests = append(requests, &docs.Request{
+			InsertTableColumn: &docs.InsertTableColumnRequest{
+				TableCellLocation: tableCellLocation(table, 0, input.ColumnIndex),
+				InsertRight:       !input.Left,
+			},
+		})
+	}
+
+	output := tableOutput(doc, input.TableIndex, table)
+	output.Columns += count
+
+	position := "right of"
+	if input.Left {
+		position = "left of"
+	}
+	result := fmt.Sprintf("Table columns inserted successfully!\n\nDocument ID: %s\nTable: %d\nInserted: %d column(s) %s column %d",
+		input.DocumentID, input.TableIndex, count, position, input.ColumnIndex)
+
+	return updateTable(ctx, "insert table column", writeControl, requests, output, result), nil
+}
+
+func deleteTableColumnHandler(ctx context.Context, request mcp.CallToolRequest, input DeleteTableColumnInput) (*mcp.CallToolResult, error) {
+	writeControl, errResult := newWriteControl(input.RevisionID, input.TargetRevisionID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	count := max(input.Count, 1)
+
+	doc, table, errResult := getTable(ctx, input.DocumentID, input.TableIndex, writeControl)
+	if errResult != nil {
+		return errResult, nil
+	}
+	if input.ColumnIndex < 0 || input.ColumnIndex+count > table.Table.Columns {
+		return mcp.NewToolResultError(fmt.Sprintf("Error: Columns %d to %d are out of range. Table has %d columns.", input.ColumnIndex, input.ColumnIndex+count-1, table.Table.Columns)), nil
+	}
+	if count == table.Table.Columns {
+		return mcp.NewToolResultError("Error: Cannot delete every column of a table. Delete the table's range with delete_text instead."), nil
+	}
+
+	// Deleting a column moves the next one left, so the same column is deleted count times
+	var requests []*docs.Request
+	for range count {
+		requests = append(requests, &docs.Request{
+			DeleteTableColumn: &docs.DeleteTableColumnRequest{
+				TableCellLocation: tableCellLocation(table, 0, input.ColumnIndex),
+			},
+		})
+	}
+
+	output := tableOutput(doc, input.TableIndex, table)
+	output.Columns -= count
+
+	result := fmt.Sprintf("Table columns deleted successfully!\n\nDocument ID: %s\nTable: %d\nDeleted: %d column(s) from column %d",
+		input.DocumentID, input.TableIndex, count, input.ColumnIndex)
+
+	return updateTable(ctx, "delete table column", writeControl, requests, output, result), nil
+}
+
+func mergeTableCellsHandler(ctx context.Context, request mcp.CallToolRequest, input MergeTableCellsInput) (*mcp.CallToolResult, error) {
+	writeControl, errResult := newWriteControl(input.RevisionID, input.TargetRevisionID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	doc, table, errResult := getTable(ctx, input.DocumentID, input.TableIndex, writeControl)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	tableRange, errResult := cellRange(table, &input.RowIndex, &input.ColumnIndex, input.RowSpan, input.ColumnSpan)
+	if errResult != nil {
+		return errResult, nil
+	}
+	if tableRange.RowSpan*tableRange.ColumnSpan < 2 {
+		return mcp.NewToolResultError("Error: Give a row_span or column_span to merge more than one cell."), nil
+	}
+
+	requests := []*docs.Request{
+		{
+			MergeTableCells: &docs.MergeTableCellsRequest{
+				TableRange: tableRange,
+			},
+		},
+	}
+
+	result := fmt.Sprintf("Table cells merged successfully!\n\nDocument ID: %s\nTable: %d\nCells: %s",
+		input.DocumentID, input.TableIndex, describeCellRange(tableRange))
+
+	return updateTable(ctx, "merge table cells", writeControl, requests, tableOutput(doc, input.TableIndex, table), result), nil
+}
+
+func unmergeTableCellsHandler(ctx context.Context, request mcp.CallToolRequest, input UnmergeTableCellsInput) (*mcp.CallToolResult, error) {
+	writeControl, errResult := newWriteControl(input.RevisionID, input.TargetRevisionID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	doc, table, errResult := getTable(ctx, input.DocumentID, input.TableIndex, writeControl)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	tableRange, errResult := cellRange(table, input.RowIndex, input.ColumnIndex, input.RowSpan, input.ColumnSpan)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	requests := []*docs.Request{
+		{
+			UnmergeTableCells: &docs.UnmergeTableCellsRequest{
+				TableRange: tableRange,
+			},
+		},
+	}
+
+	result := fmt.Sprintf("Table cells unmerged successfully!\n\nDocument ID: %s\nTable: %d\nCells: %s",
+		input.DocumentID, input.TableIndex, describeCellRange(tableRange))
+
+	return updateTable(ctx, "unmerge table cells", writeControl, requests, tableOutput(doc, input.TableIndex, table), result), nil
+}
+
+func setTableColumnWidthHandler(ctx context.Context, request mcp.CallToolRequest, input SetTableColumnWidthInput) (*mcp.CallToolResult, error) {
+	writeControl, errResult := newWriteControl(input.RevisionID, input.TargetRevisionID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	if input.Width != 0 && input.Width < 5 {
+		return mcp.NewToolResultError("Error: Column width must be at least 5 points."), nil
+	}
+
+	doc, table, errResult := getTable(ctx, input.DocumentID, input.TableIndex, writeControl)
+	if errResult != nil {
+		return errResult, nil
+	}
+	for _, column := range input.Columns {
+		if column < 0 || column >= table.Table.Columns {
+			return mcp.NewToolResultError(fmt.Sprintf("Error: Column index %d is out of range. Table has %d columns.", column, table.Table.Columns)), nil
+		}
+	}
+
+	properties := &docs.TableColumnProperties{WidthType: "EVENLY_DISTRIBUTED"}
+	fields := "widthType"
+	width := "evenly distributed"
+	if input.Width > 0 {
+		properties = &docs.TableColumnProperties{
+			WidthType: "FIXED_WIDTH",
+			Width:     &docs.Dimension{Magnitude: input.Width, Unit: "PT"},
+		}
+		fields = "widthType,width"
+		width = fmt.Sprintf("%g points", input.Width)
+	}
+
+	requests := []*docs.Request{
+		{
+			UpdateTableColumnProperties: &docs.UpdateTableColumnPropertiesRequest{
+				TableStartLocation:    &docs.Location{Index: table.StartIndex},
+				ColumnIndices:         input.Columns,
+				TableColumnProperties: properties,
+				Fields:                fields,
+			},
+		},
+	}
+
+	columns := "all"
+	if len(input.Columns) > 0 {
+		names := make([]string, len(input.Columns))
+		for i, column := range input.Columns {
+			names[i] = strconv.FormatInt(column, 10)
+		}
+		columns = strings.Join(names, ", ")
+	}
+	result := fmt.Sprintf("Table column width set successfully!\n\nDocument ID: %s\nTable: %d\nColumns: %s\nWidth: %s",
+		input.DocumentID, input.TableIndex, columns, width)
+
+	return updateTable(ctx, "set table column width", writeControl, requests, tableOutput(doc, input.TableIndex, table), result), nil
+}
+
+func styleTableCellsHandler(ctx context.Context, request mcp.CallToolRequest, input StyleTableCellsInput) (*mcp.CallToolResult, error) {
+	writeControl, errResult := newWriteControl(input.RevisionID, input.TargetRevisionID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	style, fields, err := tableCellStyle(input)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error: %v", err)), nil
+	}
+
+	doc, table, errResult := getTable(ctx, input.DocumentID, input.TableIndex, writeControl)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	tableRange, errResult := cellRange(table, input.RowIndex, input.ColumnIndex, input.RowSpan, input.ColumnSpan)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	requests := []*docs.Request{
+		{
+			UpdateTableCellStyle: &docs.UpdateTableCellStyleRequest{
+				TableRange:     tableRange,
+				TableCellStyle: style,
+				Fields:         fields,
+			},
+		},
+	}
+
+	result := fmt.Sprintf("Table cells styled successfully!\n\nDocument ID: %s\nTable: %d\nCells: %s\nChanged: %s",
+		input.DocumentID, input.TableIndex, describeCellRange(tableRange), fields)
+
+	return updateTable(ctx, "style table cells", writeControl, requests, tableOutput(doc, input.TableIndex, table), result), nil
+}
+
+func updateTableCellsHandler(ctx context.Context, request mcp.CallToolRequest, input UpdateTableCellsInput) (*mcp.CallToolResult, error) {
+	writeControl, errResult := newWriteControl(input.RevisionID, input.TargetRevisionID)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	updates := append([]TableCellUpdate{}, input.Cells...)
+	for r, row := range tableData(input.Data) {
+		for c, text := range row {
+			updates = append(updates, TableCellUpdate{Row: input.RowIndex + int64(r), Column: input.ColumnIndex + int64(c), Text: text})
+		}
+	}
+	if len(updates) == 0 {
+		return mcp.NewToolResultError("Error: Give the cells or data to write."), nil
+	}
+
+	doc, table, errResult := getTable(ctx, input.DocumentID, input.TableIndex, writeControl)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	type cellUpdate struct {
+		cell *docs.TableCell
+		text string
+	}
+	var cells []cellUpdate
+	seen := map[[2]int64]bool{}
+	for _, update := range updates {
+		if update.Row < 0 || update.Row >= int64(len(table.Table.TableRows)) {
+			return mcp.NewToolResultError(fmt.Sprintf("Error: Row index %d is out of range. Table has %d rows.", update.Row, len(table.Table.TableRows))), nil
+		}
+		row := table.Table.TableRows[update.Row]
+		if update.Column < 0 || update.Column >= int64(len(row.TableCells)) {
+			return mcp.NewToolResultError(fmt.Sprintf("Error: Column index %d is out of range. Row has %d columns.", update.Column, len(row.TableCells))), nil
+		}
+		key := [2]int64{update.Row, update.Column}
+		if seen[key] {
+			return mcp.NewToolResultError(fmt.Sprintf("Error: Cell at row %d, column %d is given more than once.", update.Row, update.Column)), nil
+		}
+		seen[key] = true
+		cells = append(cells, cellUpdate{cell: row.TableCells[update.Column], text: update.Text})
+	}
+
+	// Write the cells last to first so each edit leaves the indices of the
+	// cells before it unchanged
+	sort.Slice(cells, func(i, j int) bool {
+		return cells[i].cell.StartIndex > cells[j].cell.StartIndex
+	})
+	var requests []*docs.Request
+	for _, update := range cells {
+		requests = append(requests, util.TableCellTextRequests(update.cell, update.text)...)
+	}
+
+	output := tableOutput(doc, input.TableIndex, table)
+	output.Cells = len(cells)
+
+	result := fmt.Sprintf("Table cells updated successfully!\n\nDocument ID: %s\nTable: %d\nCells Updated: %d",
+		input.DocumentID, input.TableIndex, len(cells))
+
+	if len(requests) == 0 {
+		return mcp.NewToolResultStructured(output, result), nil
+	}
+	return updateTable(ctx, "update table cells", writeControl, requests, output, result), nil
+}
+
+// selectTable finds the table a read_table call asks for among the tables of
+// the document body, returning its position among all of them. A non-nil
+// result is an error to return.
+func selectTable(doc *docs.Document, input ReadTableInput) (int64, *docs.StructuralElement, *mcp.CallToolResult) {
+	if doc.Body == nil {
+		return 0, nil, mcp.NewToolResultError("Error: The document has no tables.")
+	}
+
+	start, end := int64(0), util.BodyEndIndex(doc)
+	if input.Heading != "" {
+		section, err := util.FindSection(doc, input.Heading)
+		if err != nil {
+			return 0, nil, mcp.NewToolResultError(fmt.Sprintf("Error: %v", err))
+		}
+		start, end = section.StartIndex, section.EndIndex
+	}
+
+	type candidate struct {
+		position int64
+		element  *docs.StructuralElement
+	}
+	var candidates []candidate
+	position := int64(-1)
+	for _, element := range doc.Body.Content {
+		if element.Table == nil {
+			continue
+		}
+		position++
+		if element.StartIndex < start || element.StartIndex >= end {
+			continue
+		}
+		if len(input.Header) > 0 && !tableHasHeader(element.Table, input.Header) {
+			continue
+		}
+		candidates = append(candidates, candidate{position: position, element: element})
+	}
+
+	if input.TableIndex < 0 || input.TableIndex >= int64(len(candidates)) {
+		filters := ""
+		if input.Heading != "" {
+			filters += fmt.Sprintf(" under heading '%s'", input.Heading)
+		}
+		if len(input.Header) > 0 {
+			filters += fmt.Sprintf(" with header %s", strings.Join(input.Header, ", "))
+		}
+		return 0, nil, mcp.NewToolResultError(fmt.Sprintf("Error: Table with index %d not found. The document has %d table(s)%s.", input.TableIndex, len(candidates), filters))
+	}
+
+	selected := candidates[input.TableIndex]
+	return selected.position, selected.element, nil
+}
+
+// readTableContents reads a table and the tables nested in its cells at any
+// depth, listed breadth first so each table follows its parent. For the csv
+// and markdown formats the tables are also rendered, with each cell holding a
+// nested table pointing at it by position in the list.
+func readTableContents(table *docs.StructuralElement, format string, headerRow, fillMerged bool) ([]TableContent, string, error) {
+	type pendingTable struct {
+		element     *docs.StructuralElement
+		parent      int
+		row, column int
+	}
+
+	tables := []TableContent{}
+	var rendered [][][]string
+	queue := []pendingTable{{element: table, parent: -1}}
+	for len(queue) > 0 {
+		pending := queue[0]
+		queue = queue[1:]
+
+		position := len(tables)
+		grid := util.ReadTableGrid(pending.element.Table, fillMerged)
+		content := TableContent{
+			Parent:     pending.parent,
+			Row:        pending.row,
+			Column:     pending.column,
+			StartIndex: pending.element.StartIndex,
+			EndIndex:   pending.element.EndIndex,
+			Rows:       len(grid.Cells),
+			Cells:      grid.Cells,
+			Merges:     grid.Merges,
+		}
+		if len(grid.Cells) > 0 {
+			content.Columns = len(grid.Cells[0])
+		}
+		if headerRow && len(grid.Cells) > 0 {
+			content.Header, content.Records = tableRecords(grid.Cells)
+		}
+		tables = append(tables, content)
+
+		cells := make([][]string, len(grid.Cells))
+		for r, row := range grid.Cells {
+			cells[r] = append([]string{}, row...)
+		}
+		next := position + len(queue) + 1
+		for i, nested := range grid.Nested {
+			reference := fmt.Sprintf("[table %d]", next+i)
+			if cells[nested.Row][nested.Column] != "" {
+				reference = "\n" + reference
+			}
+			cells[nested.Row][nested.Column] += reference
+			queue = append(queue, pendingTable{element: nested.Element, parent: position, row: nested.Row, column: nested.Column})
+		}
+		rendered = append(rendered, cells)
+	}
+
+	if format == "json" {
+		return tables, "", nil
+	}
+
+	var sb strings.Builder
+	for i, cells := range rendered {
+		if i > 0 {
+			sb.WriteString(fmt.Sprintf("\n[table %d] in table %d, row %d, column %d:\n", i, tables[i].Parent, tables[i].Row, tables[i].Column))
+		}
+		if format == "csv" {
+			text, err := util.TableCSV(cells)
+			if err != nil {
+				return nil, "", err
+			}
+			sb.WriteString(text)
+		} else {
+			sb.WriteString(util.TableMarkdown(cells))
+		}
+	}
+	return tables, sb.String(), nil
+}
+
+// tableHasHeader reports whether the first row of a table contains a cell
+// with each of the names, ignoring case and surrounding whitespace
+func tableHasHeader(table *docs.Table, names []string) bool {
+	if len(table.TableRows) == 0 {
+		return false
+	}
+
+	cells := map[string]bool{}
+	for _, cell := range table.TableRows[0].TableCells {
+		cells[strings.ToLower(strings.TrimSpace(util.TableCellText(cell)))] = true
+	}
+	for _, name := range names {
+		if !cells[strings.ToLower(strings.TrimSpace(name))] {
+			return false
+		}
+	}
+	return true
+}
+
+// tableRecords turns the rows after the first into records keyed by the
+// first row. Empty header cells are named column_N and repeated names get a
+// numeric suffix, so every key is unique.
+func tableRecords(cells [][]string) ([]string, []map[string]string) {
+	header := make([]string, len(cells[0]))
+	used := map[string]int{}
+	for c, text := range cells[0] {
+		key := strings.TrimSpace(strings.ReplaceAll(text, "\n", " "))
+		if key == "" {
+			key = fmt.Sprintf("column_%d", c+1)
+		}
+		used[key]++
+		if used[key] > 1 {
+			key = fmt.Sprintf("%s_%d", key, used[key])
+		}
+		header[c] = key
+	}
+
+	records := make([]map[string]string, 0, len(cells)-1)
+	for _, row := range cells[1:] {
+		record := make(map[string]string, len(header))
+		for c, key := range header {
+			record[key] = row[c]
+		}
+		records = append(records, record)
+	}
+	return header, records
+}
+
+// getTable reads a document and finds the table at the 0-based position among
+// the tables of its body, pinning the write control to the revision read. A
+// non-nil result is an error to return.
+func getTable(ctx context.Context, documentID string, tableIndex int64, writeControl *docs.WriteControl) (*docs.Document, *docs.StructuralElement, *mcp.CallToolResult) {
+	docsService := services.GoogleDocsClient()
+
+	doc, err := docsService.Documents.Get(documentID).Context(ctx).Do()
+	if err != nil {
+		return nil, nil, util.HandleGoogleAPIError("get document for table edit", err)
+	}
+
+	if errResult := pinWriteControl(writeControl, doc); errResult != nil {
+		return nil, nil, errResult
+	}
+
+	tableElement := findTableElement(doc, tableIndex)
+	if tableElement == nil {
+		return nil, nil, mcp.NewToolResultError(fmt.Sprintf("Error: Table with index %d not found in document.", tableIndex))
+	}
+	return doc, tableElement, nil
+}
+
+// updateTable applies the requests of a table edit and returns the output
+// completed with the new revision, or the error
+func updateTable(ctx context.Context, operation string, writeControl *docs.WriteControl, requests []*docs.Request, output TableOutput, result string) *mcp.CallToolResult {
+	docsService := services.GoogleDocsClient()
+
+	batchUpdateRequest := &docs.BatchUpdateDocumentRequest{
+		Requests:     requests,
+		WriteControl: writeControl,
+	}
+
+	response, err := docsService.Documents.BatchUpdate(output.DocumentID, batchUpdateRequest).Context(ctx).Do()
+	if err != nil {
+		return handleWriteError(ctx, operation, output.DocumentID, writeControl, err)
+	}
+
+	output.RevisionID = revisionAfter(response)
+	return mcp.NewToolResultStructured(output, result)
+}
+
+// tableOutput describes a table as read before an edit
+func tableOutput(doc *docs.Document, tableIndex int64, table *docs.StructuralElement) TableOutput {
+	return TableOutput{
+		DocumentID: doc.DocumentId,
+		RevisionID: doc.RevisionId,
+		TableIndex: tableIndex,
+		StartIndex: table.StartIndex,
+		Rows:       table.Table.Rows,
+		Columns:    table.Table.Columns,
+	}
+}
+
+// tableCellLocation returns the location of a cell of a table
+func tableCellLocation(table *docs.StructuralElement, row, column int64) *docs.TableCellLocation {
+	return &docs.TableCellLocation{
+		TableStartLocation: &docs.Location{Index: table.StartIndex},
+		RowIndex:           row,
+		ColumnIndex:        column,
+	}
+}
+
+// cellRange returns the rectangle of cells starting at a row and column and
+// spanning the given number of rows and columns, which default to 1. Without
+// a row or column it spans every row or column. A non-nil result is an error
+// to return.
+func cellRange(table *docs.StructuralElement, rowIndex, columnIndex *int64, rowSpan, columnSpan int64) (*docs.TableRange, *mcp.CallToolResult) {
+	row, column := int64(0), int64(0)
+	rows, columns := table.Table.Rows, table.Table.Columns
+	if rowIndex != nil {
+		row, rows = *rowIndex, max(rowSpan, 1)
+	}
+	if columnIndex != nil {
+		column, columns = *columnIndex, max(columnSpan, 1)
+	}
+
+	if row < 0 || row+rows > table.Table.Rows {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("Error: Rows %d to %d are out of range. Table has %d rows.", row, row+rows-1, table.Table.Rows))
+	}
+	if column < 0 || column+columns > table.Table.Columns {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("Error: Columns %d to %d are out of range. Table has %d columns.", column, column+columns-1, table.Table.Columns))
+	}
+
+	return &docs.TableRange{
+		TableCellLocation: tableCellLocation(table, row, column),
+		RowSpan:           rows,
+		ColumnSpan:        columns,
+	}, nil
+}
+
+// describeCellRange describes a rectangle of cells for tool results
+func describeCellRange(tableRange *docs.TableRange) string {
+	location := tableRange.TableCellLocation
+	return fmt.Sprintf("rows %d-%d, columns %d-%d",
+		location.RowIndex, location.RowIndex+tableRange.RowSpan-1,
+		location.ColumnIndex, location.ColumnIndex+tableRange.ColumnSpan-1)
+}
+
+// tableCellStyle builds the cell style and field mask of a style_table_cells call
+func tableCellStyle(input StyleTableCellsInput) (*docs.TableCellStyle, string, error) {
+	style := &docs.TableCellStyle{}
+	var fields []string
+
+	if input.BackgroundColor != "" {
+		color, err := parseHexColor(input.BackgroundColor)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid background_color: %v", err)
+		}
+		style.BackgroundColor = &docs.OptionalColor{Color: color}
+		fields = append(fields, "backgroundColor")
+	}
+
+	if input.BorderColor != "" || input.BorderWidth != nil || input.BorderStyle != "" || input.Borders != "" {
+		border := &docs.TableCellBorder{
+			Color:     &docs.OptionalColor{Color: &docs.Color{RgbColor: &docs.RgbColor{}}},
+			Width:     &docs.Dimension{Magnitude: 1, Unit: "PT"},
+			DashStyle: "SOLID",
+		}
+		if input.BorderColor != "" {
+			color, err := parseHexColor(input.BorderColor)
+			if err != nil {
+				return nil, "", fmt.Errorf("invalid border_color: %v", err)
+			}
+			border.Color = &docs.OptionalColor{Color: color}
+		}
+		if input.BorderWidth != nil {
+			if *input.BorderWidth < 0 {
+				return nil, "", fmt.Errorf("border_width cannot be negative")
+			}
+			border.Width = &docs.Dimension{Magnitude: *input.BorderWidth, Unit: "PT", ForceSendFields: []string{"Magnitude"}}
+		}
+		if input.BorderStyle != "" {
+			switch strings.ToUpper(input.BorderStyle) {
+			case "SOLID", "DOT", "DASH":
+				border.DashStyle = strings.ToUpper(input.BorderStyle)
+			default:
+				return nil, "", fmt.Errorf("invalid border_style %q: must be 'SOLID', 'DOT', or 'DASH'", input.BorderStyle)
+			}
+		}
+
+		sides := input.Borders
+		if sides == "" || sides == "all" {
+			sides = "top,bottom,left,right"
+		}
+		for _, side := range strings.Split(sides, ",") {
+			switch strings.TrimSpace(strings.ToLower(side)) {
+			case "top":
+				style.BorderTop = border
+				fields = append(fields, "borderTop")
+			case "bottom":
+				style.BorderBottom = border
+				fields = append(fields, "borderBottom")
+			case "left":
+				style.BorderLeft = border
+				fields = append(fields, "borderLeft")
+			case "right":
+				style.BorderRight = border
+				fields = append(fields, "borderRight")
+			default:
+				return nil, "", fmt.Errorf("invalid border %q: must be 'all' or 'top', 'bottom', 'left', 'right'", side)
+			}
+		}
+	}
+
+	if input.ContentAlignment != "" {
+		switch strings.ToUpper(input.ContentAlignment) {
+		case "TOP", "MIDDLE", "BOTTOM":
+			style.ContentAlignment = strings.ToUpper(input.ContentAlignment)
+		default:
+			return nil, "", fmt.Errorf("invalid content_alignment %q: must be 'TOP', 'MIDDLE', or 'BOTTOM'", input.ContentAlignment)
+		}
+		fields = append(fields, "contentAlignment")
+	}
+
+	if len(fields) == 0 {
+		return nil, "", fmt.Errorf("no style changes specified")
+	}
+	return style, strings.Join(fields, ","), nil
+}
+
+// tableData converts cell values given as JSON to cell texts
+func tableData(rows [][]any) [][]string {
+	data := make([][]string, len(rows))
+	for r, row := range rows {
+		data[r] = make([]string, len(row))
+		for c, value := range row {
+			data[r][c] = tableCellText(value)
+		}
+	}
+	return data
+}
+
+// tableCellText formats a JSON cell value as cell text
+func tableCellText(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,136 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hdbrzgr/docs-mcp/services"
+	"github.com/hdbrzgr/docs-mcp/util"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"google.golang.org/api/docs/v1"
+	"google.golang.org/api/drive/v3"
+)
+
+// Input types for template tools
+type CreateFromTemplateInput struct {
+	TemplateID     string         `json:"template_id" validate:"required"`
+	Title          string         `json:"title" validate:"required"`
+	Values         map[string]any `json:"values"`
+	ParentFolderID string         `json:"parent_folder_id,omitempty"`
+}
+
+// Output types for template tools
+type CreateFromTemplateOutput struct {
+	TemplateID   string   `json:"template_id"`
+	DocumentID   string   `json:"document_id"`
+	Title        string   `json:"title"`
+	RevisionID   string   `json:"revision_id,omitempty"`
+	URL          string   `json:"url"`
+	Placeholders int      `json:"placeholders" jsonschema_description:"Placeholders replaced with a value"`
+	Images       int      `json:"images" jsonschema_description:"Placeholders replaced with an image"`
+	Sections     int      `json:"sections" jsonschema_description:"Repeated sections expanded"`
+	Rows         int      `json:"rows" jsonschema_description:"Repeated table rows expanded"`
+	Removed      int      `json:"removed" jsonschema_description:"Conditional blocks removed"`
+	Unresolved   []string `json:"unresolved" jsonschema_description:"Placeholders without a value, left in the document as they are"`
+}
+
+func RegisterTemplateTools(s *server.MCPServer) {
+	// Create from template tool
+	createFromTemplateTool := mcp.NewTool("create_from_template",
+		mcp.WithDescription("Create a new Google Docs document from a template document, filling its placeholders in a single update. "+
+			"{{name}} is replaced with a value (dotted names such as {{client.name}} look inside objects). "+
+			"{{#if name}}...{{/if}} is kept only when the value is present, true, non-zero and non-empty. "+
+			"{{#each items}} and {{/each}} on paragraphs of their own repeat the paragraphs between them for every item of an array, and {{#each items}} in a table row repeats the row; inside, {{field}} reads the item and {{this}} is the item itself. "+
+			"A value {\"type\": \"image\", \"url\": \"https://...\", \"width\": 200, \"height\": 100} inserts an image (size in points, optional)"),
+		mcp.WithString("template_id", mcp.Required(), mcp.Description("The unique identifier of the template document")),
+		mcp.WithString("title", mcp.Required(), mcp.Description("The title of the new document")),
+		mcp.WithObject("values", mcp.Required(), mcp.Description("Values for the placeholders, by name. Arrays feed {{#each}} blocks")),
+		mcp.WithString("parent_folder_id", mcp.Description("ID of the Drive folder to create the document in (default: the folder of the template)")),
+		mcp.WithOutputSchema[CreateFromTemplateOutput](),
+	)
+	s.AddTool(createFromTemplateTool, mcp.NewTypedToolHandler(createFromTemplateHandler))
+}
+
+func createFromTemplateHandler(ctx context.Context, request mcp.CallToolRequest, input CreateFromTemplateInput) (*mcp.CallToolResult, error) {
+	output, err := createFromTemplate(ctx, input.TemplateID, input.Title, input.ParentFolderID, input.Values)
+	if err != nil {
+		return util.HandleGoogleAPIError("create document from template", err), nil
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Document created from template successfully!\n\nTitle: %s\nDocument ID: %s\nTemplate ID: %s\nURL: %s\n",
+		output.Title, output.DocumentID, output.TemplateID, output.URL))
+	result.WriteString(fmt.Sprintf("Placeholders Filled: %d\nImages Inserted: %d\nSections Repeated: %d\nTable Rows Repeated: %d\nConditional Blocks Removed: %d\n",
+		output.Placeholders, output.Images, output.Sections, output.Rows, output.Removed))
+	if len(output.Unresolved) > 0 {
+		result.WriteString(fmt.Sprintf("\nNo value given for: %s\n", strings.Join(output.Unresolved, ", ")))
+	}
+
+	return mcp.NewToolResultStructured(output, result.String()), nil
+}
+
+// createFromTemplate copies a template and fills the copy with values in a
+// single batch update. The copy is only useful once filled, so it goes to the
+// trash when filling fails.
+func createFromTemplate(ctx context.Context, templateID, title, parentFolderID string, values map[string]any) (CreateFromTemplateOutput, error) {
+	driveService := services.GoogleDriveClient()
+	docsService := services.GoogleDocsClient()
+
+	copiedFile, err := copyDocument(ctx, driveService, templateID, title, parentFolderID)
+	if err != nil {
+		return CreateFromTemplateOutput{}, fmt.Errorf("copy template: %w", err)
+	}
+
+	// Trash the copy even when the request was cancelled, which may be why
+	// filling it failed
+	discardCopy := func() {
+		_, _ = driveService.Files.Update(copiedFile.Id, &drive.File{Trashed: true}).Context(context.WithoutCancel(ctx)).Do()
+	}
+
+	doc, err := docsService.Documents.Get(copiedFile.Id).Context(ctx).Do()
+	if err != nil {
+		discardCopy()
+		return CreateFromTemplateOutput{}, fmt.Errorf("read copy of template: %w", err)
+	}
+
+	batch, err := util.CompileTemplate(doc, values)
+	if err != nil {
+		discardCopy()
+		return CreateFromTemplateOutput{}, fmt.Errorf("invalid template: %w", err)
+	}
+
+	output := CreateFromTemplateOutput{
+		TemplateID:   templateID,
+		DocumentID:   copiedFile.Id,
+		Title:        copiedFile.Name,
+		RevisionID:   doc.RevisionId,
+		URL:          documentURL(copiedFile.Id),
+		Placeholders: batch.Placeholders,
+		Images:       batch.Images,
+		Sections:     batch.Sections,
+		Rows:         batch.Rows,
+		Removed:      batch.Removed,
+		Unresolved:   []string{},
+	}
+	output.Unresolved = append(output.Unresolved, batch.Unresolved...)
+
+	if len(batch.Requests) > 0 {
+		batchUpdateRequest := &docs.BatchUpdateDocumentRequest{
+			Requests: batch.Requests,
+			WriteControl: &docs.WriteControl{
+				RequiredRevisionId: doc.RevisionId,
+			},
+		}
+
+		response, err := docsService.Documents.BatchUpdate(copiedFile.Id, batchUpdateRequest).Context(ctx).Do()
+		if err != nil {
+			discardCopy()
+			return CreateFromTemplateOutput{}, fmt.Errorf("fill template: %w", err)
+		}
+		output.RevisionID = revisionAfter(response)
+	}
+
+	return output, nil
+}
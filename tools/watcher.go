@@ -0,0 +1,259 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hdbrzgr/docs-mcp/services"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// watchIntervalEnv names the environment variable holding how often the Drive
+// change feed is polled for subscribed documents, as a Go duration
+const watchIntervalEnv = "DOCS_WATCH_INTERVAL"
+
+// defaultWatchInterval is used when the watch interval is not configured
+const defaultWatchInterval = 30 * time.Second
+
+// resourceSubscription is a resource URI a client session has subscribed to.
+// An empty session ID stands for the only client of a stdio server.
+type resourceSubscription struct {
+	SessionID string
+	URI       string
+}
+
+// ResourceWatcher tracks resource subscriptions and polls the Drive change feed
+// for the subscribed documents, notifying subscribers when a document changes
+type ResourceWatcher struct {
+	server   *server.MCPServer
+	interval time.Duration
+
+	mu            sync.Mutex
+	subscriptions map[string]map[resourceSubscription]bool // By document ID
+	pageToken     string                                   // Drive change feed position
+}
+
+// NewResourceWatcher creates a watcher sending notifications through the given
+// server. The poll interval is read from DOCS_WATCH_INTERVAL.
+func NewResourceWatcher(s *server.MCPServer) *ResourceWatcher {
+	interval := defaultWatchInterval
+	if value := os.Getenv(watchIntervalEnv); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil && parsed > 0 {
+			interval = parsed
+		} else {
+			log.Printf("Invalid %s %q, using %s", watchIntervalEnv, value, defaultWatchInterval)
+		}
+	}
+
+	return &ResourceWatcher{
+		server:        s,
+		interval:      interval,
+		subscriptions: make(map[string]map[resourceSubscription]bool),
+	}
+}
+
+// Subscribe starts watching the document behind a gdoc:// resource URI for a
+// client session. The change feed position is taken on the first subscription,
+// so only changes made from then on are reported.
+func (w *ResourceWatcher) Subscribe(ctx context.Context, sessionID, uri string) error {
+	documentID, err := resourceDocumentID(uri)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	needsToken := w.pageToken == ""
+	w.mu.Unlock()
+
+	if needsToken {
+		driveService := services.GoogleDriveClient()
+
+		startToken, err := driveService.Changes.GetStartPageToken().
+			SupportsAllDrives(true).
+			Context(ctx).
+			Do()
+		if err != nil {
+			return fmt.Errorf("failed to start watching Drive changes: %v", err)
+		}
+
+		w.mu.Lock()
+		if w.pageToken == "" {
+			w.pageToken = startToken.StartPageToken
+		}
+		w.mu.Unlock()
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.subscriptions[documentID] == nil {
+		w.subscriptions[documentID] = make(map[resourceSubscription]bool)
+	}
+	w.subscriptions[documentID][resourceSubscription{SessionID: sessionID, URI: uri}] = true
+
+	return nil
+}
+
+// Unsubscribe stops watching a resource URI for a client session
+func (w *ResourceWatcher) Unsubscribe(sessionID, uri string) error {
+	documentID, err := resourceDocumentID(uri)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.subscriptions[documentID], resourceSubscription{SessionID: sessionID, URI: uri})
+	if len(w.subscriptions[documentID]) == 0 {
+		delete(w.subscriptions, documentID)
+	}
+	w.resetIfIdle()
+
+	return nil
+}
+
+// UnsubscribeSession drops all subscriptions of a client session that ended
+func (w *ResourceWatcher) UnsubscribeSession(sessionID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for documentID, subscribers := range w.subscriptions {
+		for subscription := range subscribers {
+			if subscription.SessionID == sessionID {
+				delete(subscribers, subscription)
+			}
+		}
+		if len(subscribers) == 0 {
+			delete(w.subscriptions, documentID)
+		}
+	}
+	w.resetIfIdle()
+}
+
+// resetIfIdle forgets the change feed position once nothing is subscribed, so
+// a later subscription does not replay changes made in between. The caller
+// must hold the lock.
+func (w *ResourceWatcher) resetIfIdle() {
+	if len(w.subscriptions) == 0 {
+		w.pageToken = ""
+	}
+}
+
+// Run polls the Drive change feed until the context is cancelled
+func (w *ResourceWatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.poll(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("Failed to poll Drive changes: %v", err)
+			}
+		}
+	}
+}
+
+// poll reads the changes made since the last poll and notifies the
+// subscribers of every changed document
+func (w *ResourceWatcher) poll(ctx context.Context) error {
+	w.mu.Lock()
+	pageToken := w.pageToken
+	w.mu.Unlock()
+
+	if pageToken == "" {
+		return nil
+	}
+
+	driveService := services.GoogleDriveClient()
+
+	changed := make(map[string]bool)
+	for {
+		changeList, err := driveService.Changes.List(pageToken).
+			IncludeItemsFromAllDrives(true).
+			SupportsAllDrives(true).
+			IncludeRemoved(true).
+			PageSize(1000).
+			Fields("nextPageToken,newStartPageToken,changes(fileId)").
+			Context(ctx).
+			Do()
+		if err != nil {
+			return err
+		}
+
+		for _, change := range changeList.Changes {
+			changed[change.FileId] = true
+		}
+
+		// The last page carries the position to continue from next time
+		if changeList.NewStartPageToken != "" {
+			pageToken = changeList.NewStartPageToken
+			break
+		}
+		pageToken = changeList.NextPageToken
+	}
+
+	var notify []resourceSubscription
+
+	w.mu.Lock()
+	// Subscriptions may have been dropped while the feed was read
+	if w.pageToken != "" {
+		w.pageToken = pageToken
+	}
+	for documentID := range changed {
+		for subscription := range w.subscriptions[documentID] {
+			// Revisions never change once created
+			if !strings.Contains(subscription.URI, "/revisions/") {
+				notify = append(notify, subscription)
+			}
+		}
+	}
+	w.mu.Unlock()
+
+	for _, subscription := range notify {
+		w.notify(subscription)
+	}
+
+	return nil
+}
+
+// notify tells a subscriber that a resource has been updated. Sessions that
+// are not connected at the moment are skipped; they keep their subscriptions.
+func (w *ResourceWatcher) notify(subscription resourceSubscription) {
+	params := map[string]any{"uri": subscription.URI}
+
+	if subscription.SessionID == "" {
+		w.server.SendNotificationToAllClients(mcp.MethodNotificationResourceUpdated, params)
+		return
+	}
+
+	err := w.server.SendNotificationToSpecificClient(subscription.SessionID, mcp.MethodNotificationResourceUpdated, params)
+	if err != nil && !errors.Is(err, server.ErrSessionNotFound) && !errors.Is(err, server.ErrSessionNotInitialized) {
+		log.Printf("Failed to notify session %s about %s: %v", subscription.SessionID, subscription.URI, err)
+	}
+}
+
+// resourceDocumentID returns the document ID of a gdoc:// resource URI
+func resourceDocumentID(uri string) (string, error) {
+	path, ok := strings.CutPrefix(uri, "gdoc://")
+	if !ok {
+		return "", fmt.Errorf("unsupported resource URI %q: only gdoc:// resources can be subscribed to", uri)
+	}
+
+	documentID, _, _ := strings.Cut(path, "/")
+	if documentID == "" {
+		return "", fmt.Errorf("resource URI %q has no document ID", uri)
+	}
+
+	return documentID, nil
+}
@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hdbrzgr/docs-mcp/services"
+	"github.com/hdbrzgr/docs-mcp/util"
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/api/docs/v1"
+)
+
+// withWriteControl adds the optional revision_id and target_revision_id
+// parameters shared by all tools that modify document content
+func withWriteControl() mcp.ToolOption {
+	return func(t *mcp.Tool) {
+		mcp.WithString("revision_id",
+			mcp.Description("Only apply the edit if the document is still at this revision (the Revision ID returned when you read it). If someone changed the document since, nothing is modified and a 'document changed' error is returned"),
+		)(t)
+		mcp.WithString("target_revision_id",
+			mcp.Description("Apply the edit on top of any changes made since this revision: indices are interpreted against this revision and Google Docs shifts them past collaborators' edits. Cannot be combined with revision_id"),
+		)(t)
+	}
+}
+
+// withRestoreWriteControl adds the revision check of restore_revision, whose
+// revision_id parameter already names the revision to restore. Overwriting a
+// document cannot be merged with newer changes, so there is no target revision.
+func withRestoreWriteControl() mcp.ToolOption {
+	return mcp.WithString("document_revision_id",
+		mcp.Description("Only restore in place if the document is still at this revision (the Revision ID returned when you read it). If someone changed the document since, nothing is modified and a 'document changed' error is returned"),
+	)
+}
+
+// newWriteControl builds the write control for a batch update from the
+// revision parameters. A nil write control means the latest revision is edited
+// unconditionally. A non-nil result is an error to return.
+func newWriteControl(revisionID, targetRevisionID string) (*docs.WriteControl, *mcp.CallToolResult) {
+	switch {
+	case revisionID != "" && targetRevisionID != "":
+		return nil, mcp.NewToolResultError("Error: Provide either revision_id or target_revision_id, not both.")
+	case revisionID != "":
+		return &docs.WriteControl{RequiredRevisionId: revisionID}, nil
+	case targetRevisionID != "":
+		return &docs.WriteControl{TargetRevisionId: targetRevisionID}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// pinWriteControl aligns the write control with a document the tool has just
+// read to compute indices itself. Those indices belong to the revision that was
+// read, so a required revision must match it and a target revision moves to it.
+// A non-nil result is an error to return.
+func pinWriteControl(writeControl *docs.WriteControl, doc *docs.Document) *mcp.CallToolResult {
+	if writeControl == nil {
+		return nil
+	}
+	if writeControl.RequiredRevisionId != "" && writeControl.RequiredRevisionId != doc.RevisionId {
+		return util.DocumentChangedError(doc.DocumentId, writeControl.RequiredRevisionId, doc.RevisionId)
+	}
+	if writeControl.TargetRevisionId != "" {
+		writeControl.TargetRevisionId = doc.RevisionId
+	}
+	return nil
+}
+
+// handleWriteError converts a failed batch update into a tool result. When the
+// update was rejected because the document moved past the required revision,
+// a structured "document changed" error with the current revision is returned.
+func handleWriteError(ctx context.Context, operation, documentID string, writeControl *docs.WriteControl, err error) *mcp.CallToolResult {
+	if writeControl == nil || !util.IsRevisionMismatch(err) {
+		return util.HandleGoogleAPIError(operation, err)
+	}
+
+	currentRevisionID := ""
+	docsService := services.GoogleDocsClient()
+	if doc, getErr := docsService.Documents.Get(documentID).Fields("revisionId").Context(ctx).Do(); getErr == nil {
+		currentRevisionID = doc.RevisionId
+	}
+
+	if writeControl.RequiredRevisionId != "" {
+		return util.DocumentChangedError(documentID, writeControl.RequiredRevisionId, currentRevisionID)
+	}
+
+	return mcp.NewToolResultError(fmt.Sprintf("Error: Failed to %s: the target revision can no longer be merged.\n\nError Code: TARGET_REVISION_UNAVAILABLE\nDocument ID: %s\nTarget Revision ID: %s\nCurrent Revision ID: %s\n\nRe-read the document and retry with fresh indices and the current revision.",
+		operation, documentID, writeControl.TargetRevisionId, currentRevisionID))
+}
@@ -0,0 +1,144 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/docs/v1"
+)
+
+// Anchor addresses a range of a document by its content instead of raw indices.
+// Exactly one of Match, Heading or Paragraph should be set.
+type Anchor struct {
+	Match      string `json:"match,omitempty"`      // Exact text to find
+	Occurrence int    `json:"occurrence,omitempty"` // Which occurrence of Match to use (1-based, default: 1)
+	Heading    string `json:"heading,omitempty"`    // Heading text or heading ID; resolves to the whole section
+	Paragraph  int    `json:"paragraph,omitempty"`  // Paragraph number (1-based, empty paragraphs are not counted)
+}
+
+// IsZero reports whether no anchor alternative is set
+func (a *Anchor) IsZero() bool {
+	return a == nil || (a.Match == "" && a.Heading == "" && a.Paragraph == 0)
+}
+
+// ResolveAnchor resolves an anchor against the document body and returns the
+// start and end index of the range it addresses
+func ResolveAnchor(doc *docs.Document, anchor *Anchor) (int64, int64, error) {
+	if anchor.IsZero() {
+		return 0, 0, fmt.Errorf("anchor must specify one of 'match', 'heading' or 'paragraph'")
+	}
+
+	set := 0
+	for _, present := range []bool{anchor.Match != "", anchor.Heading != "", anchor.Paragraph != 0} {
+		if present {
+			set++
+		}
+	}
+	if set > 1 {
+		return 0, 0, fmt.Errorf("anchor must specify only one of 'match', 'heading' or 'paragraph'")
+	}
+
+	var start, end int64
+	var err error
+	switch {
+	case anchor.Match != "":
+		start, end, err = resolveMatchAnchor(doc, anchor.Match, anchor.Occurrence)
+	case anchor.Heading != "":
+		// A heading addresses its whole section, found as read_section finds it
+		var section OutlineEntry
+		section, err = FindSection(doc, anchor.Heading)
+		start, end = section.StartIndex, section.EndIndex
+	default:
+		start, end, err = resolveParagraphAnchor(doc, anchor.Paragraph)
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// The final newline of the body can never be edited or deleted
+	if bodyEnd := BodyEndIndex(doc) - 1; end > bodyEnd && bodyEnd > start {
+		end = bodyEnd
+	}
+
+	return start, end, nil
+}
+
+// resolveMatchAnchor finds the nth occurrence of an exact text match
+func resolveMatchAnchor(doc *docs.Document, match string, occurrence int) (int64, int64, error) {
+	if occurrence <= 0 {
+		occurrence = 1
+	}
+
+	matches := NewTextMap(doc).FindAll(match, true)
+	if len(matches) == 0 {
+		return 0, 0, fmt.Errorf("text '%s' not found in the document", match)
+	}
+	if occurrence > len(matches) {
+		return 0, 0, fmt.Errorf("occurrence %d of '%s' requested, but the document contains only %d", occurrence, match, len(matches))
+	}
+
+	m := matches[occurrence-1]
+	return m.StartIndex, m.EndIndex, nil
+}
+
+// resolveParagraphAnchor returns the range of the nth non-empty body paragraph
+func resolveParagraphAnchor(doc *docs.Document, number int) (int64, int64, error) {
+	if number <= 0 {
+		return 0, 0, fmt.Errorf("paragraph number must be at least 1")
+	}
+
+	count := 0
+	if doc.Body != nil {
+		for _, element := range doc.Body.Content {
+			if element.Paragraph == nil || strings.TrimSpace(ParagraphText(element.Paragraph)) == "" {
+				continue
+			}
+			count++
+			if count == number {
+				return element.StartIndex, element.EndIndex, nil
+			}
+		}
+	}
+
+	return 0, 0, fmt.Errorf("paragraph %d requested, but the document contains only %d paragraphs", number, count)
+}
+
+// HeadingLevel returns the outline level of a paragraph: 1 for TITLE and
+// HEADING_1 through 6 for HEADING_6, and 0 for any other paragraph
+func HeadingLevel(paragraph *docs.Paragraph) int {
+	if paragraph == nil || paragraph.ParagraphStyle == nil {
+		return 0
+	}
+
+	switch paragraph.ParagraphStyle.NamedStyleType {
+	case "TITLE", "HEADING_1":
+		return 1
+	case "HEADING_2":
+		return 2
+	case "HEADING_3":
+		return 3
+	case "HEADING_4":
+		return 4
+	case "HEADING_5":
+		return 5
+	case "HEADING_6":
+		return 6
+	default:
+		return 0
+	}
+}
+
+// ParagraphText returns the text of a paragraph without its trailing newline
+func ParagraphText(paragraph *docs.Paragraph) string {
+	if paragraph == nil {
+		return ""
+	}
+
+	var sb strings.Builder
+	for _, element := range paragraph.Elements {
+		if element.TextRun != nil {
+			sb.WriteString(element.TextRun.Content)
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
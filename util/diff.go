@@ -0,0 +1,376 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DiffKind tells whether a diffed item is unchanged, inserted or deleted
+type DiffKind int
+
+const (
+	DiffEqual DiffKind = iota
+	DiffInsert
+	DiffDelete
+	DiffModify
+)
+
+// DiffOp is one item of a diff between two sequences. For DiffModify, Text is
+// the new version and Previous the old one.
+type DiffOp struct {
+	Kind     DiffKind
+	Text     string
+	Previous string
+}
+
+// maxDiffCells bounds the size of the LCS table. Larger inputs fall back to
+// reporting the whole differing middle as deleted and inserted.
+const maxDiffCells = 4_000_000
+
+// SplitParagraphs splits plain document text into paragraphs, dropping the
+// empty paragraph after the final newline
+func SplitParagraphs(text string) []string {
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// Diff computes the shortest edit script turning before into after, using the
+// longest common subsequence of the two sequences
+func Diff(before, after []string) []DiffOp {
+	// Common prefix and suffix need no table
+	prefix := 0
+	for prefix < len(before) && prefix < len(after) && before[prefix] == after[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(before)-prefix && suffix < len(after)-prefix &&
+		before[len(before)-1-suffix] == after[len(after)-1-suffix] {
+		suffix++
+	}
+
+	var ops []DiffOp
+	for _, text := range before[:prefix] {
+		ops = append(ops, DiffOp{Kind: DiffEqual, Text: text})
+	}
+	ops = append(ops, diffMiddle(before[prefix:len(before)-suffix], after[prefix:len(after)-suffix])...)
+	for _, text := range before[len(before)-suffix:] {
+		ops = append(ops, DiffOp{Kind: DiffEqual, Text: text})
+	}
+	return ops
+}
+
+// diffMiddle diffs two sequences that share no common prefix or suffix
+func diffMiddle(before, after []string) []DiffOp {
+	var ops []DiffOp
+
+	if len(before)*len(after) > maxDiffCells {
+		for _, text := range before {
+			ops = append(ops, DiffOp{Kind: DiffDelete, Text: text})
+		}
+		for _, text := range after {
+			ops = append(ops, DiffOp{Kind: DiffInsert, Text: text})
+		}
+		return ops
+	}
+
+	// lcs[i][j] is the LCS length of before[i:] and after[j:]
+	lcs := make([][]int, len(before)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(after)+1)
+	}
+	for i := len(before) - 1; i >= 0; i-- {
+		for j := len(after) - 1; j >= 0; j-- {
+			if before[i] == after[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(before) && j < len(after) {
+		switch {
+		case before[i] == after[j]:
+			ops = append(ops, DiffOp{Kind: DiffEqual, Text: before[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, DiffOp{Kind: DiffDelete, Text: before[i]})
+			i++
+		default:
+			ops = append(ops, DiffOp{Kind: DiffInsert, Text: after[j]})
+			j++
+		}
+	}
+	for ; i < len(before); i++ {
+		ops = append(ops, DiffOp{Kind: DiffDelete, Text: before[i]})
+	}
+	for ; j < len(after); j++ {
+		ops = append(ops, DiffOp{Kind: DiffInsert, Text: after[j]})
+	}
+	return ops
+}
+
+// DiffStats counts the items of a diff by kind
+func DiffStats(ops []DiffOp) (equal, inserted, deleted int) {
+	for _, op := range ops {
+		switch op.Kind {
+		case DiffEqual:
+			equal++
+		case DiffInsert:
+			inserted++
+		case DiffDelete:
+			deleted++
+		case DiffModify:
+			inserted++
+			deleted++
+		}
+	}
+	return equal, inserted, deleted
+}
+
+// modifySimilarity is the minimum word similarity for a deleted and an inserted
+// paragraph to be reported as one modified paragraph
+const modifySimilarity = 0.4
+
+// PairModified turns deleted and inserted paragraphs that sit at the same place
+// in the diff and share most of their words into DiffModify operations
+func PairModified(ops []DiffOp) []DiffOp {
+	var result []DiffOp
+	var deleted, inserted []DiffOp
+
+	flush := func() {
+		for k := 0; k < len(deleted) || k < len(inserted); k++ {
+			switch {
+			case k < len(deleted) && k < len(inserted) && WordSimilarity(deleted[k].Text, inserted[k].Text) >= modifySimilarity:
+				result = append(result, DiffOp{Kind: DiffModify, Text: inserted[k].Text, Previous: deleted[k].Text})
+			default:
+				if k < len(deleted) {
+					result = append(result, deleted[k])
+				}
+				if k < len(inserted) {
+					result = append(result, inserted[k])
+				}
+			}
+		}
+		deleted, inserted = nil, nil
+	}
+
+	for _, op := range ops {
+		switch op.Kind {
+		case DiffDelete:
+			deleted = append(deleted, op)
+		case DiffInsert:
+			inserted = append(inserted, op)
+		default:
+			flush()
+			result = append(result, op)
+		}
+	}
+	flush()
+
+	return result
+}
+
+// wordPattern splits text into words and the whitespace between them
+var wordPattern = regexp.MustCompile(`\s+|[^\s]+`)
+
+// WordSimilarity returns the share of words two strings have in common, from 0 to 1
+func WordSimilarity(before, after string) float64 {
+	a, b := strings.Fields(before), strings.Fields(after)
+	if len(a)+len(b) == 0 {
+		return 1
+	}
+	equal, _, _ := DiffStats(Diff(a, b))
+	return float64(2*equal) / float64(len(a)+len(b))
+}
+
+// WordDiff renders the changes between two versions of a paragraph inline,
+// marking removed words as [-text-] and added words as {+text+}
+func WordDiff(before, after string) string {
+	ops := Diff(wordPattern.FindAllString(before, -1), wordPattern.FindAllString(after, -1))
+
+	var sb strings.Builder
+	for i := 0; i < len(ops); {
+		kind := ops[i].Kind
+		var run strings.Builder
+		for ; i < len(ops) && ops[i].Kind == kind; i++ {
+			run.WriteString(ops[i].Text)
+		}
+
+		switch kind {
+		case DiffDelete:
+			sb.WriteString("[-" + run.String() + "-]")
+		case DiffInsert:
+			sb.WriteString("{+" + run.String() + "+}")
+		default:
+			sb.WriteString(run.String())
+		}
+	}
+	return sb.String()
+}
+
+// UnifiedDiff renders paragraph diff operations as a unified diff with the given
+// number of context paragraphs around each change. Modified paragraphs are shown
+// once, prefixed with "~", with word-level changes marked inline. Each hunk
+// header names the section the hunk starts in.
+func UnifiedDiff(ops []DiffOp, context int, fromName, toName string) string {
+	if context < 0 {
+		context = 0
+	}
+
+	var changes []int
+	for i, op := range ops {
+		if op.Kind != DiffEqual {
+			changes = append(changes, i)
+		}
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	// Line numbers and section of every operation in both versions
+	oldLine := make([]int, len(ops))
+	newLine := make([]int, len(ops))
+	sections := make([]string, len(ops))
+	o, n, section := 1, 1, ""
+	for i, op := range ops {
+		oldLine[i], newLine[i] = o, n
+		sections[i] = section
+		if op.Kind != DiffInsert {
+			o++
+		}
+		if op.Kind != DiffDelete {
+			n++
+		}
+		if heading := diffHeading(op); heading != "" {
+			section = heading
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("--- %s\n+++ %s\n", fromName, toName))
+
+	for h := 0; h < len(changes); {
+		// Extend the hunk while the next change is within the shared context
+		last := h
+		for last+1 < len(changes) && changes[last+1]-changes[last] <= 2*context+1 {
+			last++
+		}
+		start := max(changes[h]-context, 0)
+		end := min(changes[last]+context+1, len(ops))
+
+		oldCount, newCount := 0, 0
+		for _, op := range ops[start:end] {
+			if op.Kind != DiffInsert {
+				oldCount++
+			}
+			if op.Kind != DiffDelete {
+				newCount++
+			}
+		}
+
+		sb.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@", oldLine[start], oldCount, newLine[start], newCount))
+		if sections[start] != "" {
+			sb.WriteString(" " + sections[start])
+		}
+		sb.WriteString("\n")
+
+		for _, op := range ops[start:end] {
+			switch op.Kind {
+			case DiffEqual:
+				sb.WriteString("  " + op.Text + "\n")
+			case DiffDelete:
+				sb.WriteString("- " + op.Text + "\n")
+			case DiffInsert:
+				sb.WriteString("+ " + op.Text + "\n")
+			case DiffModify:
+				sb.WriteString("~ " + WordDiff(op.Previous, op.Text) + "\n")
+			}
+		}
+
+		h = last + 1
+	}
+
+	return sb.String()
+}
+
+// SummarizeDiff describes paragraph diff operations in a few sentences grouped
+// by section, such as "3 paragraphs edited under 'Pricing'"
+func SummarizeDiff(ops []DiffOp) []string {
+	type group struct {
+		section string
+		noun    string
+		verb    string
+	}
+
+	var order []group
+	counts := make(map[group]int)
+	section := ""
+
+	for _, op := range ops {
+		if op.Kind != DiffEqual && !isTableSeparator(op.Text) {
+			g := group{section: section, noun: "paragraph"}
+			if isTableRow(op.Text) {
+				g.noun = "table row"
+			}
+			switch op.Kind {
+			case DiffInsert:
+				g.verb = "added"
+			case DiffDelete:
+				g.verb = "removed"
+			case DiffModify:
+				g.verb = "edited"
+			}
+			if counts[g] == 0 {
+				order = append(order, g)
+			}
+			counts[g]++
+		}
+
+		if heading := diffHeading(op); heading != "" {
+			section = heading
+		}
+	}
+
+	var summary []string
+	for _, g := range order {
+		noun := g.noun
+		if counts[g] != 1 {
+			noun += "s"
+		}
+		line := fmt.Sprintf("%d %s %s", counts[g], noun, g.verb)
+		if g.section != "" {
+			line += fmt.Sprintf(" under '%s'", g.section)
+		}
+		summary = append(summary, line)
+	}
+	return summary
+}
+
+// diffHeading returns the text of a Markdown heading paragraph, or "" when the
+// paragraph is not a heading
+func diffHeading(op DiffOp) string {
+	rest := strings.TrimLeft(op.Text, "#")
+	if rest == op.Text || !strings.HasPrefix(rest, " ") {
+		return ""
+	}
+	return strings.TrimSpace(rest)
+}
+
+// isTableRow reports whether a Markdown line is a table row
+func isTableRow(text string) bool {
+	text = strings.TrimSpace(text)
+	return len(text) > 1 && strings.HasPrefix(text, "|") && strings.HasSuffix(text, "|")
+}
+
+// isTableSeparator reports whether a Markdown line is the row separating a
+// table header from its body
+func isTableSeparator(text string) bool {
+	return isTableRow(text) && strings.Trim(strings.TrimSpace(text), "|-: ") == ""
+}
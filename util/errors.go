@@ -1,11 +1,15 @@
 package util
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"runtime/debug"
+	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/api/googleapi"
 )
 
 // ErrorGuard wraps a tool handler function to catch and handle panics gracefully
@@ -15,7 +19,7 @@ func ErrorGuard(handler func(ctx interface{}, request interface{}) (*mcp.CallToo
 			if r := recover(); r != nil {
 				log.Printf("Panic in tool handler: %v\n%s", r, debug.Stack())
 				err = fmt.Errorf("internal error: %v", r)
-				result = mcp.NewToolResultText(fmt.Sprintf("Error: %v", err))
+				result = mcp.NewToolResultError(fmt.Sprintf("Error: %v", err))
 			}
 		}()
 
@@ -62,7 +66,42 @@ func HandleGoogleAPIError(operation string, err error) *mcp.CallToolResult {
 		errorMsg += "\n- Consider implementing exponential backoff"
 	}
 
-	return mcp.NewToolResultText(errorMsg)
+	return mcp.NewToolResultError(errorMsg)
+}
+
+// IsRevisionMismatch reports whether a batch update was rejected because the
+// document is no longer at the required or target revision
+func IsRevisionMismatch(err error) bool {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	switch apiErr.Code {
+	case http.StatusBadRequest, http.StatusConflict, http.StatusPreconditionFailed:
+		return strings.Contains(strings.ToLower(apiErr.Message), "revision")
+	default:
+		return false
+	}
+}
+
+// DocumentChangedError returns the error for an edit that was rejected because
+// the document was modified after the caller read it
+func DocumentChangedError(documentID, requiredRevisionID, currentRevisionID string) *mcp.CallToolResult {
+	if currentRevisionID == "" {
+		currentRevisionID = "unknown"
+	}
+
+	errorMsg := "Error: The document changed since you read it. No changes were made."
+	errorMsg += "\n\nError Code: DOCUMENT_CHANGED"
+	errorMsg += fmt.Sprintf("\nDocument ID: %s", documentID)
+	errorMsg += fmt.Sprintf("\nRequired Revision ID: %s", requiredRevisionID)
+	errorMsg += fmt.Sprintf("\nCurrent Revision ID: %s", currentRevisionID)
+	errorMsg += "\n\nTo continue, either:"
+	errorMsg += "\n- Re-read the document to get fresh indices and the current revision_id, then retry"
+	errorMsg += "\n- Retry with target_revision_id set to the revision you read to merge the edit with the newer changes"
+
+	return mcp.NewToolResultError(errorMsg)
 }
 
 // contains checks if a string contains a substring (case-insensitive helper)
@@ -203,7 +203,9 @@ func FormatDriveFile(file *drive.File) string {
 
 	sb.WriteString(fmt.Sprintf("ID: %s\n", file.Id))
 	sb.WriteString(fmt.Sprintf("Name: %s\n", file.Name))
-	sb.WriteString(fmt.Sprintf("MIME Type: %s\n", file.MimeType))
+	if file.MimeType != "" {
+		sb.WriteString(fmt.Sprintf("MIME Type: %s\n", file.MimeType))
+	}
 	
 	if file.CreatedTime != "" {
 		if createdTime, err := time.Parse(time.RFC3339, file.CreatedTime); err == nil {
@@ -217,6 +219,12 @@ func FormatDriveFile(file *drive.File) string {
 		}
 	}
 	
+	if file.ViewedByMeTime != "" {
+		if viewedTime, err := time.Parse(time.RFC3339, file.ViewedByMeTime); err == nil {
+			sb.WriteString(fmt.Sprintf("Last Viewed by Me: %s\n", viewedTime.Format("2006-01-02 15:04:05")))
+		}
+	}
+	
 	if len(file.Owners) > 0 {
 		sb.WriteString("Owners: ")
 		for i, owner := range file.Owners {
@@ -265,43 +273,8 @@ func FormatDriveFileCompact(file *drive.File) string {
 	return strings.Join(parts, " | ")
 }
 
-// ExtractPlainText extracts plain text content from a Google Docs document
+// ExtractPlainText extracts plain text content from a Google Docs document.
+// Use NewTextMap to translate positions in the text to document indices.
 func ExtractPlainText(doc *docs.Document) string {
-	var sb strings.Builder
-	
-	if doc.Body != nil && len(doc.Body.Content) > 0 {
-		extractTextFromElements(doc.Body.Content, &sb)
-	}
-	
-	return sb.String()
-}
-
-// extractTextFromElements recursively extracts text from structural elements
-func extractTextFromElements(elements []*docs.StructuralElement, sb *strings.Builder) {
-	for _, element := range elements {
-		if element.Paragraph != nil {
-			extractTextFromParagraph(element.Paragraph, sb)
-		} else if element.Table != nil {
-			extractTextFromTable(element.Table, sb)
-		}
-	}
-}
-
-// extractTextFromParagraph extracts text from a paragraph
-func extractTextFromParagraph(paragraph *docs.Paragraph, sb *strings.Builder) {
-	for _, element := range paragraph.Elements {
-		if element.TextRun != nil {
-			sb.WriteString(element.TextRun.Content)
-		}
-	}
-	sb.WriteString("\n")
-}
-
-// extractTextFromTable extracts text from a table
-func extractTextFromTable(table *docs.Table, sb *strings.Builder) {
-	for _, row := range table.TableRows {
-		for _, cell := range row.TableCells {
-			extractTextFromElements(cell.Content, sb)
-		}
-	}
+	return NewTextMap(doc).Text
 }
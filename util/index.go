@@ -0,0 +1,362 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf16"
+
+	"google.golang.org/api/docs/v1"
+)
+
+// TextSegment links a span of extracted plain text to the document indices it came from
+type TextSegment struct {
+	TextStart int   // Byte offset of the segment in TextMap.Text
+	TextEnd   int   // Byte offset just after the segment in TextMap.Text
+	DocStart  int64 // Docs API index of the first character
+	DocEnd    int64 // Docs API index just after the last character
+	Container int   // Number of the segment, table cell or table of contents holding the text
+}
+
+// TextMap holds the plain text of a document segment together with the mapping
+// between byte offsets in that text and Docs API indices. Docs indices count
+// UTF-16 code units and include structural markers (tables, inline objects,
+// section breaks), so they cannot be derived from the text alone.
+type TextMap struct {
+	Text     string
+	Segments []TextSegment
+
+	containers []string // Kind of each container, by number
+	fixed      []int    // Byte offsets of the newlines that cannot be deleted
+}
+
+// NewTextMap builds a text map for the body of a document
+func NewTextMap(doc *docs.Document) *TextMap {
+	if doc == nil || doc.Body == nil {
+		return &TextMap{}
+	}
+
+	return NewTextMapFromElements(doc.Body.Content)
+}
+
+// NewTextMapFromElements builds a text map for a list of structural elements,
+// such as the content of a header, footer, footnote or table cell
+func NewTextMapFromElements(elements []*docs.StructuralElement) *TextMap {
+	m := &TextMap{}
+	var sb strings.Builder
+	m.appendElements(elements, &sb, "segment")
+	m.Text = sb.String()
+	return m
+}
+
+// appendElements recursively appends the text runs of elements in document
+// order. Every table cell and table of contents is a container of its own.
+func (m *TextMap) appendElements(elements []*docs.StructuralElement, sb *strings.Builder, kind string) {
+	container := len(m.containers)
+	m.containers = append(m.containers, kind)
+
+	// The newline ending the paragraph before a table, table of contents or
+	// section break cannot be deleted, nor can the last one of the container
+	lastNewline := -1
+	fixNewline := func() {
+		if lastNewline >= 0 {
+			m.fixed = append(m.fixed, lastNewline)
+			lastNewline = -1
+		}
+	}
+
+	for _, element := range elements {
+		switch {
+		case element.Paragraph != nil:
+			for _, pe := range element.Paragraph.Elements {
+				if pe.TextRun == nil || pe.TextRun.Content == "" {
+					continue
+				}
+				start := sb.Len()
+				sb.WriteString(pe.TextRun.Content)
+				m.Segments = append(m.Segments, TextSegment{
+					TextStart: start,
+					TextEnd:   sb.Len(),
+					DocStart:  pe.StartIndex,
+					DocEnd:    pe.StartIndex + UTF16Length(pe.TextRun.Content),
+					Container: container,
+				})
+			}
+			lastNewline = -1
+			if strings.HasSuffix(sb.String(), "\n") {
+				lastNewline = sb.Len() - 1
+			}
+		case element.Table != nil:
+			fixNewline()
+			for _, row := range element.Table.TableRows {
+				for _, cell := range row.TableCells {
+					m.appendElements(cell.Content, sb, "table cell")
+				}
+			}
+		case element.TableOfContents != nil:
+			fixNewline()
+			m.appendElements(element.TableOfContents.Content, sb, "table of contents")
+		case element.SectionBreak != nil:
+			fixNewline()
+		}
+	}
+	fixNewline()
+}
+
+// CheckDeletable returns an error when the text between two byte offsets
+// cannot be deleted in one range: it spans more than one segment, table cell
+// or table of contents, lies in a table of contents, or includes a newline
+// that the document needs, such as the last one of a table cell.
+func (m *TextMap) CheckDeletable(start, end int) error {
+	i := sort.Search(len(m.Segments), func(i int) bool {
+		return m.Segments[i].TextEnd > start
+	})
+	if i == len(m.Segments) {
+		return nil
+	}
+
+	container := m.Segments[i].Container
+	for _, seg := range m.Segments[i:] {
+		if seg.TextStart >= end {
+			break
+		}
+		if seg.Container != container {
+			return fmt.Errorf("the text crosses the boundary of a table or table cell")
+		}
+	}
+
+	kind := m.containers[container]
+	if kind == "table of contents" {
+		return fmt.Errorf("the text is in a table of contents, which cannot be edited")
+	}
+
+	j := sort.SearchInts(m.fixed, start)
+	if j < len(m.fixed) && m.fixed[j] < end {
+		if m.fixed[j] == m.containerEnd(container) {
+			return fmt.Errorf("the text includes the final newline of the %s, which cannot be deleted", kind)
+		}
+		return fmt.Errorf("the text includes the newline before a table or section break, which cannot be deleted")
+	}
+	return nil
+}
+
+// containerEnd returns the byte offset of the last character of a container
+func (m *TextMap) containerEnd(container int) int {
+	for i := len(m.Segments) - 1; i >= 0; i-- {
+		if m.Segments[i].Container == container {
+			return m.Segments[i].TextEnd - 1
+		}
+	}
+	return -1
+}
+
+// locate returns the segment containing the byte offset. When atEnd is true the
+// offset is treated as an exclusive end, so a boundary between two segments
+// resolves to the earlier one.
+func (m *TextMap) locate(offset int, atEnd bool) (TextSegment, bool) {
+	i := sort.Search(len(m.Segments), func(i int) bool {
+		if atEnd {
+			return m.Segments[i].TextEnd >= offset
+		}
+		return m.Segments[i].TextEnd > offset
+	})
+	if i == len(m.Segments) {
+		return TextSegment{}, false
+	}
+	return m.Segments[i], true
+}
+
+// DocIndex converts a byte offset in Text to the Docs API index of that character
+func (m *TextMap) DocIndex(offset int) int64 {
+	if len(m.Segments) == 0 {
+		return 1
+	}
+	if offset >= len(m.Text) {
+		return m.Segments[len(m.Segments)-1].DocEnd
+	}
+
+	seg, ok := m.locate(offset, false)
+	if !ok {
+		return m.Segments[len(m.Segments)-1].DocEnd
+	}
+	return seg.DocStart + UTF16Length(m.Text[seg.TextStart:offset])
+}
+
+// DocEndIndex converts an exclusive end byte offset in Text to a Docs API index
+func (m *TextMap) DocEndIndex(offset int) int64 {
+	if len(m.Segments) == 0 {
+		return 1
+	}
+
+	seg, ok := m.locate(offset, true)
+	if !ok {
+		return m.Segments[len(m.Segments)-1].DocEnd
+	}
+	if offset < seg.TextStart {
+		return seg.DocStart
+	}
+	return seg.DocStart + UTF16Length(m.Text[seg.TextStart:offset])
+}
+
+// DocRange converts a byte range in Text to a Docs API index range
+func (m *TextMap) DocRange(start, end int) (int64, int64) {
+	return m.DocIndex(start), m.DocEndIndex(end)
+}
+
+// TextOffset converts a Docs API index to a byte offset in Text. Indices that
+// fall on structural markers resolve to the next character of text.
+func (m *TextMap) TextOffset(index int64) int {
+	i := sort.Search(len(m.Segments), func(i int) bool {
+		return m.Segments[i].DocEnd > index
+	})
+	if i == len(m.Segments) {
+		return len(m.Text)
+	}
+
+	seg := m.Segments[i]
+	if index <= seg.DocStart {
+		return seg.TextStart
+	}
+	return seg.TextStart + utf16PrefixBytes(m.Text[seg.TextStart:seg.TextEnd], index-seg.DocStart)
+}
+
+// Slice returns the text between two Docs API indices
+func (m *TextMap) Slice(startIndex, endIndex int64) string {
+	start, end := m.TextOffset(startIndex), m.TextOffset(endIndex)
+	if start >= end {
+		return ""
+	}
+	return m.Text[start:end]
+}
+
+// StartIndex returns the Docs API index of the first character of text
+func (m *TextMap) StartIndex() int64 {
+	if len(m.Segments) == 0 {
+		return 1
+	}
+	return m.Segments[0].DocStart
+}
+
+// EndIndex returns the Docs API index just after the last character of text
+func (m *TextMap) EndIndex() int64 {
+	if len(m.Segments) == 0 {
+		return 1
+	}
+	return m.Segments[len(m.Segments)-1].DocEnd
+}
+
+// TextMatch is an occurrence of a search string, in both text and document coordinates
+type TextMatch struct {
+	TextStart  int
+	TextEnd    int
+	StartIndex int64
+	EndIndex   int64
+	Submatches []int // Byte offsets in TextMap.Text of the match and its capture groups, as returned by regexp
+}
+
+// FindAll returns every non-overlapping occurrence of a literal string
+func (m *TextMap) FindAll(find string, matchCase bool) []TextMatch {
+	if find == "" {
+		return nil
+	}
+
+	pattern := regexp.QuoteMeta(find)
+	if !matchCase {
+		pattern = "(?i)" + pattern
+	}
+
+	return m.FindAllRegexp(regexp.MustCompile(pattern))
+}
+
+// FindAllRegexp returns every non-empty match of a regular expression
+func (m *TextMap) FindAllRegexp(re *regexp.Regexp) []TextMatch {
+	var matches []TextMatch
+	for _, loc := range re.FindAllStringSubmatchIndex(m.Text, -1) {
+		if loc[0] == loc[1] {
+			continue
+		}
+		startIndex, endIndex := m.DocRange(loc[0], loc[1])
+		matches = append(matches, TextMatch{
+			TextStart:  loc[0],
+			TextEnd:    loc[1],
+			StartIndex: startIndex,
+			EndIndex:   endIndex,
+			Submatches: loc,
+		})
+	}
+	return matches
+}
+
+// TextStyleAt returns the style of the text run containing the index, looking
+// inside tables, or nil when no text run contains it
+func TextStyleAt(elements []*docs.StructuralElement, index int64) *docs.TextStyle {
+	for _, element := range elements {
+		if index < element.StartIndex || index >= element.EndIndex {
+			continue
+		}
+		switch {
+		case element.Paragraph != nil:
+			for _, pe := range element.Paragraph.Elements {
+				if pe.TextRun != nil && pe.StartIndex <= index && index < pe.EndIndex {
+					return pe.TextRun.TextStyle
+				}
+			}
+		case element.Table != nil:
+			for _, row := range element.Table.TableRows {
+				for _, cell := range row.TableCells {
+					if style := TextStyleAt(cell.Content, index); style != nil {
+						return style
+					}
+				}
+			}
+		case element.TableOfContents != nil:
+			return TextStyleAt(element.TableOfContents.Content, index)
+		}
+	}
+	return nil
+}
+
+// BodyEndIndex returns the end index of the document body. Text can be inserted
+// at BodyEndIndex-1, before the final newline.
+func BodyEndIndex(doc *docs.Document) int64 {
+	if doc == nil || doc.Body == nil || len(doc.Body.Content) == 0 {
+		return 1
+	}
+	return doc.Body.Content[len(doc.Body.Content)-1].EndIndex
+}
+
+// SortedSegmentIDs returns the IDs of a document's headers, footers or
+// footnotes in sorted order, so the segments are always visited in the same
+// order
+func SortedSegmentIDs[V any](segments map[string]V) []string {
+	ids := make([]string, 0, len(segments))
+	for id := range segments {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// UTF16Length returns the length of a string in UTF-16 code units, which is
+// how the Docs API measures indices
+func UTF16Length(s string) int64 {
+	return int64(len(utf16.Encode([]rune(s))))
+}
+
+// utf16PrefixBytes returns the number of bytes of s that make up the first
+// units UTF-16 code units
+func utf16PrefixBytes(s string, units int64) int {
+	count := int64(0)
+	for i, r := range s {
+		if count >= units {
+			return i
+		}
+		if r >= 0x10000 {
+			count += 2
+		} else {
+			count++
+		}
+	}
+	return len(s)
+}
@@ -0,0 +1,236 @@
+package util
+
+import (
+	"regexp"
+	"testing"
+
+	"google.golang.org/api/docs/v1"
+)
+
+// testBlock builds a structural element starting at the given index
+type testBlock func(start int64) *docs.StructuralElement
+
+// para builds a paragraph of text runs laid out one after another. The last
+// run should end with the paragraph's newline.
+func para(runs ...string) testBlock {
+	return func(start int64) *docs.StructuralElement {
+		paragraph := &docs.Paragraph{ParagraphStyle: &docs.ParagraphStyle{NamedStyleType: "NORMAL_TEXT"}}
+		index := start
+		for _, run := range runs {
+			end := index + UTF16Length(run)
+			paragraph.Elements = append(paragraph.Elements, &docs.ParagraphElement{
+				StartIndex: index,
+				EndIndex:   end,
+				TextRun:    &docs.TextRun{Content: run, TextStyle: &docs.TextStyle{}},
+			})
+			index = end
+		}
+		return &docs.StructuralElement{StartIndex: start, EndIndex: index, Paragraph: paragraph}
+	}
+}
+
+// table builds a table whose cells hold one paragraph of text each
+func table(rows ...[]string) testBlock {
+	return func(start int64) *docs.StructuralElement {
+		t := &docs.Table{Rows: int64(len(rows))}
+		index := start + 1
+		for _, cells := range rows {
+			t.Columns = max(t.Columns, int64(len(cells)))
+			row := &docs.TableRow{StartIndex: index}
+			index++
+			for _, text := range cells {
+				cell := &docs.TableCell{StartIndex: index}
+				cell.Content = layoutElements(index+1, para(text+"\n"))
+				index = cell.Content[len(cell.Content)-1].EndIndex
+				cell.EndIndex = index
+				row.TableCells = append(row.TableCells, cell)
+			}
+			row.EndIndex = index
+			t.TableRows = append(t.TableRows, row)
+		}
+		return &docs.StructuralElement{StartIndex: start, EndIndex: index + 1, Table: t}
+	}
+}
+
+// layoutElements builds blocks one after another from start
+func layoutElements(start int64, blocks ...testBlock) []*docs.StructuralElement {
+	var elements []*docs.StructuralElement
+	for _, block := range blocks {
+		element := block(start)
+		elements = append(elements, element)
+		start = element.EndIndex
+	}
+	return elements
+}
+
+// testDocument builds a document whose body starts with a section break
+// followed by the blocks
+func testDocument(blocks ...testBlock) *docs.Document {
+	content := []*docs.StructuralElement{{EndIndex: 1, SectionBreak: &docs.SectionBreak{}}}
+	return &docs.Document{
+		DocumentId: "doc",
+		Body:       &docs.Body{Content: append(content, layoutElements(1, blocks...)...)},
+	}
+}
+
+func TestUTF16Length(t *testing.T) {
+	tests := []struct {
+		text string
+		want int64
+	}{
+		{"", 0},
+		{"abc", 3},
+		{"é", 1},
+		{"日本", 2},
+		{"😀", 2},
+		{"a😀b\n", 5},
+	}
+	for _, tt := range tests {
+		if got := UTF16Length(tt.text); got != tt.want {
+			t.Errorf("UTF16Length(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestTextMapIndices(t *testing.T) {
+	// "a😀b\n" at 1-6, then a paragraph with an inline object at 8 between its
+	// two runs: "é" at 7 and "x\n" at 9-11
+	doc := testDocument(para("a😀b\n"))
+	doc.Body.Content = append(doc.Body.Content, &docs.StructuralElement{
+		StartIndex: 6,
+		EndIndex:   10,
+		Paragraph: &docs.Paragraph{Elements: []*docs.ParagraphElement{
+			{StartIndex: 6, EndIndex: 7, TextRun: &docs.TextRun{Content: "é"}},
+			{StartIndex: 7, EndIndex: 8, InlineObjectElement: &docs.InlineObjectElement{}},
+			{StartIndex: 8, EndIndex: 10, TextRun: &docs.TextRun{Content: "x\n"}},
+		}},
+	})
+	m := NewTextMap(doc)
+
+	if want := "a😀b\néx\n"; m.Text != want {
+		t.Fatalf("Text = %q, want %q", m.Text, want)
+	}
+
+	tests := []struct {
+		offset   int
+		index    int64
+		endIndex int64
+	}{
+		{0, 1, 1},    // a
+		{1, 2, 2},    // 😀, four bytes and two code units
+		{5, 4, 4},    // b
+		{6, 5, 5},    // newline
+		{7, 6, 6},    // é, two bytes
+		{9, 8, 7},    // x, after the inline object; as an end it stays before it
+		{10, 9, 9},   // newline
+		{11, 10, 10}, // end of text
+	}
+	for _, tt := range tests {
+		if got := m.DocIndex(tt.offset); got != tt.index {
+			t.Errorf("DocIndex(%d) = %d, want %d", tt.offset, got, tt.index)
+		}
+		if got := m.DocEndIndex(tt.offset); got != tt.endIndex {
+			t.Errorf("DocEndIndex(%d) = %d, want %d", tt.offset, got, tt.endIndex)
+		}
+	}
+
+	if got := m.TextOffset(3); got != 5 {
+		t.Errorf("TextOffset(3) inside a surrogate pair = %d, want 5", got)
+	}
+	if got := m.TextOffset(7); got != 9 {
+		t.Errorf("TextOffset(7) on the inline object = %d, want 9", got)
+	}
+	if got := m.Slice(2, 5); got != "😀b" {
+		t.Errorf("Slice(2, 5) = %q, want %q", got, "😀b")
+	}
+}
+
+func TestFindAllRegexpIndices(t *testing.T) {
+	doc := testDocument(para("😀 café\n"), para("Café 日本\n"))
+	m := NewTextMap(doc)
+
+	matches := m.FindAll("café", false)
+	if len(matches) != 2 {
+		t.Fatalf("found %d matches, want 2", len(matches))
+	}
+	// "😀 " takes 3 code units, so the first café is at 4-8; the second
+	// paragraph starts at 9
+	want := [][2]int64{{4, 8}, {9, 13}}
+	for i, match := range matches {
+		if match.StartIndex != want[i][0] || match.EndIndex != want[i][1] {
+			t.Errorf("match %d = %d-%d, want %d-%d", i, match.StartIndex, match.EndIndex, want[i][0], want[i][1])
+		}
+	}
+
+	matches = m.FindAllRegexp(regexp.MustCompile(`日本`))
+	if len(matches) != 1 || matches[0].StartIndex != 14 || matches[0].EndIndex != 16 {
+		t.Errorf("日本 matched at %+v, want 14-16", matches)
+	}
+}
+
+func TestTextMapTables(t *testing.T) {
+	// "ab\n" 1-4, table 4-13 with cell paragraphs "x\n" at 7 and "y\n" at 10,
+	// then "cd\n" 13-16
+	doc := testDocument(para("ab\n"), table([]string{"x", "y"}), para("cd\n"))
+	m := NewTextMap(doc)
+
+	if want := "ab\nx\ny\ncd\n"; m.Text != want {
+		t.Fatalf("Text = %q, want %q", m.Text, want)
+	}
+	for find, want := range map[string]int64{"x": 7, "y": 10, "cd": 13} {
+		matches := m.FindAll(find, true)
+		if len(matches) != 1 || matches[0].StartIndex != want {
+			t.Errorf("%q matched at %+v, want start %d", find, matches, want)
+		}
+	}
+}
+
+func TestCheckDeletable(t *testing.T) {
+	doc := testDocument(para("ab\n"), table([]string{"x", "y"}), para("cd\n"), para("e\n"))
+	m := NewTextMapFromElements(doc.Body.Content)
+
+	tests := []struct {
+		pattern string
+		ok      bool
+	}{
+		{`b`, true},
+		{`x`, true},
+		{`cd\n`, true},
+		{`cd\ne`, true},
+		{`b\n`, false},   // Newline before the table
+		{`x\n`, false},   // Final newline of a cell
+		{`\ny`, false},   // Crosses two cells
+		{`b\nx`, false},  // Runs into the table
+		{`e\n`, false},   // Final newline of the body
+		{`y\ncd`, false}, // Runs out of the table
+	}
+	for _, tt := range tests {
+		matches := m.FindAllRegexp(regexp.MustCompile(tt.pattern))
+		if len(matches) != 1 {
+			t.Fatalf("%s: found %d matches, want 1", tt.pattern, len(matches))
+		}
+		err := m.CheckDeletable(matches[0].TextStart, matches[0].TextEnd)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: CheckDeletable error = %v, want ok %t", tt.pattern, err, tt.ok)
+		}
+	}
+}
+
+func TestTextStyleAt(t *testing.T) {
+	doc := testDocument(para("plain ", "bold\n"), table([]string{"cell"}))
+	bold := &docs.TextStyle{Bold: true}
+	doc.Body.Content[1].Paragraph.Elements[1].TextRun.TextStyle = bold
+	cellStyle := &docs.TextStyle{Italic: true}
+	cell := doc.Body.Content[2].Table.TableRows[0].TableCells[0]
+	cell.Content[0].Paragraph.Elements[0].TextRun.TextStyle = cellStyle
+
+	if got := TextStyleAt(doc.Body.Content, 7); got != bold {
+		t.Errorf("TextStyleAt(7) = %+v, want the bold style", got)
+	}
+	if got := TextStyleAt(doc.Body.Content, cell.Content[0].StartIndex); got != cellStyle {
+		t.Errorf("TextStyleAt in cell = %+v, want the cell style", got)
+	}
+	if got := TextStyleAt(doc.Body.Content, 100); got != nil {
+		t.Errorf("TextStyleAt(100) = %+v, want nil", got)
+	}
+}
@@ -0,0 +1,434 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/docs/v1"
+)
+
+// headingPrefixes maps Google Docs named styles to Markdown heading markers
+var headingPrefixes = map[string]string{
+	"TITLE":     "#",
+	"SUBTITLE":  "##",
+	"HEADING_1": "#",
+	"HEADING_2": "##",
+	"HEADING_3": "###",
+	"HEADING_4": "####",
+	"HEADING_5": "#####",
+	"HEADING_6": "######",
+}
+
+// monospaceFonts lists font families that are rendered as inline code
+var monospaceFonts = map[string]bool{
+	"Courier New":     true,
+	"Consolas":        true,
+	"Roboto Mono":     true,
+	"Source Code Pro": true,
+	"Inconsolata":     true,
+	"Ubuntu Mono":     true,
+	"Fira Code":       true,
+	"JetBrains Mono":  true,
+}
+
+// markdownRenderer holds the state needed while converting a document to Markdown
+type markdownRenderer struct {
+	doc           *docs.Document
+	listCounters  map[string]map[int64]int
+	footnoteOrder []string
+}
+
+// ConvertToMarkdown converts the body of a Google Docs document to GitHub-flavored Markdown
+func ConvertToMarkdown(doc *docs.Document) string {
+	if doc == nil || doc.Body == nil {
+		return ""
+	}
+
+	return ConvertElementsToMarkdown(doc, doc.Body.Content)
+}
+
+// ConvertElementsToMarkdown converts a slice of structural elements to Markdown.
+// The document is used to resolve lists, inline objects and footnotes.
+func ConvertElementsToMarkdown(doc *docs.Document, elements []*docs.StructuralElement) string {
+	r := &markdownRenderer{
+		doc:          doc,
+		listCounters: make(map[string]map[int64]int),
+	}
+
+	var sb strings.Builder
+	r.renderElements(elements, &sb)
+	r.renderFootnotes(&sb)
+
+	return strings.TrimRight(sb.String(), "\n") + "\n"
+}
+
+// renderElements renders block-level elements, separating blocks with blank lines
+// and keeping consecutive list items together
+func (r *markdownRenderer) renderElements(elements []*docs.StructuralElement, sb *strings.Builder) {
+	prevWasList := false
+
+	for _, element := range elements {
+		switch {
+		case element.Paragraph != nil:
+			text := r.renderParagraph(element.Paragraph)
+			if text == "" {
+				continue
+			}
+
+			isList := element.Paragraph.Bullet != nil
+			if sb.Len() > 0 {
+				if isList && prevWasList {
+					sb.WriteString("\n")
+				} else {
+					sb.WriteString("\n\n")
+				}
+			}
+			sb.WriteString(text)
+			prevWasList = isList
+
+		case element.Table != nil:
+			text := r.renderTable(element.Table)
+			if text == "" {
+				continue
+			}
+			if sb.Len() > 0 {
+				sb.WriteString("\n\n")
+			}
+			sb.WriteString(text)
+			prevWasList = false
+		}
+	}
+}
+
+// renderParagraph renders a single paragraph including heading or list prefix
+func (r *markdownRenderer) renderParagraph(paragraph *docs.Paragraph) string {
+	text := r.renderInline(paragraph.Elements)
+	if strings.TrimSpace(text) == "" {
+		return ""
+	}
+
+	if paragraph.Bullet != nil {
+		return r.listPrefix(paragraph.Bullet) + text
+	}
+
+	if paragraph.ParagraphStyle != nil {
+		if prefix, ok := headingPrefixes[paragraph.ParagraphStyle.NamedStyleType]; ok {
+			return prefix + " " + text
+		}
+	}
+
+	return text
+}
+
+// listPrefix returns the indentation and marker for a list item, tracking
+// numbering per list and nesting level
+func (r *markdownRenderer) listPrefix(bullet *docs.Bullet) string {
+	level := bullet.NestingLevel
+	indent := strings.Repeat("    ", int(level))
+
+	if !r.isOrderedList(bullet.ListId, level) {
+		return indent + "- "
+	}
+
+	counters, ok := r.listCounters[bullet.ListId]
+	if !ok {
+		counters = make(map[int64]int)
+		r.listCounters[bullet.ListId] = counters
+	}
+
+	// Restart numbering of deeper levels when a shallower item appears
+	for l := range counters {
+		if l > level {
+			delete(counters, l)
+		}
+	}
+	counters[level]++
+
+	return fmt.Sprintf("%s%d. ", indent, counters[level])
+}
+
+// isOrderedList reports whether the given list nesting level uses a numbered glyph
+func (r *markdownRenderer) isOrderedList(listID string, level int64) bool {
+	if r.doc == nil || r.doc.Lists == nil {
+		return false
+	}
+
+	list, ok := r.doc.Lists[listID]
+	if !ok || list.ListProperties == nil || int(level) >= len(list.ListProperties.NestingLevels) {
+		return false
+	}
+
+	nesting := list.ListProperties.NestingLevels[level]
+	switch nesting.GlyphType {
+	case "", "GLYPH_TYPE_UNSPECIFIED", "NONE":
+		return false
+	default:
+		return true
+	}
+}
+
+// renderInline renders paragraph elements as inline Markdown, merging adjacent
+// text runs that share the same formatting
+func (r *markdownRenderer) renderInline(elements []*docs.ParagraphElement) string {
+	var sb strings.Builder
+
+	var pending strings.Builder
+	var pendingStyle inlineStyle
+	flush := func() {
+		if pending.Len() > 0 {
+			sb.WriteString(pendingStyle.wrap(pending.String()))
+			pending.Reset()
+		}
+	}
+
+	for _, element := range elements {
+		switch {
+		case element.TextRun != nil:
+			content := strings.TrimSuffix(element.TextRun.Content, "\n")
+			content = strings.ReplaceAll(content, "\v", "\n")
+			if content == "" {
+				continue
+			}
+			style := newInlineStyle(element.TextRun.TextStyle)
+			if style != pendingStyle {
+				flush()
+				pendingStyle = style
+			}
+			pending.WriteString(content)
+
+		case element.InlineObjectElement != nil:
+			flush()
+			sb.WriteString(r.renderInlineObject(element.InlineObjectElement.InlineObjectId))
+
+		case element.FootnoteReference != nil:
+			flush()
+			sb.WriteString(r.footnoteMarker(element.FootnoteReference.FootnoteId))
+
+		case element.HorizontalRule != nil:
+			flush()
+			sb.WriteString("---")
+
+		case element.RichLink != nil && element.RichLink.RichLinkProperties != nil:
+			flush()
+			props := element.RichLink.RichLinkProperties
+			sb.WriteString(fmt.Sprintf("[%s](%s)", escapeMarkdown(props.Title), props.Uri))
+
+		case element.Person != nil && element.Person.PersonProperties != nil:
+			flush()
+			sb.WriteString(escapeMarkdown(element.Person.PersonProperties.Name))
+		}
+	}
+	flush()
+
+	return sb.String()
+}
+
+// renderInlineObject renders an embedded image as a Markdown image reference
+func (r *markdownRenderer) renderInlineObject(objectID string) string {
+	if r.doc == nil || r.doc.InlineObjects == nil {
+		return ""
+	}
+
+	object, ok := r.doc.InlineObjects[objectID]
+	if !ok || object.InlineObjectProperties == nil || object.InlineObjectProperties.EmbeddedObject == nil {
+		return ""
+	}
+
+	embedded := object.InlineObjectProperties.EmbeddedObject
+	if embedded.ImageProperties == nil {
+		return ""
+	}
+
+	alt := embedded.Title
+	if alt == "" {
+		alt = embedded.Description
+	}
+
+	return fmt.Sprintf("![%s](%s)", escapeMarkdown(alt), embedded.ImageProperties.ContentUri)
+}
+
+// footnoteMarker returns the Markdown footnote reference for a footnote ID
+func (r *markdownRenderer) footnoteMarker(footnoteID string) string {
+	for i, id := range r.footnoteOrder {
+		if id == footnoteID {
+			return fmt.Sprintf("[^%d]", i+1)
+		}
+	}
+	r.footnoteOrder = append(r.footnoteOrder, footnoteID)
+	return fmt.Sprintf("[^%d]", len(r.footnoteOrder))
+}
+
+// renderFootnotes appends the definitions of all referenced footnotes
+func (r *markdownRenderer) renderFootnotes(sb *strings.Builder) {
+	if r.doc == nil || len(r.footnoteOrder) == 0 {
+		return
+	}
+
+	sb.WriteString("\n")
+	// Footnote bodies may reference further footnotes, so iterate by index
+	for i := 0; i < len(r.footnoteOrder); i++ {
+		footnote, ok := r.doc.Footnotes[r.footnoteOrder[i]]
+		if !ok {
+			continue
+		}
+
+		var parts []string
+		for _, element := range footnote.Content {
+			if element.Paragraph != nil {
+				if text := strings.TrimSpace(r.renderInline(element.Paragraph.Elements)); text != "" {
+					parts = append(parts, text)
+				}
+			}
+		}
+		sb.WriteString(fmt.Sprintf("\n[^%d]: %s", i+1, strings.Join(parts, " ")))
+	}
+}
+
+// renderTable renders a table as a GFM table, using the first row as the header
+func (r *markdownRenderer) renderTable(table *docs.Table) string {
+	if len(table.TableRows) == 0 {
+		return ""
+	}
+
+	columns := int(table.Columns)
+	for _, row := range table.TableRows {
+		if len(row.TableCells) > columns {
+			columns = len(row.TableCells)
+		}
+	}
+	if columns == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	for i, row := range table.TableRows {
+		sb.WriteString("|")
+		for c := 0; c < columns; c++ {
+			cellText := ""
+			if c < len(row.TableCells) {
+				cellText = r.renderTableCell(row.TableCells[c])
+			}
+			sb.WriteString(" " + cellText + " |")
+		}
+		sb.WriteString("\n")
+
+		if i == 0 {
+			sb.WriteString("|" + strings.Repeat(" --- |", columns) + "\n")
+		}
+	}
+
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// renderTableCell renders the content of a table cell on a single line
+func (r *markdownRenderer) renderTableCell(cell *docs.TableCell) string {
+	var parts []string
+
+	for _, element := range cell.Content {
+		switch {
+		case element.Paragraph != nil:
+			text := r.renderParagraph(element.Paragraph)
+			if text != "" {
+				parts = append(parts, text)
+			}
+		case element.Table != nil:
+			// GFM cannot nest tables, so flatten nested table rows
+			for _, row := range element.Table.TableRows {
+				var cells []string
+				for _, nested := range row.TableCells {
+					cells = append(cells, r.renderTableCell(nested))
+				}
+				parts = append(parts, strings.Join(cells, " / "))
+			}
+		}
+	}
+
+	text := strings.Join(parts, "<br>")
+	text = strings.ReplaceAll(text, "\n", "<br>")
+	return strings.ReplaceAll(text, "|", "\\|")
+}
+
+// inlineStyle captures the text style attributes that have a Markdown representation
+type inlineStyle struct {
+	bold          bool
+	italic        bool
+	strikethrough bool
+	code          bool
+	link          string
+}
+
+// newInlineStyle extracts the Markdown-relevant attributes of a text style
+func newInlineStyle(style *docs.TextStyle) inlineStyle {
+	if style == nil {
+		return inlineStyle{}
+	}
+
+	s := inlineStyle{
+		bold:          style.Bold,
+		italic:        style.Italic,
+		strikethrough: style.Strikethrough,
+	}
+
+	if style.WeightedFontFamily != nil && monospaceFonts[style.WeightedFontFamily.FontFamily] {
+		s.code = true
+	}
+
+	if style.Link != nil {
+		switch {
+		case style.Link.Url != "":
+			s.link = style.Link.Url
+		case style.Link.HeadingId != "":
+			s.link = "#" + style.Link.HeadingId
+		case style.Link.BookmarkId != "":
+			s.link = "#" + style.Link.BookmarkId
+		}
+	}
+
+	return s
+}
+
+// wrap applies the Markdown markers for the style around the text. Surrounding
+// whitespace is kept outside the markers so the result stays valid Markdown.
+func (s inlineStyle) wrap(text string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+	start := strings.Index(text, trimmed)
+	leading, trailing := text[:start], text[start+len(trimmed):]
+
+	if s.code {
+		trimmed = "`" + trimmed + "`"
+	} else {
+		trimmed = escapeMarkdown(trimmed)
+	}
+	if s.strikethrough {
+		trimmed = "~~" + trimmed + "~~"
+	}
+	if s.italic {
+		trimmed = "_" + trimmed + "_"
+	}
+	if s.bold {
+		trimmed = "**" + trimmed + "**"
+	}
+	if s.link != "" {
+		trimmed = fmt.Sprintf("[%s](%s)", trimmed, s.link)
+	}
+
+	return leading + trimmed + trailing
+}
+
+// markdownEscaper escapes characters that would otherwise be read as Markdown syntax
+var markdownEscaper = strings.NewReplacer(
+	"\\", "\\\\",
+	"*", "\\*",
+	"_", "\\_",
+	"`", "\\`",
+	"[", "\\[",
+	"]", "\\]",
+)
+
+// escapeMarkdown escapes Markdown control characters in plain text
+func escapeMarkdown(text string) string {
+	return markdownEscaper.Replace(text)
+}
@@ -0,0 +1,644 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+	"google.golang.org/api/docs/v1"
+)
+
+// codeFontFamily is the font applied to inline code and code blocks
+const codeFontFamily = "Courier New"
+
+// MarkdownBatch holds the Docs API requests compiled from a Markdown string
+type MarkdownBatch struct {
+	Requests   []*docs.Request
+	StartIndex int64 // Index where the content was inserted
+	EndIndex   int64 // Index just after the inserted content once all requests are applied
+	Paragraphs int
+	Tables     int
+	Images     int
+}
+
+// markdownRun is a piece of inline text with its Markdown formatting
+type markdownRun struct {
+	text          string
+	bold          bool
+	italic        bool
+	strikethrough bool
+	code          bool
+	link          string
+	image         string // Image URL; text is ignored for image runs
+}
+
+// bulletGroup is a range of paragraphs that form one list in the document
+type bulletGroup struct {
+	startIndex int64
+	endIndex   int64
+	preset     string
+	tabs       int64
+}
+
+// markdownCompiler walks a Markdown AST and builds Docs API requests. Content is
+// inserted front to back so every recorded index is the final index of the text.
+type markdownCompiler struct {
+	source []byte
+
+	cursor       int64
+	segmentStart int64
+	segment      strings.Builder
+
+	// contentStart is where the paragraphs of the content begin, after the
+	// newline of a leading table that ends the paragraph before it
+	contentStart int64
+
+	inserts     []*docs.Request
+	resets      []*docs.Request
+	styles      []*docs.Request
+	textStyles  []*docs.Request
+	bullets     []bulletGroup
+	activeGroup *bulletGroup
+
+	batch *MarkdownBatch
+}
+
+// CompileMarkdown parses CommonMark with GFM extensions and compiles it into a
+// sequence of requests that insert the content at the given index as native
+// Docs structure (headings, lists, tables, links and text styles). With
+// fillParagraph, index is the start of the empty paragraph that ends a
+// segment, and the last paragraph of the content takes it over instead of
+// leaving it empty after the content.
+func CompileMarkdown(markdown string, index int64, fillParagraph bool) (*MarkdownBatch, error) {
+	if index < 1 {
+		return nil, fmt.Errorf("insertion index must be at least 1")
+	}
+
+	source := []byte(markdown)
+	parser := goldmark.New(goldmark.WithExtensions(extension.GFM)).Parser()
+	root := parser.Parse(text.NewReader(source))
+
+	c := &markdownCompiler{
+		source:       source,
+		cursor:       index,
+		segmentStart: index,
+		contentStart: index,
+		batch:        &MarkdownBatch{StartIndex: index},
+	}
+
+	for node := root.FirstChild(); node != nil; node = node.NextSibling() {
+		if err := c.compileBlock(node, 0, false); err != nil {
+			return nil, err
+		}
+	}
+	bulletsEnd := c.finishContent(fillParagraph)
+	c.flushSegment()
+
+	requests := append([]*docs.Request{}, c.inserts...)
+	requests = append(requests, c.resets...)
+	requests = append(requests, c.clearBullets(bulletsEnd)...)
+	requests = append(requests, c.styles...)
+	requests = append(requests, c.textStyles...)
+
+	// Creating bullets removes the leading tabs used for nesting, which shifts
+	// everything after the list. Applying the lists last to first keeps the
+	// ranges of the remaining lists valid.
+	removedTabs := int64(0)
+	for i := len(c.bullets) - 1; i >= 0; i-- {
+		group := c.bullets[i]
+		requests = append(requests, &docs.Request{
+			CreateParagraphBullets: &docs.CreateParagraphBulletsRequest{
+				Range: &docs.Range{
+					StartIndex: group.startIndex,
+					EndIndex:   group.endIndex,
+				},
+				BulletPreset: group.preset,
+			},
+		})
+		removedTabs += group.tabs
+	}
+
+	c.batch.Requests = requests
+	c.batch.EndIndex = c.cursor - removedTabs
+
+	return c.batch, nil
+}
+
+// CompilePlainText compiles text into requests that insert it at the given
+// index as normal paragraphs, one per line, without any formatting.
+// fillParagraph is as for CompileMarkdown.
+func CompilePlainText(text string, index int64, fillParagraph bool) (*MarkdownBatch, error) {
+	if index < 1 {
+		return nil, fmt.Errorf("insertion index must be at least 1")
+	}
+
+	c := &markdownCompiler{
+		cursor:       index,
+		segmentStart: index,
+		contentStart: index,
+		batch:        &MarkdownBatch{StartIndex: index},
+	}
+
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if text != "" {
+		for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+			c.writeParagraph([]markdownRun{{text: line}}, "NORMAL_TEXT", 0, false, false)
+		}
+	}
+	bulletsEnd := c.finishContent(fillParagraph)
+	c.flushSegment()
+
+	requests := append([]*docs.Request{}, c.inserts...)
+	requests = append(requests, c.resets...)
+	requests = append(requests, c.clearBullets(bulletsEnd)...)
+	requests = append(requests, c.styles...)
+
+	c.batch.Requests = requests
+	c.batch.EndIndex = c.cursor
+
+	return c.batch, nil
+}
+
+// finishContent ends the content before its last segment is flushed and
+// returns the end of the paragraphs it occupies. With fillParagraph, the last
+// paragraph of the content ends with the newline of the empty paragraph it was
+// inserted into; after a trailing table, that paragraph stays and is made a
+// plain paragraph again.
+func (c *markdownCompiler) finishContent(fillParagraph bool) int64 {
+	if !fillParagraph || c.cursor == c.batch.StartIndex {
+		return c.cursor
+	}
+
+	if pending := c.segment.String(); strings.HasSuffix(pending, "\n") {
+		c.segment.Reset()
+		c.segment.WriteString(strings.TrimSuffix(pending, "\n"))
+		c.cursor--
+		return c.cursor + 1
+	}
+
+	c.styles = append(c.styles, PlainParagraphRequests(c.cursor)...)
+	return c.cursor
+}
+
+// clearBullets removes the bullets the inserted paragraphs inherit from the
+// paragraph they were inserted into. The lists of the content are created
+// afterwards.
+func (c *markdownCompiler) clearBullets(end int64) []*docs.Request {
+	if end <= c.contentStart {
+		return nil
+	}
+	return []*docs.Request{
+		{
+			DeleteParagraphBullets: &docs.DeleteParagraphBulletsRequest{
+				Range: &docs.Range{StartIndex: c.contentStart, EndIndex: end},
+			},
+		},
+	}
+}
+
+// PlainParagraphRequests returns the requests that make the paragraph at index
+// a normal paragraph without a bullet, such as the empty paragraph left at the
+// end of a segment after its content was replaced
+func PlainParagraphRequests(index int64) []*docs.Request {
+	return []*docs.Request{
+		{
+			DeleteParagraphBullets: &docs.DeleteParagraphBulletsRequest{
+				Range: &docs.Range{StartIndex: index, EndIndex: index + 1},
+			},
+		},
+		{
+			UpdateParagraphStyle: &docs.UpdateParagraphStyleRequest{
+				Range:          &docs.Range{StartIndex: index, EndIndex: index + 1},
+				ParagraphStyle: &docs.ParagraphStyle{NamedStyleType: "NORMAL_TEXT"},
+				Fields:         "namedStyleType,indentStart,indentFirstLine",
+			},
+		},
+	}
+}
+
+// compileBlock compiles a block-level node. Level is the list nesting level.
+func (c *markdownCompiler) compileBlock(node gast.Node, level int, inList bool) error {
+	switch n := node.(type) {
+	case *gast.Heading:
+		style := fmt.Sprintf("HEADING_%d", n.Level)
+		c.writeParagraph(c.collectInline(n, markdownRun{}), style, 0, false, false)
+
+	case *gast.Paragraph, *gast.TextBlock:
+		c.writeParagraph(c.collectInline(n, markdownRun{}), "NORMAL_TEXT", level, inList, false)
+
+	case *gast.Blockquote:
+		for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+			if child.Kind() == gast.KindParagraph {
+				c.writeParagraph(c.collectInline(child, markdownRun{}), "NORMAL_TEXT", level, inList, true)
+				continue
+			}
+			if err := c.compileBlock(child, level, inList); err != nil {
+				return err
+			}
+		}
+
+	case *gast.FencedCodeBlock, *gast.CodeBlock:
+		lines := n.Lines()
+		for i := 0; i < lines.Len(); i++ {
+			segment := lines.At(i)
+			line := strings.TrimRight(string(segment.Value(c.source)), "\r\n")
+			c.writeParagraph([]markdownRun{{text: line, code: true}}, "NORMAL_TEXT", level, inList, false)
+		}
+
+	case *gast.ThematicBreak:
+		// The Docs API cannot insert horizontal rules, so use the same
+		// centered line as insert_horizontal_rule
+		start := c.cursor
+		c.writeParagraph([]markdownRun{{text: "___"}}, "NORMAL_TEXT", 0, false, false)
+		c.styles = append(c.styles, &docs.Request{
+			UpdateParagraphStyle: &docs.UpdateParagraphStyleRequest{
+				Range:          &docs.Range{StartIndex: start, EndIndex: c.cursor},
+				ParagraphStyle: &docs.ParagraphStyle{Alignment: "CENTER"},
+				Fields:         "alignment",
+			},
+		})
+
+	case *gast.List:
+		return c.compileList(n, level)
+
+	case *east.Table:
+		c.compileTable(n)
+
+	case *gast.HTMLBlock:
+		// Raw HTML has no Docs equivalent and is skipped
+
+	default:
+		for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+			if err := c.compileBlock(child, level, inList); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// compileList compiles a list and its nested lists into one bullet group
+func (c *markdownCompiler) compileList(list *gast.List, level int) error {
+	outermost := c.activeGroup == nil
+	if outermost {
+		preset := "BULLET_DISC_CIRCLE_SQUARE"
+		if list.IsOrdered() {
+			preset = "NUMBERED_DECIMAL_ALPHA_ROMAN"
+		} else if hasTaskCheckBox(list) {
+			preset = "BULLET_CHECKBOX"
+		}
+		c.activeGroup = &bulletGroup{startIndex: c.cursor, preset: preset}
+	}
+
+	for item := list.FirstChild(); item != nil; item = item.NextSibling() {
+		for child := item.FirstChild(); child != nil; child = child.NextSibling() {
+			childLevel := level
+			if child.Kind() == gast.KindList {
+				childLevel = level + 1
+			}
+			if err := c.compileBlock(child, childLevel, true); err != nil {
+				return err
+			}
+		}
+	}
+
+	if outermost {
+		c.activeGroup.endIndex = c.cursor
+		if c.activeGroup.endIndex > c.activeGroup.startIndex {
+			c.bullets = append(c.bullets, *c.activeGroup)
+		}
+		c.activeGroup = nil
+	}
+
+	return nil
+}
+
+// hasTaskCheckBox reports whether any item of the list is a GFM task item
+func hasTaskCheckBox(list *gast.List) bool {
+	found := false
+	gast.Walk(list, func(node gast.Node, entering bool) (gast.WalkStatus, error) {
+		if entering && node.Kind() == east.KindTaskCheckBox {
+			found = true
+			return gast.WalkStop, nil
+		}
+		return gast.WalkContinue, nil
+	})
+	return found
+}
+
+// writeParagraph appends a paragraph to the current text segment and records
+// its paragraph and text style requests
+func (c *markdownCompiler) writeParagraph(runs []markdownRun, namedStyle string, level int, inList, quote bool) {
+	start := c.cursor
+
+	if inList && level > 0 {
+		tabs := strings.Repeat("\t", level)
+		c.writeText(tabs)
+		c.activeGroup.tabs += int64(level)
+	}
+
+	c.writeRuns(runs)
+	c.writeText("\n")
+	c.batch.Paragraphs++
+
+	paragraphStyle := &docs.ParagraphStyle{NamedStyleType: namedStyle}
+	if quote {
+		paragraphStyle.IndentStart = &docs.Dimension{Magnitude: 36, Unit: "PT"}
+		paragraphStyle.IndentFirstLine = &docs.Dimension{Magnitude: 36, Unit: "PT"}
+	}
+
+	c.styles = append(c.styles, &docs.Request{
+		UpdateParagraphStyle: &docs.UpdateParagraphStyleRequest{
+			Range:          &docs.Range{StartIndex: start, EndIndex: c.cursor},
+			ParagraphStyle: paragraphStyle,
+			Fields:         "namedStyleType,indentStart,indentFirstLine",
+		},
+	})
+}
+
+// writeRuns appends inline runs at the cursor, inserting images as they occur
+func (c *markdownCompiler) writeRuns(runs []markdownRun) {
+	for _, run := range runs {
+		if run.image != "" {
+			c.flushSegment()
+			c.inserts = append(c.inserts, &docs.Request{
+				InsertInlineImage: &docs.InsertInlineImageRequest{
+					Location: &docs.Location{Index: c.cursor},
+					Uri:      run.image,
+				},
+			})
+			c.cursor++
+			c.segmentStart = c.cursor
+			c.batch.Images++
+			continue
+		}
+
+		start := c.cursor
+		c.writeText(run.text)
+		c.addTextStyle(run, start, c.cursor)
+	}
+}
+
+// writeText appends plain text to the pending segment and advances the cursor
+func (c *markdownCompiler) writeText(s string) {
+	c.segment.WriteString(s)
+	c.cursor += UTF16Length(s)
+}
+
+// flushSegment emits the InsertText request for the pending text segment
+func (c *markdownCompiler) flushSegment() {
+	if c.segment.Len() == 0 {
+		return
+	}
+
+	c.inserts = append(c.inserts, &docs.Request{
+		InsertText: &docs.InsertTextRequest{
+			Location: &docs.Location{Index: c.segmentStart},
+			Text:     c.segment.String(),
+		},
+	})
+	c.resets = append(c.resets, resetTextStyleRequest(c.segmentStart, c.cursor))
+
+	c.segment.Reset()
+	c.segmentStart = c.cursor
+}
+
+// compileTable inserts a table and fills its cells. Cells are filled last to
+// first so the precomputed empty-table indices stay valid.
+func (c *markdownCompiler) compileTable(table *east.Table) {
+	var rows [][][]markdownRun
+	for row := table.FirstChild(); row != nil; row = row.NextSibling() {
+		var cells [][]markdownRun
+		header := row.Kind() == east.KindTableHeader
+		for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+			cells = append(cells, c.collectInline(cell, markdownRun{bold: header}))
+		}
+		rows = append(rows, cells)
+	}
+
+	columns := 0
+	for _, cells := range rows {
+		if len(cells) > columns {
+			columns = len(cells)
+		}
+	}
+	if len(rows) == 0 || columns == 0 {
+		return
+	}
+
+	// The newline before a leading table ends the paragraph it is inserted
+	// into, which is not part of the content
+	if c.cursor == c.batch.StartIndex {
+		c.contentStart = c.cursor + 1
+	}
+
+	// InsertTable adds a newline of its own, so a paragraph right before the
+	// table ends with that newline instead of leaving an empty paragraph
+	// between them
+	if pending := c.segment.String(); strings.HasSuffix(pending, "\n") {
+		c.segment.Reset()
+		c.segment.WriteString(strings.TrimSuffix(pending, "\n"))
+		c.cursor--
+	}
+	c.flushSegment()
+
+	// InsertTable adds a newline before the table, so the table starts one
+	// index after the insertion point
+	tableStart := c.cursor + 1
+	rowSize := int64(1 + 2*columns)
+	cellIndex := func(r, col int) int64 {
+		return tableStart + 3 + int64(r)*rowSize + int64(2*col)
+	}
+
+	c.inserts = append(c.inserts, &docs.Request{
+		InsertTable: &docs.InsertTableRequest{
+			Location: &docs.Location{Index: c.cursor},
+			Rows:     int64(len(rows)),
+			Columns:  int64(columns),
+		},
+	})
+
+	var cellInserts []*docs.Request
+	offset := int64(0)
+	for r, cells := range rows {
+		for col := 0; col < columns; col++ {
+			if col >= len(cells) {
+				continue
+			}
+
+			var cellText strings.Builder
+			runStart := cellIndex(r, col) + offset
+			for _, run := range cells[col] {
+				if run.image != "" {
+					continue
+				}
+				cellText.WriteString(run.text)
+				runEnd := runStart + UTF16Length(run.text)
+				c.addTextStyle(run, runStart, runEnd)
+				runStart = runEnd
+			}
+			if cellText.Len() == 0 {
+				continue
+			}
+
+			length := UTF16Length(cellText.String())
+			cellInserts = append(cellInserts, &docs.Request{
+				InsertText: &docs.InsertTextRequest{
+					Location: &docs.Location{Index: cellIndex(r, col)},
+					Text:     cellText.String(),
+				},
+			})
+			c.resets = append(c.resets, resetTextStyleRequest(cellIndex(r, col)+offset, cellIndex(r, col)+offset+length))
+			offset += length
+		}
+	}
+
+	for i := len(cellInserts) - 1; i >= 0; i-- {
+		c.inserts = append(c.inserts, cellInserts[i])
+	}
+
+	tableSize := 2 + int64(len(rows))*rowSize
+	c.cursor = tableStart + tableSize + offset
+	c.segmentStart = c.cursor
+	c.batch.Tables++
+}
+
+// collectInline flattens the inline children of a node into formatted runs
+func (c *markdownCompiler) collectInline(node gast.Node, style markdownRun) []markdownRun {
+	var runs []markdownRun
+
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		switch n := child.(type) {
+		case *gast.Text:
+			run := style
+			run.text = string(n.Segment.Value(c.source))
+			if n.HardLineBreak() {
+				run.text += "\v"
+			} else if n.SoftLineBreak() {
+				run.text += " "
+			}
+			runs = append(runs, run)
+
+		case *gast.String:
+			run := style
+			run.text = string(n.Value)
+			runs = append(runs, run)
+
+		case *gast.CodeSpan:
+			inner := style
+			inner.code = true
+			runs = append(runs, c.collectInline(n, inner)...)
+
+		case *gast.Emphasis:
+			inner := style
+			if n.Level >= 2 {
+				inner.bold = true
+			} else {
+				inner.italic = true
+			}
+			runs = append(runs, c.collectInline(n, inner)...)
+
+		case *east.Strikethrough:
+			inner := style
+			inner.strikethrough = true
+			runs = append(runs, c.collectInline(n, inner)...)
+
+		case *gast.Link:
+			inner := style
+			inner.link = string(n.Destination)
+			runs = append(runs, c.collectInline(n, inner)...)
+
+		case *gast.AutoLink:
+			run := style
+			run.text = string(n.Label(c.source))
+			run.link = string(n.URL(c.source))
+			if n.AutoLinkType == gast.AutoLinkEmail && !strings.HasPrefix(run.link, "mailto:") {
+				run.link = "mailto:" + run.link
+			}
+			runs = append(runs, run)
+
+		case *gast.Image:
+			runs = append(runs, markdownRun{image: string(n.Destination)})
+
+		case *east.TaskCheckBox:
+			// The checkbox itself is rendered by the BULLET_CHECKBOX preset
+
+		case *gast.RawHTML:
+			segments := n.Segments
+			for i := 0; i < segments.Len(); i++ {
+				segment := segments.At(i)
+				tag := strings.ToLower(string(segment.Value(c.source)))
+				if strings.HasPrefix(tag, "<br") {
+					run := style
+					run.text = "\v"
+					runs = append(runs, run)
+				}
+			}
+
+		default:
+			runs = append(runs, c.collectInline(n, style)...)
+		}
+	}
+
+	return runs
+}
+
+// addTextStyle records the text style request for a formatted run
+func (c *markdownCompiler) addTextStyle(run markdownRun, start, end int64) {
+	if start >= end {
+		return
+	}
+
+	textStyle := &docs.TextStyle{}
+	var fields []string
+
+	if run.bold {
+		textStyle.Bold = true
+		fields = append(fields, "bold")
+	}
+	if run.italic {
+		textStyle.Italic = true
+		fields = append(fields, "italic")
+	}
+	if run.strikethrough {
+		textStyle.Strikethrough = true
+		fields = append(fields, "strikethrough")
+	}
+	if run.code {
+		textStyle.WeightedFontFamily = &docs.WeightedFontFamily{FontFamily: codeFontFamily}
+		fields = append(fields, "weightedFontFamily")
+	}
+	if run.link != "" {
+		textStyle.Link = &docs.Link{Url: run.link}
+		fields = append(fields, "link")
+	}
+
+	if len(fields) == 0 {
+		return
+	}
+
+	c.textStyles = append(c.textStyles, &docs.Request{
+		UpdateTextStyle: &docs.UpdateTextStyleRequest{
+			Range:     &docs.Range{StartIndex: start, EndIndex: end},
+			TextStyle: textStyle,
+			Fields:    strings.Join(fields, ","),
+		},
+	})
+}
+
+// resetTextStyleRequest clears the styles that inserted text inherits from its
+// surroundings so only the Markdown formatting is applied
+func resetTextStyleRequest(start, end int64) *docs.Request {
+	return &docs.Request{
+		UpdateTextStyle: &docs.UpdateTextStyleRequest{
+			Range:     &docs.Range{StartIndex: start, EndIndex: end},
+			TextStyle: &docs.TextStyle{},
+			Fields:    "bold,italic,underline,strikethrough,link,weightedFontFamily",
+		},
+	}
+}
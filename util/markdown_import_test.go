@@ -0,0 +1,273 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/docs/v1"
+)
+
+// describeRequests summarizes requests as one line each with the ranges they
+// touch, so tests can compare them with a readable list
+func describeRequests(requests []*docs.Request) []string {
+	var lines []string
+	for _, r := range requests {
+		lines = append(lines, describeRequest(r))
+	}
+	return lines
+}
+
+func describeRequest(r *docs.Request) string {
+	span := func(rng *docs.Range) string {
+		if rng.SegmentId != "" {
+			return fmt.Sprintf("%s:%d-%d", rng.SegmentId, rng.StartIndex, rng.EndIndex)
+		}
+		return fmt.Sprintf("%d-%d", rng.StartIndex, rng.EndIndex)
+	}
+	at := func(loc *docs.Location) string {
+		if loc.SegmentId != "" {
+			return fmt.Sprintf("%s:%d", loc.SegmentId, loc.Index)
+		}
+		return fmt.Sprint(loc.Index)
+	}
+
+	switch {
+	case r.InsertText != nil:
+		return fmt.Sprintf("insertText %s %q", at(r.InsertText.Location), r.InsertText.Text)
+	case r.InsertTable != nil:
+		return fmt.Sprintf("insertTable %s %dx%d", at(r.InsertTable.Location), r.InsertTable.Rows, r.InsertTable.Columns)
+	case r.InsertInlineImage != nil:
+		return fmt.Sprintf("insertImage %s %s", at(r.InsertInlineImage.Location), r.InsertInlineImage.Uri)
+	case r.DeleteContentRange != nil:
+		return "deleteContent " + span(r.DeleteContentRange.Range)
+	case r.InsertTableRow != nil:
+		cell := r.InsertTableRow.TableCellLocation
+		return fmt.Sprintf("insertRow %s row %d", at(cell.TableStartLocation), cell.RowIndex)
+	case r.DeleteTableRow != nil:
+		cell := r.DeleteTableRow.TableCellLocation
+		return fmt.Sprintf("deleteRow %s row %d", at(cell.TableStartLocation), cell.RowIndex)
+	case r.UpdateParagraphStyle != nil:
+		return fmt.Sprintf("paragraphStyle %s %s", span(r.UpdateParagraphStyle.Range), r.UpdateParagraphStyle.ParagraphStyle.NamedStyleType)
+	case r.UpdateTextStyle != nil:
+		return fmt.Sprintf("textStyle %s %s", span(r.UpdateTextStyle.Range), r.UpdateTextStyle.Fields)
+	case r.DeleteParagraphBullets != nil:
+		return "deleteBullets " + span(r.DeleteParagraphBullets.Range)
+	case r.CreateParagraphBullets != nil:
+		return fmt.Sprintf("createBullets %s %s", span(r.CreateParagraphBullets.Range), r.CreateParagraphBullets.BulletPreset)
+	}
+	return "unknown request"
+}
+
+// compareRequests reports the requests that differ from want
+func compareRequests(t *testing.T, requests []*docs.Request, want []string) {
+	t.Helper()
+	got := describeRequests(requests)
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("requests:\n\t%s\nwant:\n\t%s", strings.Join(got, "\n\t"), strings.Join(want, "\n\t"))
+	}
+}
+
+const resetFields = "bold,italic,underline,strikethrough,link,weightedFontFamily"
+
+func TestCompileMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		index    int64
+		fill     bool
+		want     []string
+		end      int64
+	}{
+		{
+			name:     "heading and paragraph",
+			markdown: "# Title\n\nHello **world**\n",
+			index:    1,
+			want: []string{
+				`insertText 1 "Title\nHello world\n"`,
+				"textStyle 1-19 " + resetFields,
+				"deleteBullets 1-19",
+				"paragraphStyle 1-7 HEADING_1",
+				"paragraphStyle 7-19 NORMAL_TEXT",
+				"textStyle 13-18 bold",
+			},
+			end: 19,
+		},
+		{
+			// The last paragraph takes over the newline of the paragraph the
+			// content is inserted into
+			name:     "fill paragraph",
+			markdown: "# Title\n\nHello **world**\n",
+			index:    1,
+			fill:     true,
+			want: []string{
+				`insertText 1 "Title\nHello world"`,
+				"textStyle 1-18 " + resetFields,
+				"deleteBullets 1-19",
+				"paragraphStyle 1-7 HEADING_1",
+				"paragraphStyle 7-19 NORMAL_TEXT",
+				"textStyle 13-18 bold",
+			},
+			end: 18,
+		},
+		{
+			// The emoji is a surrogate pair taking two indices
+			name:     "UTF-16",
+			markdown: "😀 **b**",
+			index:    1,
+			want: []string{
+				`insertText 1 "😀 b\n"`,
+				"textStyle 1-6 " + resetFields,
+				"deleteBullets 1-6",
+				"paragraphStyle 1-6 NORMAL_TEXT",
+				"textStyle 4-5 bold",
+			},
+			end: 6,
+		},
+		{
+			// The paragraph ends with the newline InsertTable adds before the
+			// table at 9. Cells are filled last to first at the indices of the
+			// empty table; their styles use the indices once all are filled.
+			name:     "table after paragraph",
+			markdown: "Intro **x**\n\n| a | b |\n|---|---|\n| c | d |\n",
+			index:    1,
+			want: []string{
+				`insertText 1 "Intro x"`,
+				"insertTable 8 2x2",
+				`insertText 19 "d"`,
+				`insertText 17 "c"`,
+				`insertText 14 "b"`,
+				`insertText 12 "a"`,
+				"textStyle 1-8 " + resetFields,
+				"textStyle 12-13 " + resetFields,
+				"textStyle 15-16 " + resetFields,
+				"textStyle 19-20 " + resetFields,
+				"textStyle 22-23 " + resetFields,
+				"deleteBullets 1-25",
+				"paragraphStyle 1-9 NORMAL_TEXT",
+				"textStyle 7-8 bold",
+				"textStyle 12-13 bold",
+				"textStyle 15-16 bold",
+			},
+			end: 25,
+		},
+		{
+			// The newline before the table ends the paragraph at 10, which
+			// keeps its bullet; the paragraph after the table is made plain
+			name:     "leading table",
+			markdown: "| a |\n|---|\n",
+			index:    10,
+			fill:     true,
+			want: []string{
+				"insertTable 10 1x1",
+				`insertText 14 "a"`,
+				"textStyle 14-15 " + resetFields,
+				"deleteBullets 11-17",
+				"deleteBullets 17-18",
+				"paragraphStyle 17-18 NORMAL_TEXT",
+				"textStyle 14-15 bold",
+			},
+			end: 17,
+		},
+		{
+			// Creating the bullets removes the tab before "b", so the content
+			// ends one index before the cursor
+			name:     "nested list",
+			markdown: "- a\n  - b\n- c\n",
+			index:    5,
+			want: []string{
+				`insertText 5 "a\n\tb\nc\n"`,
+				"textStyle 5-12 " + resetFields,
+				"deleteBullets 5-12",
+				"paragraphStyle 5-7 NORMAL_TEXT",
+				"paragraphStyle 7-10 NORMAL_TEXT",
+				"paragraphStyle 10-12 NORMAL_TEXT",
+				"createBullets 5-12 BULLET_DISC_CIRCLE_SQUARE",
+			},
+			end: 11,
+		},
+		{
+			// The second list is created first, while the tab in the first
+			// list still holds its indices
+			name:     "lists last to first",
+			markdown: "1. a\n   1. b\n\ntext\n\n- c\n  - d\n",
+			index:    1,
+			want: []string{
+				`insertText 1 "a\n\tb\ntext\nc\n\td\n"`,
+				"textStyle 1-16 " + resetFields,
+				"deleteBullets 1-16",
+				"paragraphStyle 1-3 NORMAL_TEXT",
+				"paragraphStyle 3-6 NORMAL_TEXT",
+				"paragraphStyle 6-11 NORMAL_TEXT",
+				"paragraphStyle 11-13 NORMAL_TEXT",
+				"paragraphStyle 13-16 NORMAL_TEXT",
+				"createBullets 11-16 BULLET_DISC_CIRCLE_SQUARE",
+				"createBullets 1-6 NUMBERED_DECIMAL_ALPHA_ROMAN",
+			},
+			end: 14,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batch, err := CompileMarkdown(tt.markdown, tt.index, tt.fill)
+			if err != nil {
+				t.Fatal(err)
+			}
+			compareRequests(t, batch.Requests, tt.want)
+			if batch.EndIndex != tt.end {
+				t.Errorf("EndIndex = %d, want %d", batch.EndIndex, tt.end)
+			}
+		})
+	}
+}
+
+func TestCompilePlainText(t *testing.T) {
+	tests := []struct {
+		name string
+		fill bool
+		want []string
+		end  int64
+	}{
+		{
+			name: "insert",
+			want: []string{
+				`insertText 3 "one\ntwo\n"`,
+				"textStyle 3-11 " + resetFields,
+				"deleteBullets 3-11",
+				"paragraphStyle 3-7 NORMAL_TEXT",
+				"paragraphStyle 7-11 NORMAL_TEXT",
+			},
+			end: 11,
+		},
+		{
+			name: "fill paragraph",
+			fill: true,
+			want: []string{
+				`insertText 3 "one\ntwo"`,
+				"textStyle 3-10 " + resetFields,
+				"deleteBullets 3-11",
+				"paragraphStyle 3-7 NORMAL_TEXT",
+				"paragraphStyle 7-11 NORMAL_TEXT",
+			},
+			end: 10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batch, err := CompilePlainText("one\r\ntwo\n", 3, tt.fill)
+			if err != nil {
+				t.Fatal(err)
+			}
+			compareRequests(t, batch.Requests, tt.want)
+			if batch.EndIndex != tt.end {
+				t.Errorf("EndIndex = %d, want %d", batch.EndIndex, tt.end)
+			}
+		})
+	}
+}
+
+func TestCompileMarkdownIndex(t *testing.T) {
+	if _, err := CompileMarkdown("text", 0, false); err == nil {
+		t.Error("CompileMarkdown at index 0 succeeded, want an error")
+	}
+}
@@ -0,0 +1,77 @@
+package util
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LoadMergeRecords reads the records of a mail merge from a local file. A .csv
+// file has a header row naming the columns, and each following row is a
+// record with a string value per column. A .json file holds an array of
+// objects, one per record.
+func LoadMergeRecords(path string) ([]map[string]any, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	content = bytes.TrimPrefix(content, []byte("\ufeff"))
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return parseCSVRecords(content)
+	case ".json":
+		return parseJSONRecords(content)
+	}
+	return nil, fmt.Errorf("unsupported data file '%s': must be a .csv or .json file", filepath.Base(path))
+}
+
+func parseCSVRecords(content []byte) ([]map[string]any, error) {
+	reader := csv.NewReader(bytes.NewReader(content))
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("the CSV file has no header row")
+	}
+
+	header := make([]string, len(rows[0]))
+	for i, name := range rows[0] {
+		header[i] = strings.TrimSpace(name)
+		if header[i] == "" {
+			return nil, fmt.Errorf("column %d of the CSV header has no name", i+1)
+		}
+	}
+
+	records := make([]map[string]any, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		record := make(map[string]any, len(header))
+		for i, name := range header {
+			record[name] = row[i]
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func parseJSONRecords(content []byte) ([]map[string]any, error) {
+	var items []any
+	if err := json.Unmarshal(content, &items); err != nil {
+		return nil, fmt.Errorf("invalid JSON: the file must hold an array of objects: %w", err)
+	}
+
+	records := make([]map[string]any, 0, len(items))
+	for i, item := range items {
+		record, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("item %d of the JSON array is not an object", i+1)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
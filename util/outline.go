@@ -0,0 +1,123 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/docs/v1"
+)
+
+// OutlineEntry is a heading of a document together with the range of the
+// section it starts and a count of the content inside that section
+type OutlineEntry struct {
+	Level           int    `json:"level"`
+	Text            string `json:"text"`
+	HeadingID       string `json:"heading_id,omitempty"`
+	Parent          int    `json:"parent" jsonschema_description:"Position in the headings list of the enclosing heading, or -1 for a top-level heading"`
+	StartIndex      int64  `json:"start_index" jsonschema_description:"Start of the heading, which is also the start of its section"`
+	HeadingEndIndex int64  `json:"heading_end_index" jsonschema_description:"End of the heading paragraph"`
+	EndIndex        int64  `json:"end_index" jsonschema_description:"End of the section, at the next heading of the same or higher level or the end of the body"`
+	Tables          int    `json:"tables" jsonschema_description:"Tables in the section, including its subsections"`
+	Images          int    `json:"images" jsonschema_description:"Inline images in the section, including its subsections"`
+	Lists           int    `json:"lists" jsonschema_description:"Lists in the section, including its subsections"`
+}
+
+// BuildOutline returns the headings of the document body in order. Each entry
+// spans from its heading to the next heading of the same or higher level, or
+// to the end of the body, and points to the closest preceding heading of a
+// higher level as its parent.
+func BuildOutline(doc *docs.Document) []OutlineEntry {
+	if doc == nil || doc.Body == nil {
+		return nil
+	}
+
+	var outline []OutlineEntry
+	content := doc.Body.Content
+	for i, element := range content {
+		level := HeadingLevel(element.Paragraph)
+		if level == 0 {
+			continue
+		}
+
+		text := strings.TrimSpace(ParagraphText(element.Paragraph))
+		if text == "" {
+			continue
+		}
+
+		entry := OutlineEntry{
+			Level:           level,
+			Text:            text,
+			HeadingID:       element.Paragraph.ParagraphStyle.HeadingId,
+			Parent:          -1,
+			StartIndex:      element.StartIndex,
+			HeadingEndIndex: element.EndIndex,
+			EndIndex:        BodyEndIndex(doc),
+		}
+
+		end := len(content)
+		for j, next := range content[i+1:] {
+			if nextLevel := HeadingLevel(next.Paragraph); nextLevel > 0 && nextLevel <= level {
+				entry.EndIndex = next.StartIndex
+				end = i + 1 + j
+				break
+			}
+		}
+
+		lists := make(map[string]bool)
+		countSectionContent(content[i+1:end], &entry, lists)
+		entry.Lists = len(lists)
+
+		for parent := len(outline) - 1; parent >= 0; parent-- {
+			if outline[parent].Level < level {
+				entry.Parent = parent
+				break
+			}
+		}
+
+		outline = append(outline, entry)
+	}
+
+	return outline
+}
+
+// FindSection returns the outline entry of the section started by the first
+// heading with the given heading ID or text. Text is compared ignoring case.
+func FindSection(doc *docs.Document, heading string) (OutlineEntry, error) {
+	outline := BuildOutline(doc)
+	for _, entry := range outline {
+		if entry.HeadingID != "" && entry.HeadingID == heading {
+			return entry, nil
+		}
+	}
+	for _, entry := range outline {
+		if strings.EqualFold(entry.Text, strings.TrimSpace(heading)) {
+			return entry, nil
+		}
+	}
+	return OutlineEntry{}, fmt.Errorf("heading '%s' not found in the document", heading)
+}
+
+// countSectionContent adds the tables and images in the elements to the entry
+// and collects the IDs of the lists they contain, looking inside table cells
+func countSectionContent(elements []*docs.StructuralElement, entry *OutlineEntry, lists map[string]bool) {
+	for _, element := range elements {
+		switch {
+		case element.Paragraph != nil:
+			if element.Paragraph.Bullet != nil {
+				lists[element.Paragraph.Bullet.ListId] = true
+			}
+			for _, paragraphElement := range element.Paragraph.Elements {
+				if paragraphElement.InlineObjectElement != nil {
+					entry.Images++
+				}
+			}
+		case element.Table != nil:
+			entry.Tables++
+			for _, row := range element.Table.TableRows {
+				for _, cell := range row.TableCells {
+					countSectionContent(cell.Content, entry, lists)
+				}
+			}
+		}
+	}
+}
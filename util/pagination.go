@@ -0,0 +1,88 @@
+package util
+
+import (
+	"strings"
+
+	"google.golang.org/api/docs/v1"
+)
+
+// PageOptions controls where NextPage ends a page
+type PageOptions struct {
+	MaxChars      int64 // Character budget of a page in UTF-16 code units, 0 for no limit
+	Paragraphs    int   // Maximum number of structural elements on a page, 0 for no limit
+	BySection     bool  // End the page before the next heading
+	WholeElements bool  // Never split a paragraph that alone exceeds the budget
+}
+
+// NextPage returns the end index of the page of the document body that starts
+// at start and extends at most to end. Pages end at element boundaries: a page
+// holds as many paragraphs and tables as the options allow, but always at
+// least one. A single paragraph exceeding the budget is split near the budget
+// unless WholeElements is set.
+func NextPage(doc *docs.Document, textMap *TextMap, start, end int64, options PageOptions) int64 {
+	if doc == nil || doc.Body == nil {
+		return end
+	}
+
+	pageEnd := start
+	chars := int64(0)
+	count := 0
+	for _, element := range doc.Body.Content {
+		if element.EndIndex <= start {
+			continue
+		}
+		if element.StartIndex >= end {
+			break
+		}
+
+		if count > 0 {
+			if options.BySection && HeadingLevel(element.Paragraph) > 0 {
+				break
+			}
+			if options.Paragraphs > 0 && count >= options.Paragraphs {
+				break
+			}
+		}
+
+		elementStart := max(element.StartIndex, start)
+		elementEnd := min(element.EndIndex, end)
+		length := UTF16Length(textMap.Slice(elementStart, elementEnd))
+
+		if options.MaxChars > 0 && chars+length > options.MaxChars {
+			if count > 0 {
+				break
+			}
+			if element.Paragraph != nil && !options.WholeElements {
+				return splitText(textMap, elementStart, elementEnd, options.MaxChars)
+			}
+		}
+
+		chars += length
+		count++
+		pageEnd = elementEnd
+	}
+
+	if pageEnd <= start {
+		return end
+	}
+	return pageEnd
+}
+
+// splitText returns the index at which to cut the text between start and end
+// so that at most maxChars come before it, preferring to cut after whitespace
+func splitText(textMap *TextMap, start, end int64, maxChars int64) int64 {
+	text := textMap.Slice(start, end)
+	cut := utf16PrefixBytes(text, maxChars)
+	if space := strings.LastIndexAny(text[:cut], " \t\v"); space >= cut/2 {
+		cut = space + 1
+	}
+	if cut == 0 {
+		return end
+	}
+
+	index := textMap.DocIndex(textMap.TextOffset(start) + cut)
+	if index <= start || index > end {
+		return end
+	}
+	return index
+}
@@ -0,0 +1,68 @@
+package util
+
+// indexEdit records a single change to the document: Deleted code units were
+// removed at Index and Inserted code units were added in their place
+type indexEdit struct {
+	Index    int64
+	Deleted  int64
+	Inserted int64
+}
+
+// IndexRebaser tracks the inserts and deletes made by a sequence of operations
+// so indices expressed against an earlier state of the document can be mapped
+// to the state after those operations. Step 0 is the original document; each
+// recorded edit advances the step by one.
+type IndexRebaser struct {
+	edits []indexEdit
+}
+
+// Step returns the number of edits recorded so far
+func (r *IndexRebaser) Step() int {
+	return len(r.edits)
+}
+
+// Record adds an edit expressed in the coordinates of the current step
+func (r *IndexRebaser) Record(index, deleted, inserted int64) {
+	if deleted == 0 && inserted == 0 {
+		return
+	}
+	r.edits = append(r.edits, indexEdit{Index: index, Deleted: deleted, Inserted: inserted})
+}
+
+// Start maps the start of a range, given in the coordinates of step from, to
+// the current step. Text inserted exactly at the index is placed before it.
+func (r *IndexRebaser) Start(index int64, from int) int64 {
+	return r.rebase(index, from, false)
+}
+
+// End maps the exclusive end of a range, given in the coordinates of step
+// from, to the current step. Text inserted exactly at the index is placed
+// after it, so the range does not grow to include it.
+func (r *IndexRebaser) End(index int64, from int) int64 {
+	return r.rebase(index, from, true)
+}
+
+func (r *IndexRebaser) rebase(index int64, from int, isEnd bool) int64 {
+	for _, edit := range r.edits[from:] {
+		switch {
+		case index < edit.Index, isEnd && index == edit.Index && edit.Deleted == 0:
+			// Before the edit, unaffected
+		case index < edit.Index+edit.Deleted:
+			// Inside deleted content, collapse onto the edit point
+			if isEnd {
+				index = edit.Index
+			} else {
+				index = edit.Index + edit.Inserted
+			}
+		default:
+			index += edit.Inserted - edit.Deleted
+		}
+	}
+	return index
+}
+
+// InsertedTableLength returns how many indices an InsertTable request adds to
+// the document, including the newline inserted before the table
+func InsertedTableLength(rows, columns int64) int64 {
+	return 1 + 2 + rows*(1+2*columns)
+}
@@ -0,0 +1,80 @@
+package util
+
+import "testing"
+
+func TestIndexRebaserSingleEdit(t *testing.T) {
+	tests := []struct {
+		name     string
+		deleted  int64
+		inserted int64
+		index    int64
+		start    int64
+		end      int64
+	}{
+		{"before insert", 0, 5, 9, 9, 9},
+		{"at insert", 0, 5, 10, 15, 10},
+		{"after insert", 0, 5, 11, 16, 16},
+		{"at delete", 4, 0, 10, 10, 10},
+		{"inside delete", 4, 0, 12, 10, 10},
+		{"end of delete", 4, 0, 14, 10, 10},
+		{"after delete", 4, 0, 20, 16, 16},
+		{"inside replace", 4, 2, 12, 12, 10},
+		{"after replace", 4, 2, 20, 18, 18},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r IndexRebaser
+			r.Record(10, tt.deleted, tt.inserted)
+			if got := r.Start(tt.index, 0); got != tt.start {
+				t.Errorf("Start(%d) = %d, want %d", tt.index, got, tt.start)
+			}
+			if got := r.End(tt.index, 0); got != tt.end {
+				t.Errorf("End(%d) = %d, want %d", tt.index, got, tt.end)
+			}
+		})
+	}
+}
+
+func TestIndexRebaserSteps(t *testing.T) {
+	var r IndexRebaser
+	r.Record(5, 0, 3)
+	r.Record(4, 0, 0)
+	if r.Step() != 1 {
+		t.Fatalf("Step() = %d after an empty edit, want 1", r.Step())
+	}
+	// Deletes 1-3 in the coordinates after the first insert
+	r.Record(1, 2, 0)
+
+	tests := []struct {
+		index int64
+		from  int
+		start int64
+		end   int64
+	}{
+		{10, 0, 11, 11},
+		{10, 1, 8, 8},
+		{10, 2, 10, 10},
+		{5, 0, 6, 3},
+		{2, 0, 1, 1},
+	}
+	for _, tt := range tests {
+		if got := r.Start(tt.index, tt.from); got != tt.start {
+			t.Errorf("Start(%d, %d) = %d, want %d", tt.index, tt.from, got, tt.start)
+		}
+		if got := r.End(tt.index, tt.from); got != tt.end {
+			t.Errorf("End(%d, %d) = %d, want %d", tt.index, tt.from, got, tt.end)
+		}
+	}
+}
+
+func TestInsertedTableLength(t *testing.T) {
+	// An inserted table has empty cells and comes after its own newline
+	doc := testDocument(para("a\n"), table([]string{"", ""}, []string{"", ""}), para("b\n"))
+	tableElement := doc.Body.Content[2]
+	if got, want := InsertedTableLength(2, 2), tableElement.EndIndex-tableElement.StartIndex+1; got != want {
+		t.Errorf("InsertedTableLength(2, 2) = %d, want %d", got, want)
+	}
+	if got := InsertedTableLength(1, 1); got != 6 {
+		t.Errorf("InsertedTableLength(1, 1) = %d, want 6", got)
+	}
+}
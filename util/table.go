@@ -0,0 +1,255 @@
+package util
+
+import (
+	"encoding/csv"
+	"strings"
+
+	"google.golang.org/api/docs/v1"
+)
+
+// Size limits of tables inserted by the tools
+const (
+	MaxTableRows    = 100
+	MaxTableColumns = 20
+)
+
+// TableCellContentRange returns the range of the content of a table cell,
+// leaving out the newline that ends its last paragraph, which cannot be
+// deleted. The range is empty for an empty cell.
+func TableCellContentRange(cell *docs.TableCell) (int64, int64) {
+	if len(cell.Content) == 0 {
+		return cell.StartIndex + 1, cell.StartIndex + 1
+	}
+	return cell.Content[0].StartIndex, cell.Content[len(cell.Content)-1].EndIndex - 1
+}
+
+// TableCellTextRequests returns the requests that replace the content of a
+// table cell with text. Requests for several cells of a document must be
+// applied from the last cell to the first so the indices stay valid.
+func TableCellTextRequests(cell *docs.TableCell, text string) []*docs.Request {
+	start, end := TableCellContentRange(cell)
+
+	var requests []*docs.Request
+	if end > start {
+		requests = append(requests, &docs.Request{
+			DeleteContentRange: &docs.DeleteContentRangeRequest{
+				Range: &docs.Range{StartIndex: start, EndIndex: end},
+			},
+		})
+	}
+	if text != "" {
+		requests = append(requests, &docs.Request{
+			InsertText: &docs.InsertTextRequest{
+				Location: &docs.Location{Index: start},
+				Text:     text,
+			},
+		})
+	}
+	return requests
+}
+
+// TableDataRequests returns the requests that insert a table of the given
+// size at index and fill it with data, one slice of cell texts per row, along
+// with the number of indices they add. Missing cells are left empty. With
+// header, the first row is bold and pinned as a header row.
+func TableDataRequests(index int64, data [][]string, rows, columns int64, header bool) ([]*docs.Request, int64) {
+	// InsertTable adds a newline before the table, so the table starts one
+	// index after the insertion point
+	tableStart := index + 1
+	rowSize := 1 + 2*columns
+	cellIndex := func(r, c int64) int64 {
+		return tableStart + 3 + r*rowSize + 2*c
+	}
+
+	requests := []*docs.Request{
+		{
+			InsertTable: &docs.InsertTableRequest{
+				Location: &docs.Location{Index: index},
+				Rows:     rows,
+				Columns:  columns,
+			},
+		},
+	}
+
+	// Fill the cells last to first so the empty-table indices stay valid
+	length := InsertedTableLength(rows, columns)
+	for r := int64(len(data)) - 1; r >= 0; r-- {
+		for c := min(int64(len(data[r])), columns) - 1; c >= 0; c-- {
+			if data[r][c] == "" {
+				continue
+			}
+			requests = append(requests, &docs.Request{
+				InsertText: &docs.InsertTextRequest{
+					Location: &docs.Location{Index: cellIndex(r, c)},
+					Text:     data[r][c],
+				},
+			})
+			length += UTF16Length(data[r][c])
+		}
+	}
+
+	if !header {
+		return requests, length
+	}
+
+	if len(data) > 0 {
+		// Header cells only move by the text of the header cells before them
+		offset := int64(0)
+		for c := int64(0); c < min(int64(len(data[0])), columns); c++ {
+			textLength := UTF16Length(data[0][c])
+			if textLength == 0 {
+				continue
+			}
+			start := cellIndex(0, c) + offset
+			requests = append(requests, &docs.Request{
+				UpdateTextStyle: &docs.UpdateTextStyleRequest{
+					Range:     &docs.Range{StartIndex: start, EndIndex: start + textLength},
+					TextStyle: &docs.TextStyle{Bold: true},
+					Fields:    "bold",
+				},
+			})
+			offset += textLength
+		}
+	}
+	requests = append(requests, &docs.Request{
+		PinTableHeaderRows: &docs.PinTableHeaderRowsRequest{
+			TableStartLocation:    &docs.Location{Index: tableStart},
+			PinnedHeaderRowsCount: 1,
+		},
+	})
+
+	return requests, length
+}
+
+// TableGrid is the content of a table as text, cell by cell
+type TableGrid struct {
+	Cells  [][]string
+	Merges []TableMerge
+	Nested []NestedTable
+}
+
+// TableMerge is a cell that spans several rows or columns
+type TableMerge struct {
+	Row        int `json:"row"`
+	Column     int `json:"column"`
+	RowSpan    int `json:"row_span"`
+	ColumnSpan int `json:"column_span"`
+}
+
+// NestedTable is a table inside a cell of another table
+type NestedTable struct {
+	Row     int
+	Column  int
+	Element *docs.StructuralElement
+}
+
+// ReadTableGrid returns the text of every cell of a table along with its
+// merged cells and the tables nested in its cells. The cells covered by a
+// merged cell are empty, or repeat its text with fillMerged.
+func ReadTableGrid(table *docs.Table, fillMerged bool) TableGrid {
+	columns := int(table.Columns)
+	for _, row := range table.TableRows {
+		columns = max(columns, len(row.TableCells))
+	}
+
+	grid := TableGrid{
+		Cells:  make([][]string, len(table.TableRows)),
+		Merges: []TableMerge{},
+	}
+	for r, row := range table.TableRows {
+		grid.Cells[r] = make([]string, columns)
+		for c, cell := range row.TableCells {
+			grid.Cells[r][c] = TableCellText(cell)
+
+			for _, element := range cell.Content {
+				if element.Table != nil {
+					grid.Nested = append(grid.Nested, NestedTable{Row: r, Column: c, Element: element})
+				}
+			}
+
+			if cell.TableCellStyle == nil {
+				continue
+			}
+			rowSpan, columnSpan := int(cell.TableCellStyle.RowSpan), int(cell.TableCellStyle.ColumnSpan)
+			if rowSpan > 1 || columnSpan > 1 {
+				grid.Merges = append(grid.Merges, TableMerge{Row: r, Column: c, RowSpan: max(rowSpan, 1), ColumnSpan: max(columnSpan, 1)})
+			}
+		}
+	}
+
+	// The cells covered by a merged cell keep their own, normally empty,
+	// content in the document
+	for _, merge := range grid.Merges {
+		for r := merge.Row; r < min(merge.Row+merge.RowSpan, len(grid.Cells)); r++ {
+			for c := merge.Column; c < min(merge.Column+merge.ColumnSpan, columns); c++ {
+				if r == merge.Row && c == merge.Column {
+					continue
+				}
+				grid.Cells[r][c] = ""
+				if fillMerged {
+					grid.Cells[r][c] = grid.Cells[merge.Row][merge.Column]
+				}
+			}
+		}
+	}
+
+	return grid
+}
+
+// TableCellText returns the text of the paragraphs of a table cell, one line
+// per paragraph. Tables nested in the cell are left out.
+func TableCellText(cell *docs.TableCell) string {
+	var lines []string
+	for _, element := range cell.Content {
+		if element.Paragraph == nil {
+			continue
+		}
+		var line strings.Builder
+		for _, paragraphElement := range element.Paragraph.Elements {
+			if paragraphElement.TextRun != nil {
+				line.WriteString(paragraphElement.TextRun.Content)
+			}
+		}
+		lines = append(lines, strings.TrimSuffix(line.String(), "\n"))
+	}
+
+	// A cell always ends with a paragraph, which is empty after a nested table
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// TableCSV renders table cells as CSV. Line breaks inside cells are kept in
+// quoted fields.
+func TableCSV(cells [][]string) (string, error) {
+	var sb strings.Builder
+	writer := csv.NewWriter(&sb)
+	if err := writer.WriteAll(cells); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// TableMarkdown renders table cells as a GFM table, using the first row as
+// the header. Line breaks inside cells become <br>.
+func TableMarkdown(cells [][]string) string {
+	if len(cells) == 0 || len(cells[0]) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	for i, row := range cells {
+		sb.WriteString("|")
+		for _, cell := range row {
+			cell = strings.ReplaceAll(cell, "|", "\\|")
+			sb.WriteString(" " + strings.ReplaceAll(cell, "\n", "<br>") + " |")
+		}
+		sb.WriteString("\n")
+
+		if i == 0 {
+			sb.WriteString("|" + strings.Repeat(" --- |", len(row)) + "\n")
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,960 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/api/docs/v1"
+)
+
+// templateTag matches {{name}}, {{#if name}}, {{/if}}, {{#each name}} and {{/each}}
+var templateTag = regexp.MustCompile(`\{\{\s*(#if|#each|/if|/each)?\s*([\w.]*)\s*\}\}`)
+
+// TemplateBatch holds the Docs API requests that fill a template document
+type TemplateBatch struct {
+	Requests     []*docs.Request
+	Placeholders int      // Placeholders replaced with a value
+	Images       int      // Placeholders replaced with an image
+	Sections     int      // Repeated sections expanded
+	Rows         int      // Repeated table rows expanded
+	Removed      int      // Conditional blocks removed
+	Unresolved   []string // Placeholders without a value, left in the document
+}
+
+// templateEdit is a group of requests that only touch the document at or after
+// index, so edits applied from the highest index down never move each other
+type templateEdit struct {
+	index    int64
+	requests []*docs.Request
+}
+
+// templateMatch is a tag found in the text of a paragraph
+type templateMatch struct {
+	kind       string // "", "#if", "/if", "#each" or "/each"
+	name       string
+	start, end int // Byte offsets in the paragraph text
+}
+
+// templateCompiler finds the tags of a template document and builds the edits
+// that fill them
+type templateCompiler struct {
+	doc        *docs.Document
+	values     map[string]any
+	edits      []templateEdit
+	unresolved map[string]bool
+	batch      *TemplateBatch
+}
+
+// CompileTemplate compiles the requests that fill a template document with
+// values in a single batch update. In the body, headers and footers:
+//
+//   - {{name}} is replaced with the value, keeping the style of the tag. Dotted
+//     names look inside objects, and an object {"type": "image", "url": ...}
+//     inserts an image, optionally sized with "width" and "height" in points.
+//   - {{#if name}}...{{/if}} is removed with its content when the value is
+//     missing, false, zero or empty; otherwise only the tags are removed. The
+//     tags can be within a paragraph or on paragraphs of their own.
+//   - {{#each name}} and {{/each}} on paragraphs of their own repeat the
+//     paragraphs between them for every item of an array value.
+//   - {{#each name}} in a table row repeats the row for every item.
+//
+// Inside repeated content, names are looked up in the item first and {{this}}
+// is the item itself. Repeated content keeps its text and paragraph styles,
+// but not inline objects or tables.
+func CompileTemplate(doc *docs.Document, values map[string]any) (*TemplateBatch, error) {
+	c := &templateCompiler{
+		doc:        doc,
+		values:     values,
+		unresolved: make(map[string]bool),
+		batch:      &TemplateBatch{},
+	}
+
+	if doc.Body != nil {
+		if err := c.compileElements(doc.Body.Content, "", BodyEndIndex(doc)); err != nil {
+			return nil, err
+		}
+	}
+	for _, id := range SortedSegmentIDs(doc.Headers) {
+		content := doc.Headers[id].Content
+		if err := c.compileElements(content, id, contentEndIndex(content)); err != nil {
+			return nil, fmt.Errorf("header %s: %w", id, err)
+		}
+	}
+	for _, id := range SortedSegmentIDs(doc.Footers) {
+		content := doc.Footers[id].Content
+		if err := c.compileElements(content, id, contentEndIndex(content)); err != nil {
+			return nil, fmt.Errorf("footer %s: %w", id, err)
+		}
+	}
+
+	// Segments have their own indices, so only the order within a segment
+	// matters; apply every edit from the highest index down
+	sort.SliceStable(c.edits, func(i, j int) bool {
+		return c.edits[i].index > c.edits[j].index
+	})
+	for _, edit := range c.edits {
+		c.batch.Requests = append(c.batch.Requests, edit.requests...)
+	}
+
+	for name := range c.unresolved {
+		c.batch.Unresolved = append(c.batch.Unresolved, name)
+	}
+	sort.Strings(c.batch.Unresolved)
+
+	return c.batch, nil
+}
+
+// FillTemplateText replaces the {{name}} placeholders in a line of text, such
+// as a title, with values. Placeholders without a value are left as they are.
+func FillTemplateText(text string, values map[string]any) string {
+	return templateTag.ReplaceAllStringFunc(text, func(tag string) string {
+		matches := findTemplateTags(tag)
+		if len(matches) != 1 || matches[0].kind != "" {
+			return tag
+		}
+		value, found := lookupTemplateValue(matches[0].name, values, nil)
+		if !found {
+			return tag
+		}
+		return formatTemplateValue(value)
+	})
+}
+
+// compileElements compiles the tags in a list of structural elements that end
+// at containerEnd, whose final newline cannot be deleted
+func (c *templateCompiler) compileElements(elements []*docs.StructuralElement, segmentID string, containerEnd int64) error {
+	for i := 0; i < len(elements); i++ {
+		element := elements[i]
+
+		switch {
+		case element.Paragraph != nil:
+			tag, ok := blockTag(element.Paragraph)
+			if !ok {
+				if err := c.compileParagraph(element, segmentID); err != nil {
+					return err
+				}
+				continue
+			}
+
+			switch tag.kind {
+			case "#each":
+				end, err := findBlockEnd(elements, i, tag)
+				if err != nil {
+					return err
+				}
+				if err := c.compileSection(elements[i:end+1], tag.name, segmentID, containerEnd); err != nil {
+					return err
+				}
+				i = end
+
+			case "#if":
+				end, err := findBlockEnd(elements, i, tag)
+				if err != nil {
+					return err
+				}
+				value, _ := lookupTemplateValue(tag.name, c.values, nil)
+				if !templateTruthy(value) {
+					c.deleteRange(elements[i].StartIndex, elements[end].EndIndex, segmentID, containerEnd)
+					c.batch.Removed++
+					i = end
+					continue
+				}
+				c.deleteRange(elements[i].StartIndex, elements[i].EndIndex, segmentID, containerEnd)
+				if err := c.compileElements(elements[i+1:end], segmentID, containerEnd); err != nil {
+					return err
+				}
+				c.deleteRange(elements[end].StartIndex, elements[end].EndIndex, segmentID, containerEnd)
+				i = end
+
+			default:
+				return fmt.Errorf("{{%s}} without a matching opening tag", tag.kind)
+			}
+
+		case element.Table != nil:
+			if err := c.compileTable(element, segmentID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// compileParagraph compiles the placeholders and inline conditional blocks of a
+// paragraph outside repeated content, editing them in place
+func (c *templateCompiler) compileParagraph(element *docs.StructuralElement, segmentID string) error {
+	textMap := NewTextMapFromElements([]*docs.StructuralElement{element})
+	matches := findTemplateTags(textMap.Text)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	var open []templateMatch
+	skipping := 0 // Number of open blocks being removed
+
+	for _, match := range matches {
+		start, end := textMap.DocRange(match.start, match.end)
+
+		switch match.kind {
+		case "":
+			if skipping > 0 {
+				continue
+			}
+			value, found := lookupTemplateValue(match.name, c.values, nil)
+			if !found {
+				c.unresolved[match.name] = true
+				continue
+			}
+			c.replaceTag(start, end, value, segmentID, []*docs.StructuralElement{element})
+
+		case "#if":
+			value, _ := lookupTemplateValue(match.name, c.values, nil)
+			open = append(open, match)
+			if skipping > 0 || !templateTruthy(value) {
+				skipping++
+			}
+
+		case "/if":
+			if len(open) == 0 {
+				return fmt.Errorf("{{/if}} without a matching {{#if}} in paragraph %q", strings.TrimSpace(textMap.Text))
+			}
+			block := open[len(open)-1]
+			open = open[:len(open)-1]
+			if skipping > 0 {
+				skipping--
+				if skipping == 0 {
+					blockStart, _ := textMap.DocRange(block.start, block.end)
+					c.deleteRange(blockStart, end, segmentID, 0)
+					c.batch.Removed++
+				}
+				continue
+			}
+			openStart, openEnd := textMap.DocRange(block.start, block.end)
+			c.deleteRange(openStart, openEnd, segmentID, 0)
+			c.deleteRange(start, end, segmentID, 0)
+
+		default:
+			return fmt.Errorf("{{%s %s}} must be on a paragraph of its own or in a table row", match.kind, match.name)
+		}
+	}
+
+	if len(open) > 0 {
+		return fmt.Errorf("{{#if %s}} is not closed in paragraph %q", open[0].name, strings.TrimSpace(textMap.Text))
+	}
+	return nil
+}
+
+// replaceTag replaces a placeholder with a value in place. Text takes the style
+// of the tag it replaces rather than of the text before it.
+func (c *templateCompiler) replaceTag(start, end int64, value any, segmentID string, elements []*docs.StructuralElement) {
+	requests := []*docs.Request{{
+		DeleteContentRange: &docs.DeleteContentRangeRequest{
+			Range: &docs.Range{SegmentId: segmentID, StartIndex: start, EndIndex: end},
+		},
+	}}
+
+	if image, ok := templateImage(value); ok {
+		requests = append(requests, image.request(start, segmentID))
+		c.batch.Images++
+		c.edits = append(c.edits, templateEdit{index: start, requests: requests})
+		return
+	}
+
+	text := formatTemplateValue(value)
+	if text != "" {
+		style := TextStyleAt(elements, start)
+		if style == nil {
+			style = &docs.TextStyle{}
+		}
+		requests = append(requests,
+			&docs.Request{
+				InsertText: &docs.InsertTextRequest{
+					Location: &docs.Location{SegmentId: segmentID, Index: start},
+					Text:     text,
+				},
+			},
+			&docs.Request{
+				UpdateTextStyle: &docs.UpdateTextStyleRequest{
+					Range:     &docs.Range{SegmentId: segmentID, StartIndex: start, EndIndex: start + UTF16Length(text)},
+					TextStyle: style,
+					Fields:    "*",
+				},
+			},
+		)
+	}
+	c.batch.Placeholders++
+	c.edits = append(c.edits, templateEdit{index: start, requests: requests})
+}
+
+// deleteRange adds an edit deleting a range. A range reaching containerEnd
+// stops before its final newline, which cannot be deleted.
+func (c *templateCompiler) deleteRange(start, end int64, segmentID string, containerEnd int64) {
+	if containerEnd > 0 && end >= containerEnd {
+		end = containerEnd - 1
+	}
+	if end <= start {
+		return
+	}
+	c.edits = append(c.edits, templateEdit{
+		index: start,
+		requests: []*docs.Request{{
+			DeleteContentRange: &docs.DeleteContentRangeRequest{
+				Range: &docs.Range{SegmentId: segmentID, StartIndex: start, EndIndex: end},
+			},
+		}},
+	})
+}
+
+// compileSection replaces a repeated section, including its tag paragraphs,
+// with its content rendered once per item
+func (c *templateCompiler) compileSection(elements []*docs.StructuralElement, name, segmentID string, containerEnd int64) error {
+	items, err := c.templateItems(name)
+	if err != nil {
+		return err
+	}
+
+	content := &renderedContent{}
+	for _, item := range items {
+		if err := c.render(content, elements[1:len(elements)-1], []any{item}); err != nil {
+			return err
+		}
+	}
+
+	start := elements[0].StartIndex
+	end := elements[len(elements)-1].EndIndex
+	if end >= containerEnd {
+		end = containerEnd - 1
+	}
+
+	var requests []*docs.Request
+	if end > start {
+		requests = append(requests, &docs.Request{
+			DeleteContentRange: &docs.DeleteContentRangeRequest{
+				Range: &docs.Range{SegmentId: segmentID, StartIndex: start, EndIndex: end},
+			},
+		})
+	}
+	requests = append(requests, c.insertRendered(content, start, segmentID, false)...)
+
+	c.edits = append(c.edits, templateEdit{index: start, requests: requests})
+	c.batch.Sections++
+	return nil
+}
+
+// compileTable compiles the tags in each cell of a table, repeating the rows
+// that contain {{#each}}
+func (c *templateCompiler) compileTable(element *docs.StructuralElement, segmentID string) error {
+	// Find the repeated rows first: a table cannot lose all of its rows, so
+	// when every row repeats nothing the whole table goes
+	eachNames := make([]string, len(element.Table.TableRows))
+	eachItems := make([][]any, len(element.Table.TableRows))
+	removed := 0
+	for r, row := range element.Table.TableRows {
+		var cells []*docs.StructuralElement
+		for _, cell := range row.TableCells {
+			cells = append(cells, cell.Content...)
+		}
+
+		for _, match := range findTemplateTags(NewTextMapFromElements(cells).Text) {
+			if match.kind == "#each" {
+				eachNames[r] = match.name
+				break
+			}
+		}
+		if eachNames[r] == "" {
+			continue
+		}
+
+		items, err := c.templateItems(eachNames[r])
+		if err != nil {
+			return err
+		}
+		eachItems[r] = items
+		if len(items) == 0 {
+			removed++
+		}
+	}
+
+	if removed == len(element.Table.TableRows) {
+		c.edits = append(c.edits, templateEdit{
+			index: element.StartIndex,
+			requests: []*docs.Request{{
+				DeleteContentRange: &docs.DeleteContentRangeRequest{
+					Range: &docs.Range{SegmentId: segmentID, StartIndex: element.StartIndex, EndIndex: element.EndIndex},
+				},
+			}},
+		})
+		c.batch.Rows += removed
+		return nil
+	}
+
+	for r, row := range element.Table.TableRows {
+		if eachNames[r] != "" {
+			if err := c.compileRow(element, r, eachNames[r], eachItems[r], segmentID); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, cell := range row.TableCells {
+			if err := c.compileElements(cell.Content, segmentID, contentEndIndex(cell.Content)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// compileRow repeats a table row once per item, or deletes it when there are
+// no items. New rows are inserted below the template row and filled from the
+// last to the first; the template row itself is filled with the first item.
+func (c *templateCompiler) compileRow(element *docs.StructuralElement, rowIndex int, name string, items []any, segmentID string) error {
+	row := element.Table.TableRows[rowIndex]
+
+	location := &docs.TableCellLocation{
+		TableStartLocation: &docs.Location{SegmentId: segmentID, Index: element.StartIndex},
+		RowIndex:           int64(rowIndex),
+	}
+
+	if len(items) == 0 {
+		c.edits = append(c.edits, templateEdit{
+			index: row.StartIndex,
+			requests: []*docs.Request{{
+				DeleteTableRow: &docs.DeleteTableRowRequest{TableCellLocation: location},
+			}},
+		})
+		c.batch.Rows++
+		return nil
+	}
+
+	for _, cell := range row.TableCells {
+		if cell.TableCellStyle != nil && (cell.TableCellStyle.ColumnSpan > 1 || cell.TableCellStyle.RowSpan > 1) {
+			return fmt.Errorf("the repeated table row for '%s' cannot contain merged cells", name)
+		}
+	}
+
+	var requests []*docs.Request
+	for range items[1:] {
+		requests = append(requests, &docs.Request{
+			InsertTableRow: &docs.InsertTableRowRequest{TableCellLocation: location, InsertBelow: true},
+		})
+	}
+
+	// An inserted row is a row marker followed by a cell marker and an empty
+	// paragraph for every column
+	columns := int64(len(row.TableCells))
+	rowLength := 1 + 2*columns
+	for i := len(items) - 1; i >= 0; i-- {
+		for j := len(row.TableCells) - 1; j >= 0; j-- {
+			cell := row.TableCells[j]
+			content := &renderedContent{}
+			if err := c.render(content, cell.Content, []any{items[i]}); err != nil {
+				return err
+			}
+
+			if i > 0 {
+				index := row.EndIndex + int64(i-1)*rowLength + 2 + 2*int64(j)
+				requests = append(requests, c.insertRendered(content, index, segmentID, true)...)
+				continue
+			}
+
+			start := cell.Content[0].StartIndex
+			end := contentEndIndex(cell.Content) - 1
+			if end > start {
+				requests = append(requests, &docs.Request{
+					DeleteContentRange: &docs.DeleteContentRangeRequest{
+						Range: &docs.Range{SegmentId: segmentID, StartIndex: start, EndIndex: end},
+					},
+				})
+			}
+			requests = append(requests, c.insertRendered(content, start, segmentID, true)...)
+		}
+	}
+
+	c.edits = append(c.edits, templateEdit{index: row.StartIndex, requests: requests})
+	c.batch.Rows++
+	return nil
+}
+
+// templateItems returns the items of the array value repeated by an {{#each}}
+// block. A missing value repeats nothing.
+func (c *templateCompiler) templateItems(name string) ([]any, error) {
+	value, found := lookupTemplateValue(name, c.values, nil)
+	if !found || value == nil {
+		c.unresolved[name] = true
+		return nil, nil
+	}
+	items, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("{{#each %s}} needs an array value", name)
+	}
+	return items, nil
+}
+
+// renderedContent is repeated template content rendered as plain text, with
+// the styles and images to apply once it is inserted. Offsets are in UTF-16
+// code units from the start of the text.
+type renderedContent struct {
+	text       strings.Builder
+	length     int64
+	runs       []renderedRun
+	paragraphs []renderedParagraph
+	images     []renderedImage
+}
+
+type renderedRun struct {
+	start, end int64
+	style      *docs.TextStyle
+}
+
+type renderedParagraph struct {
+	start, end int64
+	style      *docs.ParagraphStyle
+	preset     string // Bullet preset, empty for paragraphs outside lists
+}
+
+type renderedImage struct {
+	offset int64
+	image  templateImageValue
+}
+
+func (r *renderedContent) write(text string, style *docs.TextStyle) {
+	if text == "" {
+		return
+	}
+	start := r.length
+	r.text.WriteString(text)
+	r.length += UTF16Length(text)
+	if last := len(r.runs) - 1; last >= 0 && r.runs[last].end == start && r.runs[last].style == style {
+		r.runs[last].end = r.length
+		return
+	}
+	r.runs = append(r.runs, renderedRun{start: start, end: r.length, style: style})
+}
+
+// render appends the paragraphs of repeated content for one item. Scopes are
+// the items of the enclosing repeated blocks, innermost last.
+func (c *templateCompiler) render(content *renderedContent, elements []*docs.StructuralElement, scopes []any) error {
+	for i := 0; i < len(elements); i++ {
+		element := elements[i]
+		if element.Table != nil {
+			return fmt.Errorf("repeated sections cannot contain tables; repeat a table row with {{#each}} in the row instead")
+		}
+		if element.Paragraph == nil {
+			continue
+		}
+
+		tag, ok := blockTag(element.Paragraph)
+		if !ok {
+			if err := c.renderParagraph(content, element.Paragraph, scopes); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if tag.kind != "#if" {
+			return fmt.Errorf("{{%s %s}} cannot be nested in a repeated block", tag.kind, tag.name)
+		}
+		end, err := findBlockEnd(elements, i, tag)
+		if err != nil {
+			return err
+		}
+		value, _ := lookupTemplateValue(tag.name, c.values, scopes)
+		if templateTruthy(value) {
+			if err := c.render(content, elements[i+1:end], scopes); err != nil {
+				return err
+			}
+		}
+		i = end
+	}
+	return nil
+}
+
+// renderParagraph appends one paragraph of repeated content with its tags
+// filled for the item in scope
+func (c *templateCompiler) renderParagraph(content *renderedContent, paragraph *docs.Paragraph, scopes []any) error {
+	type textRun struct {
+		start, end int
+		style      *docs.TextStyle
+	}
+	var runs []textRun
+	var sb strings.Builder
+	for _, element := range paragraph.Elements {
+		if element.TextRun == nil || element.TextRun.Content == "" {
+			continue
+		}
+		start := sb.Len()
+		sb.WriteString(element.TextRun.Content)
+		runs = append(runs, textRun{start: start, end: sb.Len(), style: element.TextRun.TextStyle})
+	}
+	text := sb.String()
+
+	styleAt := func(offset int) *docs.TextStyle {
+		for _, run := range runs {
+			if offset < run.end {
+				return run.style
+			}
+		}
+		return nil
+	}
+
+	// writeText copies template text, split where the style changes
+	writeText := func(start, end int) {
+		for _, run := range runs {
+			from, to := max(start, run.start), min(end, run.end)
+			if from < to {
+				content.write(text[from:to], run.style)
+			}
+		}
+	}
+
+	paragraphStart := content.length
+	cursor := 0
+	skipping := 0
+	depth := 0
+	for _, match := range findTemplateTags(text) {
+		if skipping == 0 {
+			writeText(cursor, match.start)
+		}
+		cursor = match.end
+
+		switch match.kind {
+		case "":
+			if skipping > 0 {
+				continue
+			}
+			value, found := lookupTemplateValue(match.name, c.values, scopes)
+			if !found {
+				c.unresolved[match.name] = true
+				content.write(text[match.start:match.end], styleAt(match.start))
+				continue
+			}
+			if image, ok := templateImage(value); ok {
+				content.images = append(content.images, renderedImage{offset: content.length, image: image})
+				c.batch.Images++
+				continue
+			}
+			content.write(formatTemplateValue(value), styleAt(match.start))
+			c.batch.Placeholders++
+
+		case "#if":
+			depth++
+			value, _ := lookupTemplateValue(match.name, c.values, scopes)
+			if skipping > 0 || !templateTruthy(value) {
+				skipping++
+			}
+
+		case "/if":
+			if depth == 0 {
+				return fmt.Errorf("{{/if}} without a matching {{#if}} in paragraph %q", strings.TrimSpace(text))
+			}
+			depth--
+			if skipping > 0 {
+				skipping--
+				if skipping == 0 {
+					c.batch.Removed++
+				}
+			}
+
+		case "#each", "/each":
+			// The tags of a repeated table row are dropped from its cells
+		}
+	}
+	if depth > 0 {
+		return fmt.Errorf("{{#if}} is not closed in paragraph %q", strings.TrimSpace(text))
+	}
+	writeText(cursor, len(text))
+
+	rendered := renderedParagraph{start: paragraphStart, end: content.length, style: paragraph.ParagraphStyle}
+	if paragraph.Bullet != nil {
+		rendered.preset = c.bulletPreset(paragraph.Bullet)
+	}
+	content.paragraphs = append(content.paragraphs, rendered)
+	return nil
+}
+
+// bulletPreset returns the preset closest to the list a paragraph belongs to
+func (c *templateCompiler) bulletPreset(bullet *docs.Bullet) string {
+	if list, ok := c.doc.Lists[bullet.ListId]; ok && list.ListProperties != nil {
+		levels := list.ListProperties.NestingLevels
+		if int(bullet.NestingLevel) < len(levels) {
+			switch levels[bullet.NestingLevel].GlyphType {
+			case "", "GLYPH_TYPE_UNSPECIFIED", "NONE":
+			default:
+				return "NUMBERED_DECIMAL_ALPHA_ROMAN"
+			}
+		}
+	}
+	return "BULLET_DISC_CIRCLE_SQUARE"
+}
+
+// insertRendered returns the requests inserting rendered content at index. In
+// a table cell the content goes into the cell's existing empty paragraph, so
+// the final newline is left out.
+func (c *templateCompiler) insertRendered(content *renderedContent, index int64, segmentID string, inCell bool) []*docs.Request {
+	text := content.text.String()
+	if inCell {
+		text = strings.TrimSuffix(text, "\n")
+	}
+	if text == "" && len(content.images) == 0 {
+		return nil
+	}
+
+	var requests []*docs.Request
+	if text != "" {
+		requests = append(requests, &docs.Request{
+			InsertText: &docs.InsertTextRequest{
+				Location: &docs.Location{SegmentId: segmentID, Index: index},
+				Text:     text,
+			},
+		})
+	}
+
+	// Inserted paragraphs take the style of the paragraph they were inserted
+	// into, so every paragraph gets the style of its template paragraph
+	if len(content.paragraphs) > 0 {
+		requests = append(requests, &docs.Request{
+			DeleteParagraphBullets: &docs.DeleteParagraphBulletsRequest{
+				Range: &docs.Range{SegmentId: segmentID, StartIndex: index, EndIndex: index + content.length},
+			},
+		})
+	}
+	for _, paragraph := range content.paragraphs {
+		style := &docs.ParagraphStyle{NamedStyleType: "NORMAL_TEXT"}
+		if paragraph.style != nil && paragraph.style.NamedStyleType != "" {
+			style = &docs.ParagraphStyle{NamedStyleType: paragraph.style.NamedStyleType, Alignment: paragraph.style.Alignment}
+		}
+		paragraphRange := &docs.Range{SegmentId: segmentID, StartIndex: index + paragraph.start, EndIndex: index + paragraph.end}
+		requests = append(requests, &docs.Request{
+			UpdateParagraphStyle: &docs.UpdateParagraphStyleRequest{
+				Range:          paragraphRange,
+				ParagraphStyle: style,
+				Fields:         "namedStyleType,alignment",
+			},
+		})
+		if paragraph.preset != "" {
+			requests = append(requests, &docs.Request{
+				CreateParagraphBullets: &docs.CreateParagraphBulletsRequest{
+					Range:        paragraphRange,
+					BulletPreset: paragraph.preset,
+				},
+			})
+		}
+	}
+
+	for _, run := range content.runs {
+		if run.start >= UTF16Length(text) {
+			continue
+		}
+		style := run.style
+		if style == nil {
+			style = &docs.TextStyle{}
+		}
+		requests = append(requests, &docs.Request{
+			UpdateTextStyle: &docs.UpdateTextStyleRequest{
+				Range:     &docs.Range{SegmentId: segmentID, StartIndex: index + run.start, EndIndex: index + min(run.end, UTF16Length(text))},
+				TextStyle: style,
+				Fields:    "*",
+			},
+		})
+	}
+
+	// Images go in last, from the end, so the offsets above stay valid
+	for i := len(content.images) - 1; i >= 0; i-- {
+		image := content.images[i]
+		requests = append(requests, image.image.request(index+image.offset, segmentID))
+	}
+
+	return requests
+}
+
+// templateImageValue is a value that inserts an image
+type templateImageValue struct {
+	url    string
+	width  float64
+	height float64
+}
+
+func (v templateImageValue) request(index int64, segmentID string) *docs.Request {
+	request := &docs.InsertInlineImageRequest{
+		Location: &docs.Location{SegmentId: segmentID, Index: index},
+		Uri:      v.url,
+	}
+	if v.width > 0 || v.height > 0 {
+		request.ObjectSize = &docs.Size{}
+		if v.width > 0 {
+			request.ObjectSize.Width = &docs.Dimension{Magnitude: v.width, Unit: "PT"}
+		}
+		if v.height > 0 {
+			request.ObjectSize.Height = &docs.Dimension{Magnitude: v.height, Unit: "PT"}
+		}
+	}
+	return &docs.Request{InsertInlineImage: request}
+}
+
+// templateImage reports whether a value is an image: an object with "type"
+// set to "image" and a "url"
+func templateImage(value any) (templateImageValue, bool) {
+	object, ok := value.(map[string]any)
+	if !ok || object["type"] != "image" {
+		return templateImageValue{}, false
+	}
+	url, ok := object["url"].(string)
+	if !ok || url == "" {
+		return templateImageValue{}, false
+	}
+	image := templateImageValue{url: url}
+	image.width, _ = object["width"].(float64)
+	image.height, _ = object["height"].(float64)
+	return image, true
+}
+
+// lookupTemplateValue resolves a tag name against the scopes of the enclosing
+// repeated blocks, innermost first, and then the top-level values. "this" is
+// the innermost item and dotted names look inside objects.
+func lookupTemplateValue(name string, values map[string]any, scopes []any) (any, bool) {
+	if name == "" {
+		return nil, false
+	}
+
+	parts := strings.Split(name, ".")
+	var value any
+	found := false
+
+	if parts[0] == "this" || name == "." {
+		if len(scopes) == 0 {
+			value, found = values, true
+		} else {
+			value, found = scopes[len(scopes)-1], true
+		}
+		if name == "." {
+			return value, true
+		}
+		parts = parts[1:]
+	} else {
+		for i := len(scopes) - 1; i >= -1 && !found; i-- {
+			scope := any(values)
+			if i >= 0 {
+				scope = scopes[i]
+			}
+			if object, ok := scope.(map[string]any); ok {
+				value, found = object[parts[0]]
+			}
+		}
+		parts = parts[1:]
+	}
+
+	for _, part := range parts {
+		if !found {
+			break
+		}
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		value, found = object[part]
+	}
+	return value, found
+}
+
+// templateTruthy reports whether a value keeps an {{#if}} block
+func templateTruthy(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	case float64:
+		return v != 0
+	case []any:
+		return len(v) > 0
+	case map[string]any:
+		return len(v) > 0
+	}
+	return true
+}
+
+// formatTemplateValue returns the text a value is replaced with
+func formatTemplateValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = formatTemplateValue(item)
+		}
+		return strings.Join(parts, ", ")
+	}
+	return fmt.Sprint(value)
+}
+
+// findTemplateTags returns the tags in a text in order
+func findTemplateTags(text string) []templateMatch {
+	var matches []templateMatch
+	for _, loc := range templateTag.FindAllStringSubmatchIndex(text, -1) {
+		match := templateMatch{start: loc[0], end: loc[1]}
+		if loc[2] >= 0 {
+			match.kind = text[loc[2]:loc[3]]
+		}
+		match.name = text[loc[4]:loc[5]]
+		if match.kind == "" && match.name == "" {
+			continue
+		}
+		matches = append(matches, match)
+	}
+	return matches
+}
+
+// blockTag returns the tag of a paragraph that holds nothing but an {{#if}},
+// {{/if}}, {{#each}} or {{/each}} tag
+func blockTag(paragraph *docs.Paragraph) (templateMatch, bool) {
+	text := strings.TrimSpace(ParagraphText(paragraph))
+	matches := findTemplateTags(text)
+	if len(matches) != 1 || matches[0].kind == "" || matches[0].start != 0 || matches[0].end != len(text) {
+		return templateMatch{}, false
+	}
+	return matches[0], true
+}
+
+// findBlockEnd returns the position of the paragraph closing the block opened
+// at position start, skipping nested blocks of the same kind
+func findBlockEnd(elements []*docs.StructuralElement, start int, open templateMatch) (int, error) {
+	closing := "/" + strings.TrimPrefix(open.kind, "#")
+	depth := 0
+	for i := start + 1; i < len(elements); i++ {
+		if elements[i].Paragraph == nil {
+			continue
+		}
+		tag, ok := blockTag(elements[i].Paragraph)
+		if !ok {
+			continue
+		}
+		switch tag.kind {
+		case open.kind:
+			depth++
+		case closing:
+			if depth == 0 {
+				return i, nil
+			}
+			depth--
+		}
+	}
+	return 0, fmt.Errorf("{{%s %s}} is not closed by a {{%s}} paragraph", open.kind, open.name, closing)
+}
+
+// contentEndIndex returns the end index of a list of structural elements
+func contentEndIndex(elements []*docs.StructuralElement) int64 {
+	if len(elements) == 0 {
+		return 0
+	}
+	return elements[len(elements)-1].EndIndex
+}
@@ -0,0 +1,181 @@
+package util
+
+import (
+	"slices"
+	"testing"
+
+	"google.golang.org/api/docs/v1"
+)
+
+func TestCompileTemplate(t *testing.T) {
+	withHeader := func(doc *docs.Document, blocks ...testBlock) *docs.Document {
+		doc.Headers = map[string]docs.Header{"kix.h": {Content: layoutElements(0, blocks...)}}
+		return doc
+	}
+
+	tests := []struct {
+		name       string
+		doc        *docs.Document
+		values     map[string]any
+		want       []string
+		unresolved []string
+	}{
+		{
+			// Edits go from the highest index down, each segment after the
+			// body; the emoji before the first tag takes two indices
+			name: "placeholders",
+			doc: withHeader(
+				testDocument(para("😀 {{name}}, {{greeting}}\n"), para("{{name}}\n")),
+				para("{{name}}\n"),
+			),
+			values: map[string]any{"name": "Ann"},
+			want: []string{
+				"deleteContent 27-35",
+				`insertText 27 "Ann"`,
+				"textStyle 27-30 *",
+				"deleteContent 4-12",
+				`insertText 4 "Ann"`,
+				"textStyle 4-7 *",
+				"deleteContent kix.h:0-8",
+				`insertText kix.h:0 "Ann"`,
+				"textStyle kix.h:0-3 *",
+			},
+			unresolved: []string{"greeting"},
+		},
+		{
+			name:   "inline if removed",
+			doc:    testDocument(para("a{{#if on}}b{{/if}}c\n")),
+			values: map[string]any{"on": false},
+			want:   []string{"deleteContent 2-20"},
+		},
+		{
+			// Only the tags go, the closing one first
+			name:   "inline if kept",
+			doc:    testDocument(para("a{{#if on}}b{{/if}}c\n")),
+			values: map[string]any{"on": true},
+			want: []string{
+				"deleteContent 13-20",
+				"deleteContent 2-12",
+			},
+		},
+		{
+			// The section and its tag paragraphs at 1-32 are replaced with one
+			// paragraph per item, both styled from the same template run
+			name: "repeated section",
+			doc: testDocument(
+				para("{{#each p}}\n"),
+				para("{{this}}\n"),
+				para("{{/each}}\n"),
+				para("end\n"),
+			),
+			values: map[string]any{"p": []any{"a", "b"}},
+			want: []string{
+				"deleteContent 1-32",
+				`insertText 1 "a\nb\n"`,
+				"deleteBullets 1-5",
+				"paragraphStyle 1-3 NORMAL_TEXT",
+				"paragraphStyle 3-5 NORMAL_TEXT",
+				"textStyle 1-5 *",
+			},
+		},
+		{
+			// The table at 3 has a header row and a repeated row 16-60 whose
+			// cell paragraphs start at 18 and 43. The new row goes below it at
+			// 60 with its cell paragraphs at 62 and 64; it is filled before the
+			// template row, and every row from its last cell.
+			name: "repeated row",
+			doc: testDocument(
+				para("x\n"),
+				table(
+					[]string{"Name", "Qty"},
+					[]string{"{{#each items}}{{name}}", "{{qty}}{{/each}}"},
+				),
+				para("{{title}}\n"),
+			),
+			values: map[string]any{
+				"title": "T",
+				"items": []any{
+					map[string]any{"name": "A", "qty": 1.0},
+					map[string]any{"name": "B", "qty": 2.0},
+				},
+			},
+			want: []string{
+				"deleteContent 61-70",
+				`insertText 61 "T"`,
+				"textStyle 61-62 *",
+				"insertRow 3 row 1",
+				`insertText 64 "2"`,
+				"deleteBullets 64-66",
+				"paragraphStyle 64-66 NORMAL_TEXT",
+				"textStyle 64-65 *",
+				`insertText 62 "B"`,
+				"deleteBullets 62-64",
+				"paragraphStyle 62-64 NORMAL_TEXT",
+				"textStyle 62-63 *",
+				"deleteContent 43-59",
+				`insertText 43 "1"`,
+				"deleteBullets 43-45",
+				"paragraphStyle 43-45 NORMAL_TEXT",
+				"textStyle 43-44 *",
+				"deleteContent 18-41",
+				`insertText 18 "A"`,
+				"deleteBullets 18-20",
+				"paragraphStyle 18-20 NORMAL_TEXT",
+				"textStyle 18-19 *",
+			},
+		},
+		{
+			name: "one empty row",
+			doc: testDocument(
+				para("x\n"),
+				table([]string{"H"}, []string{"{{#each a}}{{x}}{{/each}}"}),
+				para("y\n"),
+			),
+			values: map[string]any{"a": []any{}},
+			want:   []string{"deleteRow 3 row 1"},
+		},
+		{
+			// A table cannot lose all of its rows, so the table at 3-61 goes
+			name: "every row empty",
+			doc: testDocument(
+				para("x\n"),
+				table([]string{"{{#each a}}{{x}}{{/each}}"}, []string{"{{#each b}}{{x}}{{/each}}"}),
+				para("y\n"),
+			),
+			values:     map[string]any{"a": []any{}},
+			want:       []string{"deleteContent 3-61"},
+			unresolved: []string{"b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batch, err := CompileTemplate(tt.doc, tt.values)
+			if err != nil {
+				t.Fatal(err)
+			}
+			compareRequests(t, batch.Requests, tt.want)
+			if !slices.Equal(batch.Unresolved, tt.unresolved) {
+				t.Errorf("Unresolved = %v, want %v", batch.Unresolved, tt.unresolved)
+			}
+		})
+	}
+}
+
+func TestCompileTemplateErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		doc    *docs.Document
+		values map[string]any
+	}{
+		{"unclosed if", testDocument(para("{{#if a}}b\n")), nil},
+		{"each in paragraph", testDocument(para("a {{#each b}}\n")), nil},
+		{"each of a string", testDocument(para("{{#each a}}\n"), para("{{/each}}\n"), para("\n")), map[string]any{"a": "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := CompileTemplate(tt.doc, tt.values); err == nil {
+				t.Error("CompileTemplate succeeded, want an error")
+			}
+		})
+	}
+}